@@ -0,0 +1,60 @@
+package butler
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ResolveAlias returns the module of the alias. Overrides of the guild take precedence over the global aliases.
+func (c DocsConfig) ResolveAlias(guildID *snowflake.ID, alias string) (string, bool) {
+	if guildID != nil {
+		if module, ok := c.GuildAliases[guildID.String()][alias]; ok {
+			return module, true
+		}
+	}
+	module, ok := c.Aliases[alias]
+	return module, ok
+}
+
+// ResolvedAliases returns all aliases with the overrides of the guild applied.
+func (c DocsConfig) ResolvedAliases(guildID *snowflake.ID) map[string]string {
+	aliases := make(map[string]string, len(c.Aliases))
+	for alias, module := range c.Aliases {
+		aliases[alias] = module
+	}
+	if guildID != nil {
+		for alias, module := range c.GuildAliases[guildID.String()] {
+			aliases[alias] = module
+		}
+	}
+	return aliases
+}
+
+// RemoveAlias removes the global alias and all its overrides.
+func (c *DocsConfig) RemoveAlias(alias string) {
+	delete(c.Aliases, alias)
+	for guildID, overrides := range c.GuildAliases {
+		delete(overrides, alias)
+		if len(overrides) == 0 {
+			delete(c.GuildAliases, guildID)
+		}
+	}
+}
+
+// SetAliasOverride points the alias to another module in the guild only. An empty module removes the override.
+func (c *DocsConfig) SetAliasOverride(guildID snowflake.ID, alias string, module string) {
+	key := guildID.String()
+	if module == "" {
+		delete(c.GuildAliases[key], alias)
+		if len(c.GuildAliases[key]) == 0 {
+			delete(c.GuildAliases, key)
+		}
+		return
+	}
+	if c.GuildAliases == nil {
+		c.GuildAliases = map[string]map[string]string{}
+	}
+	if c.GuildAliases[key] == nil {
+		c.GuildAliases[key] = map[string]string{}
+	}
+	c.GuildAliases[key][alias] = module
+}
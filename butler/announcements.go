@@ -0,0 +1,40 @@
+package butler
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// AnnouncementWebhook is the webhook used to post announcements in a channel.
+type AnnouncementWebhook struct {
+	WebhookID    snowflake.ID `json:"webhook_id" yaml:"webhook_id" toml:"webhook_id"`
+	WebhookToken string       `json:"webhook_token" yaml:"webhook_token" toml:"webhook_token"`
+}
+
+// AnnouncementWebhook returns the webhook client to post announcements in the given channel and creates a new webhook
+// if the channel has none yet.
+func (b *Butler) AnnouncementWebhook(channelID snowflake.ID) (webhook.Client, error) {
+	b.configMu.RLock()
+	cfg, ok := b.Config.AnnouncementWebhooks[channelID.String()]
+	b.configMu.RUnlock()
+	if ok {
+		return webhook.New(cfg.WebhookID, cfg.WebhookToken), nil
+	}
+	incomingWebhook, err := b.Client.Rest().CreateWebhook(channelID, discord.WebhookCreate{Name: "Announcements"})
+	if err != nil {
+		return nil, err
+	}
+	if err = b.UpdateConfig(func(cfg *Config) {
+		if cfg.AnnouncementWebhooks == nil {
+			cfg.AnnouncementWebhooks = map[string]AnnouncementWebhook{}
+		}
+		cfg.AnnouncementWebhooks[channelID.String()] = AnnouncementWebhook{
+			WebhookID:    incomingWebhook.ID(),
+			WebhookToken: incomingWebhook.Token,
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return webhook.New(incomingWebhook.ID(), incomingWebhook.Token), nil
+}
@@ -0,0 +1,105 @@
+package butler
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// autoDeferAfter is how long a command handler may take before its interaction is deferred, leaving some headroom to
+// Discord's 3 second limit.
+const autoDeferAfter = 2500 * time.Millisecond
+
+var errModalAfterDefer = errors.New("can't respond with a modal after the interaction was deferred")
+
+// autoDefer is a safety net for slow command handlers. It defers the interaction if the handler didn't respond in time
+// and turns later responses of the handler into edits of the deferred response. Ephemeral responses replace it with a
+// follow-up instead, as the deferred response is public.
+type autoDefer struct {
+	mu             sync.Mutex
+	responded      bool
+	deferred       bool
+	filled         bool
+	timer          *time.Timer
+	respond        events.InteractionResponderFunc
+	edit           events.InteractionResponderFunc
+	followup       events.InteractionResponderFunc
+	deleteDeferred func() error
+}
+
+// withAutoDefer replaces the responder of the event with one guarded by an autoDefer. Follow-ups are ephemeral if the
+// responses of the command are.
+func (b *Butler) withAutoDefer(e *events.ApplicationCommandInteractionCreate, ephemeral bool) *autoDefer {
+	a := &autoDefer{
+		respond:  e.Respond,
+		edit:     common.DeferredResponder(e.Client(), e, ephemeral),
+		followup: common.FollowupResponder(e.Client(), e, ephemeral),
+		deleteDeferred: func() error {
+			return e.Client().Rest().DeleteInteractionResponse(e.ApplicationID(), e.Token())
+		},
+	}
+	a.timer = time.AfterFunc(autoDeferAfter, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.responded {
+			return
+		}
+		if err := a.respond(discord.InteractionResponseTypeDeferredCreateMessage, nil); err != nil {
+			b.Logger.Error("Failed to automatically defer interaction: ", err)
+			return
+		}
+		b.Logger.Debugf("Automatically deferred slow command /%s", e.Data.CommandName())
+		a.responded = true
+		a.deferred = true
+	})
+	e.Respond = a.Respond
+	return a
+}
+
+func (a *autoDefer) Respond(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.deferred {
+		a.responded = true
+		a.timer.Stop()
+		return a.respond(responseType, data, opts...)
+	}
+	switch responseType {
+	case discord.InteractionResponseTypeDeferredCreateMessage:
+		return nil
+	case discord.InteractionResponseTypeModal:
+		return errModalAfterDefer
+	case discord.InteractionResponseTypeCreateMessage:
+		// the first response fills in the deferred message, later ones are sent as follow-ups
+		if a.filled {
+			return a.followup(responseType, data, opts...)
+		}
+		a.filled = true
+		if messageCreate, ok := data.(discord.MessageCreate); ok && messageCreate.Flags.Has(discord.MessageFlagEphemeral) {
+			// the deferred response is public and edits can't change that, so it is replaced with an ephemeral follow-up
+			if err := a.followup(responseType, data, opts...); err != nil {
+				return err
+			}
+			return a.deleteDeferred()
+		}
+		return a.edit(responseType, data, opts...)
+	}
+	return a.respond(responseType, data, opts...)
+}
+
+// done is called after the handler failed. If it didn't respond yet the user gets the error like from common.RespondErr
+// instead of waiting for a response which never comes.
+func (a *autoDefer) done(err error) {
+	a.mu.Lock()
+	pending := !a.responded || (a.deferred && !a.filled)
+	a.mu.Unlock()
+	if !pending {
+		return
+	}
+	_ = common.RespondErr(a.Respond, err)
+}
@@ -0,0 +1,127 @@
+package butler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// newTestAutoDefer returns an autoDefer which records which responder each response went through.
+func newTestAutoDefer(deferred bool) (*autoDefer, *[]string) {
+	var calls []string
+	record := func(name string) events.InteractionResponderFunc {
+		return func(_ discord.InteractionResponseType, _ discord.InteractionResponseData, _ ...rest.RequestOpt) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	return &autoDefer{
+		responded: deferred,
+		deferred:  deferred,
+		timer:     time.NewTimer(time.Hour),
+		respond:   record("respond"),
+		edit:      record("edit"),
+		followup:  record("followup"),
+		deleteDeferred: func() error {
+			calls = append(calls, "delete")
+			return nil
+		},
+	}, &calls
+}
+
+func TestAutoDeferRespond(t *testing.T) {
+	tests := []struct {
+		name      string
+		deferred  bool
+		ephemeral bool
+		responses []discord.InteractionResponseType
+		want      []string
+		wantErr   error
+	}{
+		{
+			name:      "in time",
+			responses: []discord.InteractionResponseType{discord.InteractionResponseTypeCreateMessage},
+			want:      []string{"respond"},
+		},
+		{
+			name:      "handler defers in time",
+			responses: []discord.InteractionResponseType{discord.InteractionResponseTypeDeferredCreateMessage, discord.InteractionResponseTypeCreateMessage},
+			want:      []string{"respond", "respond"},
+		},
+		{
+			name:      "auto deferred then multiple responses",
+			deferred:  true,
+			responses: []discord.InteractionResponseType{discord.InteractionResponseTypeCreateMessage, discord.InteractionResponseTypeCreateMessage, discord.InteractionResponseTypeCreateMessage},
+			want:      []string{"edit", "followup", "followup"},
+		},
+		{
+			name:      "ephemeral responses after auto defer",
+			deferred:  true,
+			ephemeral: true,
+			responses: []discord.InteractionResponseType{discord.InteractionResponseTypeCreateMessage, discord.InteractionResponseTypeCreateMessage},
+			want:      []string{"followup", "delete", "followup"},
+		},
+		{
+			name:      "handler defers after auto defer",
+			deferred:  true,
+			responses: []discord.InteractionResponseType{discord.InteractionResponseTypeDeferredCreateMessage, discord.InteractionResponseTypeCreateMessage, discord.InteractionResponseTypeCreateMessage},
+			want:      []string{"edit", "followup"},
+		},
+		{
+			name:      "modal after auto defer",
+			deferred:  true,
+			responses: []discord.InteractionResponseType{discord.InteractionResponseTypeModal},
+			wantErr:   errModalAfterDefer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, calls := newTestAutoDefer(tt.deferred)
+			message := discord.MessageCreate{Content: "done"}
+			if tt.ephemeral {
+				message.Flags = discord.MessageFlagEphemeral
+			}
+			var err error
+			for _, responseType := range tt.responses {
+				if err = a.Respond(responseType, message); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Respond() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*calls, tt.want) {
+				t.Errorf("responders = %q, want %q", *calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoDeferDone(t *testing.T) {
+	tests := []struct {
+		name     string
+		deferred bool
+		filled   bool
+		want     []string
+	}{
+		{name: "no response yet", want: []string{"respond"}},
+		// errors are ephemeral
+		{name: "deferred without response", deferred: true, want: []string{"followup", "delete"}},
+		{name: "deferred and filled", deferred: true, filled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, calls := newTestAutoDefer(tt.deferred)
+			a.filled = tt.filled
+			a.done(errors.New("handler failed"))
+			if !reflect.DeepEqual(*calls, tt.want) {
+				t.Errorf("responders = %q, want %q", *calls, tt.want)
+			}
+		})
+	}
+}
@@ -5,10 +5,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/disgoorg/disgo"
+	"github.com/disgoorg/disgo-butler/common"
 	"github.com/disgoorg/disgo-butler/db"
+	"github.com/disgoorg/disgo-butler/eventbus"
 	"github.com/disgoorg/disgo-butler/mod_mail"
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/cache"
@@ -17,41 +21,71 @@ import (
 	"github.com/disgoorg/disgo/gateway"
 	"github.com/disgoorg/disgo/httpserver"
 	"github.com/disgoorg/disgo/oauth2"
-	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/log"
 	"github.com/disgoorg/utils/paginator"
 	"github.com/google/go-github/v44/github"
 	"github.com/hhhapz/doc"
-	"github.com/hhhapz/doc/godocs"
+	"golang.org/x/exp/slices"
 )
 
 func New(logger log.Logger, version string, config Config) *Butler {
 	return &Butler{
-		Config:     config,
-		Logger:     logger,
-		Commands:   map[string]Command{},
-		Components: map[string]Component{},
-		Webhooks:   map[string]webhook.Client{},
-		Paginator:  paginator.NewManager(),
-		Version:    version,
+		Config:       config,
+		Logger:       logger,
+		Commands:     map[string]Command{},
+		Components:   map[string]Component{},
+		TextCommands: map[string]TextCommand{},
+		Paginator:    paginator.NewManager(),
+		Events:       eventbus.New(logger),
+		Version:      version,
+		logLevel:     config.LogLevel,
+		startedAt:    time.Now(),
+		restart:      make(chan string, 1),
 	}
 }
 
 type Butler struct {
-	Client       bot.Client
-	OAuth2       oauth2.Client
-	Logger       log.Logger
-	Mux          *http.ServeMux
-	GitHubClient *github.Client
-	Paginator    *paginator.Manager
-	Commands     map[string]Command
-	Components   map[string]Component
-	DocClient    *doc.CachedSearcher
-	ModMail      *mod_mail.ModMail
-	DB           db.DB
-	Config       Config
-	Webhooks     map[string]webhook.Client
-	Version      string
+	Client            bot.Client
+	OAuth2            oauth2.Client
+	Logger            log.Logger
+	Mux               *http.ServeMux
+	GitHubClient      *github.Client
+	GithubCache       GithubCache
+	Paginator         *paginator.Manager
+	Commands          map[string]Command
+	Components        map[string]Component
+	TextCommands      map[string]TextCommand
+	GuildPrefixes     GuildPrefixes
+	ChannelAllowlists ChannelAllowlists
+	DocClient         *doc.CachedSearcher
+	DocSources        *DocSources
+	Events            *eventbus.Bus
+	DocStatuses       DocStatuses
+	UserDocsPrefs     UserDocsPrefs
+	ContributorSync   ContributorSync
+	Health            Health
+	RateLimits        RateLimits
+	ReleaseDeliveries ReleaseDeliveries
+	ErrorReports      ErrorReports
+	ComponentStates   ComponentStates
+	Jobs              Jobs
+	LogLimiter        common.LogLimiter
+	ModMail           *mod_mail.ModMail
+	DB                db.DB
+	Config            Config
+	ReleaseWebhooks   ReleaseWebhooks
+	Version           string
+
+	// configMu guards the Config, see UpdateConfig
+	configMu    sync.RWMutex
+	commandSync commandSync
+	startedAt   time.Time
+	// restart receives the interaction which requested a restart, see Restart
+	restart chan string
+
+	logLevelMu sync.Mutex
+	logLevel   log.Level
 }
 
 func (b *Butler) SetupRoutes(routes http.Handler) {
@@ -60,52 +94,114 @@ func (b *Butler) SetupRoutes(routes http.Handler) {
 }
 
 func (b *Butler) SetupBot() {
-	b.ModMail = mod_mail.New(b.Config.ModMail)
+	var (
+		token              string
+		secret             string
+		interactions       InteractionsConfig
+		githubEnterprise   GithubEnterpriseConfig
+		sourceConfigs      []DocSourceConfig
+		modMailConfig      mod_mail.Config
+		logSuppression     time.Duration
+		githubCacheSeconds int
+	)
+	b.ReadConfig(func(cfg Config) {
+		token, secret = cfg.Token, cfg.Secret
+		interactions = cfg.Interactions
+		githubEnterprise = cfg.GithubEnterprise
+		sourceConfigs = slices.Clone(cfg.Docs.Sources)
+		modMailConfig = cfg.ModMail
+		logSuppression = time.Duration(cfg.LogSuppressionSeconds) * time.Second
+		githubCacheSeconds = cfg.GithubCacheSeconds
+	})
+	b.LogLimiter.Window = logSuppression
+	b.ModMail = mod_mail.New(modMailConfig, b.Events, &b.LogLimiter)
+	intents := b.Intents()
+	b.logDisabledFeatures()
 	var err error
-	if b.Client, err = disgo.New(b.Config.Token,
+	if b.Client, err = disgo.New(token,
 		bot.WithGatewayConfigOpts(
-			gateway.WithIntents(gateway.IntentGuildMessages|gateway.IntentDirectMessages|gateway.IntentGuildMessageTyping|gateway.IntentDirectMessageTyping|gateway.IntentMessageContent),
+			gateway.WithIntents(intents),
 			gateway.WithCompress(true),
-			gateway.WithPresence(gateway.MessageDataPresenceUpdate{
-				Activities: []discord.Activity{
-					{
-						Name: "loading...",
-						Type: discord.ActivityTypeGame,
-					},
-				},
-				Status: discord.OnlineStatusDND,
+			gateway.WithPresence(b.startupPresence().PresenceUpdate()),
+		),
+		bot.WithCacheConfigOpts(
+			cache.WithCacheFlags(cache.FlagGuilds, cache.FlagChannels, cache.FlagRoles, cache.FlagMembers),
+			cache.WithMemberCachePolicy(func(member discord.Member) bool {
+				return member.User.ID == b.Client.ID()
 			}),
 		),
-		bot.WithCacheConfigOpts(cache.WithCacheFlags(cache.FlagGuilds)),
 		bot.WithEventListenerFunc(b.OnReady),
 		bot.WithEventListenerFunc(b.OnApplicationCommandInteraction),
 		bot.WithEventListenerFunc(b.OnComponentInteraction),
 		bot.WithEventListenerFunc(b.OnAutocompleteInteraction),
+		bot.WithEventListenerFunc(b.OnGuildMessageCreate),
 		bot.WithEventListeners(b.Paginator),
 		bot.WithEventListeners(b.ModMail),
-		bot.WithHTTPServerConfigOpts(b.Config.Interactions.PublicKey,
+		bot.WithHTTPServerConfigOpts(interactions.PublicKey,
 			httpserver.WithServeMux(b.Mux),
-			httpserver.WithAddress(b.Config.Interactions.Address),
-			httpserver.WithURL(b.Config.Interactions.URL),
+			httpserver.WithAddress(interactions.Address),
+			httpserver.WithURL(interactions.URL),
 		),
+		bot.WithRestClientConfigOpts(rest.WithHTTPClient(&http.Client{
+			Timeout:   20 * time.Second,
+			Transport: b.RateLimits.Transport(http.DefaultTransport),
+		})),
 		bot.WithLogger(b.Logger),
 	); err != nil {
 		b.Logger.Errorf("Failed to start bot: %s", err)
 	}
+	b.checkPrivilegedIntents(intents)
 
-	b.OAuth2 = oauth2.New(b.Client.ApplicationID(), b.Config.Secret)
+	for guildID, guild := range b.ModMail.Guilds() {
+		if err = guild.ValidateChannel(b.Client); err != nil {
+			b.Logger.Errorf("Invalid mod mail channel for guild %s: %s", guildID, err)
+		}
+	}
 
-	b.GitHubClient = github.NewClient(b.Client.Rest().HTTPClient())
-	b.DocClient = doc.WithCache(doc.New(b.Client.Rest().HTTPClient(), godocs.Parser))
+	b.OAuth2 = oauth2.New(b.Client.ApplicationID(), secret)
+
+	b.GithubCache.TTL = time.Duration(githubCacheSeconds) * time.Second
+	// the rest client is shared with Discord, only GitHub requests go through the cache
+	githubHTTPClient := *b.Client.Rest().HTTPClient()
+	githubHTTPClient.Transport = b.GithubCache.Transport(githubHTTPClient.Transport)
+	if b.GitHubClient, err = newGithubClient(&githubHTTPClient, githubEnterprise); err != nil {
+		b.Logger.Fatalf("Failed to setup GitHub client: %s", err)
+	}
+	if b.DocSources, err = NewDocSources(b.Client.Rest().HTTPClient(), sourceConfigs); err != nil {
+		b.Logger.Warnf("Failed to setup doc sources: %s", err)
+	}
+	b.DocClient = doc.WithCache(b.DocSources)
+	if loaded, err := b.LoadDocCache(); err != nil {
+		b.Logger.Warnf("Failed to load doc cache: %s", err)
+	} else if loaded > 0 {
+		b.Logger.Infof("Loaded %d go modules from the doc cache", loaded)
+	}
+	if loaded, err := b.LoadComponentStates(); err != nil {
+		b.Logger.Warnf("Failed to load component states: %s", err)
+	} else if loaded > 0 {
+		b.Logger.Infof("Loaded %d component states", loaded)
+	}
 	b.Logger.Info("Loading go modules aliases...")
-	for _, module := range b.Config.Docs.Aliases {
-		_, _ = b.DocClient.Search(context.TODO(), module)
+	var failed int
+	aliases := b.Aliases(nil)
+	for alias, module := range aliases {
+		if err = b.WarmAlias(alias, module); err != nil {
+			b.Logger.Warnf("Failed to load module %s for alias %s: %s", module, alias, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		b.Logger.Warnf("Failed to load %d/%d go modules aliases", failed, len(aliases))
 	}
 }
 
 func (b *Butler) SetupDB(shouldSyncDBTables bool) {
+	var dbConfig db.Config
+	b.ReadConfig(func(cfg Config) {
+		dbConfig = cfg.Database
+	})
 	var err error
-	if b.DB, err = db.SetupDatabase(shouldSyncDBTables, b.Config.Database); err != nil {
+	if b.DB, err = db.SetupDatabase(shouldSyncDBTables, dbConfig); err != nil {
 		b.Logger.Fatalf("Failed to setup database: %s", err)
 	}
 }
@@ -118,33 +214,51 @@ func (b *Butler) StartAndBlock() {
 		b.Logger.Errorf("Failed to start http server: %s", err)
 	}
 
+	b.Health.OnChange(func(HealthStatus) {
+		b.updatePresence()
+	})
+	b.RegisterJobs()
+	b.Jobs.Start(b.Logger, &b.LogLimiter)
+
+	var restartInteraction string
 	defer func() {
 		b.Logger.Info("Shutting down...")
+		// wait for running jobs before closing the clients they use
+		b.Jobs.Stop()
 		b.Client.Close(context.TODO())
 		b.DB.Close()
-		b.Config.ModMail.Threads = b.ModMail.Close()
-		if err := SaveConfig(b.Config); err != nil {
+		if err := b.SaveDocCache(); err != nil {
+			b.Logger.Errorf("Failed to save doc cache: %s", err)
+		}
+		if err := b.SaveComponentStates(); err != nil {
+			b.Logger.Errorf("Failed to save component states: %s", err)
+		}
+		modMailState := b.ModMail.Close()
+		if err := b.UpdateConfig(func(cfg *Config) {
+			cfg.ModMail = cfg.ModMail.WithState(modMailState)
+		}); err != nil {
 			b.Logger.Errorf("Failed to save config: %s", err)
+			if restartInteraction != "" {
+				b.Logger.Error("Not restarting as changes to the config would be lost")
+			}
+			return
+		}
+		if restartInteraction != "" {
+			b.exec(restartInteraction)
 		}
 	}()
 
 	b.Logger.Info("Client is running. Press CTRL-C to exit.")
 	s := make(chan os.Signal, 1)
 	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-s
+	select {
+	case <-s:
+	case restartInteraction = <-b.restart:
+	}
 }
 
 func (b *Butler) OnReady(_ *events.Ready) {
 	b.Logger.Infof("Butler ready")
-	if err := b.Client.SetPresence(context.TODO(), gateway.MessageDataPresenceUpdate{
-		Activities: []discord.Activity{
-			{
-				Name: "you in DMs",
-				Type: discord.ActivityTypeListening,
-			},
-		},
-		Status: discord.OnlineStatusOnline,
-	}); err != nil {
-		b.Logger.Errorf("Failed to set presence: %s", err)
-	}
+	b.updatePresence()
+	b.finishRestart()
 }
@@ -0,0 +1,114 @@
+package butler
+
+import (
+	"context"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/snowflake/v2"
+	"golang.org/x/exp/slices"
+)
+
+// ChannelAllowlistExempt are the features which can't be limited to channels, so guild admins can't lock themselves out.
+var ChannelAllowlistExempt = []string{"config", "admin"}
+
+// ChannelAllowlists caches the channels the features of the bot are limited to per guild. Features are the names of
+// the commands.
+type ChannelAllowlists struct {
+	cache dbCache[map[string][]snowflake.ID]
+}
+
+// GuildChannelAllowlists returns the allowed channels of all features limited in the guild.
+func (b *Butler) GuildChannelAllowlists(guildID snowflake.ID) (map[string][]snowflake.ID, error) {
+	allowlists, err := b.guildChannelAllowlists(guildID)
+	if err != nil {
+		return nil, err
+	}
+	return cloneAllowlists(allowlists), nil
+}
+
+// guildChannelAllowlists returns the cached allowlists of the guild or loads them. They must not be modified.
+func (b *Butler) guildChannelAllowlists(guildID snowflake.ID) (map[string][]snowflake.ID, error) {
+	return b.ChannelAllowlists.cache.get(guildID, func(ctx context.Context) (map[string][]snowflake.ID, error) {
+		rows, err := b.DB.GetChannelAllowlists(ctx, guildID)
+		if err != nil {
+			return nil, err
+		}
+		allowlists := map[string][]snowflake.ID{}
+		for _, row := range rows {
+			allowlists[row.Feature] = append(allowlists[row.Feature], row.ChannelID)
+		}
+		return allowlists, nil
+	})
+}
+
+func cloneAllowlists(allowlists map[string][]snowflake.ID) map[string][]snowflake.ID {
+	cloned := make(map[string][]snowflake.ID, len(allowlists))
+	for feature, channelIDs := range allowlists {
+		cloned[feature] = slices.Clone(channelIDs)
+	}
+	return cloned
+}
+
+// AllowChannel adds the channel to the allowlist of the feature in the guild.
+func (b *Butler) AllowChannel(guildID snowflake.ID, feature string, channelID snowflake.ID) error {
+	if slices.Contains(ChannelAllowlistExempt, feature) {
+		return common.NewUserErrorf("`%s` can't be limited to channels", feature)
+	}
+	if _, ok := b.Commands[feature]; !ok {
+		return common.NewUserErrorf("`%s` is not a command", feature)
+	}
+	if err := b.DB.AddChannelAllowlist(guildID, feature, channelID); err != nil {
+		return err
+	}
+	b.ChannelAllowlists.cache.update(guildID, func(allowlists map[string][]snowflake.ID) map[string][]snowflake.ID {
+		allowlists = cloneAllowlists(allowlists)
+		if !slices.Contains(allowlists[feature], channelID) {
+			allowlists[feature] = append(allowlists[feature], channelID)
+		}
+		return allowlists
+	})
+	return nil
+}
+
+// DisallowChannel removes the channel from the allowlist of the feature in the guild. The feature can be used anywhere
+// again once its last channel is removed.
+func (b *Butler) DisallowChannel(guildID snowflake.ID, feature string, channelID snowflake.ID) (bool, error) {
+	removed, err := b.DB.RemoveChannelAllowlist(guildID, feature, channelID)
+	if err != nil {
+		return false, err
+	}
+	b.ChannelAllowlists.cache.update(guildID, func(allowlists map[string][]snowflake.ID) map[string][]snowflake.ID {
+		allowlists = cloneAllowlists(allowlists)
+		if i := slices.Index(allowlists[feature], channelID); i >= 0 {
+			allowlists[feature] = slices.Delete(allowlists[feature], i, i+1)
+		}
+		if len(allowlists[feature]) == 0 {
+			delete(allowlists, feature)
+		}
+		return allowlists
+	})
+	return removed, nil
+}
+
+// ChannelAllowed returns whether the feature can be used in the channel. Threads and channels are also allowed when
+// their parent channel or category is. If not allowed, the channels the feature is limited to are returned.
+func (b *Butler) ChannelAllowed(guildID snowflake.ID, feature string, channelID snowflake.ID) (bool, []snowflake.ID, error) {
+	allowlists, err := b.guildChannelAllowlists(guildID)
+	if err != nil {
+		return false, nil, err
+	}
+	allowed := slices.Clone(allowlists[feature])
+	if len(allowed) == 0 || slices.Contains(allowed, channelID) {
+		return true, nil, nil
+	}
+	if channel, ok := b.Client.Caches().Channels().GetGuildChannel(channelID); ok && channel.ParentID() != nil {
+		if slices.Contains(allowed, *channel.ParentID()) {
+			return true, nil, nil
+		}
+		// threads in channels of an allowed category
+		if parent, ok := b.Client.Caches().Channels().GetGuildChannel(*channel.ParentID()); ok && parent.ParentID() != nil && slices.Contains(allowed, *parent.ParentID()) {
+			return true, nil, nil
+		}
+	}
+	return false, allowed, nil
+}
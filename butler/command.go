@@ -1,27 +1,41 @@
 package butler
 
 import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/common"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 func (b *Butler) SetupCommands(shouldSyncCommands bool, commands ...Command) {
-	commandCreates := make([]discord.ApplicationCommandCreate, len(commands))
-	for i, command := range commands {
-		commandCreates[i] = command.Create
+	guildIDs := map[snowflake.ID]struct{}{}
+	for _, command := range commands {
 		b.Commands[command.Create.Name()] = command
+		for _, guildID := range command.GuildIDs {
+			guildIDs[guildID] = struct{}{}
+		}
 	}
 
 	if shouldSyncCommands {
 		b.Client.Logger().Info("Syncing commands...")
-		var err error
-		if b.Config.DevMode {
-			_, err = b.Client.Rest().SetGuildCommands(b.Client.ApplicationID(), b.Config.GuildID, commandCreates)
-		} else {
-			_, err = b.Client.Rest().SetGlobalCommands(b.Client.ApplicationID(), commandCreates)
+		if b.DevMode() {
+			guildIDs[b.GuildID()] = struct{}{}
+		} else if _, err := b.SyncCommands(nil); err != nil {
+			b.Client.Logger().Error("Failed to set global commands: ", err)
 		}
-		if err != nil {
-			b.Client.Logger().Error("Failed to set commands: ", err)
+
+		for guildID := range guildIDs {
+			if _, err := b.Client.Rest().GetGuild(guildID, false); err != nil {
+				b.Client.Logger().Errorf("Failed to get guild %s for guild commands, skipping: %s", guildID, err)
+				continue
+			}
+			if _, err := b.SyncCommands(&guildID); err != nil {
+				b.Client.Logger().Errorf("Failed to set guild commands for guild %s: %s", guildID, err)
+			}
 		}
 	}
 }
@@ -38,8 +52,31 @@ func (b *Butler) OnApplicationCommandInteraction(e *events.ApplicationCommandInt
 			}
 		}
 		if handler, ok := command.CommandHandlers[path]; ok {
-			if err := handler(b, e); err != nil {
-				b.Client.Logger().Error("Error handling command: ", err)
+			if message := command.contextError(e); message != "" {
+				if err := common.RespondErrMessage(e.Respond, message); err != nil {
+					b.Client.Logger().Error("Error responding to command used in the wrong context: ", err)
+				}
+				return
+			}
+			if !b.checkChannelAllowed(e) {
+				return
+			}
+			ephemeral := command.ephemeral(e)
+			if ephemeral {
+				e.Respond = common.EphemeralResponder(e.Respond)
+			}
+			autoDefer := b.withAutoDefer(e, ephemeral)
+			err := b.runHandler(handler, e)
+			if err == nil {
+				return
+			}
+			b.Client.Logger().Error("Error handling command: ", err)
+			if command.OnError == nil {
+				autoDefer.done(err)
+				return
+			}
+			if err = command.OnError(b, e, err); err != nil {
+				b.Client.Logger().Error("Error handling command error: ", err)
 			}
 			return
 		}
@@ -49,6 +86,43 @@ func (b *Butler) OnApplicationCommandInteraction(e *events.ApplicationCommandInt
 	b.Logger.Warnf("No handler for command with name %s found", e.Data.CommandName())
 }
 
+// checkChannelAllowed responds with an error and returns false if the command is not allowed in the channel it was used
+// in. The command is allowed if the allowlist can't be loaded.
+func (b *Butler) checkChannelAllowed(e *events.ApplicationCommandInteractionCreate) bool {
+	if e.GuildID() == nil {
+		return true
+	}
+	allowed, channelIDs, err := b.ChannelAllowed(*e.GuildID(), e.Data.CommandName(), e.ChannelID())
+	if err != nil {
+		if !IsCachedFailure(err) {
+			b.Logger.Errorf("Failed to get channel allowlist of guild %s: %s", *e.GuildID(), err)
+		}
+		return true
+	}
+	if allowed {
+		return true
+	}
+	mentions := make([]string, len(channelIDs))
+	for i, channelID := range channelIDs {
+		mentions[i] = discord.ChannelMention(channelID)
+	}
+	if err = common.RespondErrMessage(e.Respond, common.Message(common.MessageChannelNotAllowed, strings.Join(mentions, ", "))); err != nil {
+		b.Logger.Error("Error responding to command used in a disallowed channel: ", err)
+	}
+	return false
+}
+
+// runHandler runs the handler and turns a panic into an error, so it is handled like any other error of the command.
+func (b *Butler) runHandler(handler HandleFunc, e *events.ApplicationCommandInteractionCreate) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.Logger.Errorf("recovered from panic in command /%s: %v\n%s", e.Data.CommandName(), r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(b, e)
+}
+
 func (b *Butler) OnAutocompleteInteraction(e *events.AutocompleteInteractionCreate) {
 	if command, ok := b.Commands[e.Data.CommandName]; ok {
 		var path string
@@ -59,6 +133,9 @@ func (b *Butler) OnAutocompleteInteraction(e *events.AutocompleteInteractionCrea
 			path += *e.Data.SubCommandName
 		}
 
+		if b.configuredChoicesAutocomplete(e, path) {
+			return
+		}
 		if handler, ok := command.AutocompleteHandlers[path]; ok {
 			if err := handler(b, e); err != nil {
 				b.Client.Logger().Error("Error handling autocomplete: ", err)
@@ -74,9 +151,43 @@ func (b *Butler) OnAutocompleteInteraction(e *events.AutocompleteInteractionCrea
 type (
 	HandleFunc             func(b *Butler, e *events.ApplicationCommandInteractionCreate) error
 	AutocompleteHandleFunc func(b *Butler, e *events.AutocompleteInteractionCreate) error
-	Command                struct {
+	// ErrorHandleFunc responds to an error returned by a command handler. The handler may have responded already before
+	// failing, in which case the response can only be edited or followed up.
+	ErrorHandleFunc func(b *Butler, e *events.ApplicationCommandInteractionCreate, err error) error
+	Command         struct {
 		Create               discord.ApplicationCommandCreate
+		GuildIDs             []snowflake.ID
 		CommandHandlers      map[string]HandleFunc
 		AutocompleteHandlers map[string]AutocompleteHandleFunc
+		// Contexts limits where the command can be used. Zero allows all contexts and registers the command as declared.
+		Contexts CommandContexts
+		// OnError is called when a handler returns an error or panics. Defaults to responding like common.RespondErr.
+		OnError ErrorHandleFunc
+		// Ephemeral shows the responses only to the user who used the command. Users can choose themselves with the
+		// EphemeralOption if the command has it.
+		Ephemeral bool
 	}
 )
+
+// EphemeralOption lets users choose whether the response of a command is only shown to them, see Command.Ephemeral.
+var EphemeralOption = discord.ApplicationCommandOptionBool{
+	OptionName:  "ephemeral",
+	Description: "Whether the response is only shown to you.",
+}
+
+// ephemeral returns whether the responses to the interaction are only shown to the user. Responses the handler makes
+// ephemeral stay ephemeral either way.
+func (c Command) ephemeral(e *events.ApplicationCommandInteractionCreate) bool {
+	if data, ok := e.Data.(discord.SlashCommandInteractionData); ok {
+		if ephemeral, ok := data.OptBool(EphemeralOption.OptionName); ok {
+			return ephemeral
+		}
+	}
+	return c.Ephemeral
+}
+
+// WithGuildIDs returns a copy of the Command which is only registered in the given guilds instead of globally.
+func (c Command) WithGuildIDs(guildIDs ...snowflake.ID) Command {
+	c.GuildIDs = guildIDs
+	return c
+}
@@ -0,0 +1,112 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+// validateCommandChoices checks the configured option choices. Keys are option paths like "config/aliases/add/module".
+func validateCommandChoices(choices map[string][]string) error {
+	for path, values := range choices {
+		if parts := strings.Split(path, "/"); len(parts) < 2 || len(parts) > 4 {
+			return fmt.Errorf("invalid command_choices path %q: must be command/[group/][subcommand/]option", path)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("command_choices %q has no values", path)
+		}
+		seen := map[string]struct{}{}
+		for _, value := range values {
+			if value == "" || utf8.RuneCountInString(value) > common.MaxAutocompleteChoiceLength {
+				return fmt.Errorf("command_choices %q value %q must be between 1 and %d characters", path, value, common.MaxAutocompleteChoiceLength)
+			}
+			if _, ok := seen[value]; ok {
+				return fmt.Errorf("command_choices %q contains %q more than once", path, value)
+			}
+			seen[value] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// withConfiguredChoices returns a copy of the command with the configured choices applied to its string options.
+// Options with more than 25 choices use autocomplete instead, see configuredChoicesAutocomplete.
+func (b *Butler) withConfiguredChoices(create discord.ApplicationCommandCreate) discord.ApplicationCommandCreate {
+	slashCreate, ok := create.(discord.SlashCommandCreate)
+	if !ok {
+		return create
+	}
+	var hasChoices bool
+	b.ReadConfig(func(cfg Config) {
+		hasChoices = len(cfg.CommandChoices) > 0
+	})
+	if !hasChoices {
+		return create
+	}
+	slashCreate.Options = b.applyChoices(slashCreate.CommandName, slashCreate.Options)
+	return slashCreate
+}
+
+func (b *Butler) applyChoices(path string, options []discord.ApplicationCommandOption) []discord.ApplicationCommandOption {
+	newOptions := make([]discord.ApplicationCommandOption, len(options))
+	for i, option := range options {
+		switch o := option.(type) {
+		case discord.ApplicationCommandOptionSubCommandGroup:
+			subCommands := make([]discord.ApplicationCommandOptionSubCommand, len(o.Options))
+			for ii, subCommand := range o.Options {
+				subCommand.Options = b.applyChoices(path+"/"+o.GroupName+"/"+subCommand.CommandName, subCommand.Options)
+				subCommands[ii] = subCommand
+			}
+			o.Options = subCommands
+			option = o
+		case discord.ApplicationCommandOptionSubCommand:
+			o.Options = b.applyChoices(path+"/"+o.CommandName, o.Options)
+			option = o
+		case discord.ApplicationCommandOptionString:
+			values, ok := b.CommandChoices(path + "/" + o.OptionName)
+			if !ok {
+				break
+			}
+			if len(values) > common.MaxAutocompleteChoices {
+				o.Choices = nil
+				o.Autocomplete = true
+			} else {
+				o.Autocomplete = false
+				o.Choices = make([]discord.ApplicationCommandOptionChoiceString, len(values))
+				for ii, value := range values {
+					o.Choices[ii] = discord.ApplicationCommandOptionChoiceString{Name: value, Value: value}
+				}
+			}
+			option = o
+		}
+		newOptions[i] = option
+	}
+	return newOptions
+}
+
+// configuredChoicesAutocomplete responds to the autocomplete of options with more than 25 configured choices and
+// reports whether it did.
+func (b *Butler) configuredChoicesAutocomplete(e *events.AutocompleteInteractionCreate, path string) bool {
+	option, ok := e.Data.Find(func(option discord.AutocompleteOption) bool {
+		return option.Focused
+	})
+	if !ok {
+		return false
+	}
+	key := e.Data.CommandName
+	if path != "" {
+		key += "/" + path
+	}
+	values, ok := b.CommandChoices(key + "/" + option.Name)
+	if !ok || len(values) <= common.MaxAutocompleteChoices {
+		return false
+	}
+	if err := e.Result(common.AutocompleteChoices(values, e.Data.String(option.Name))); err != nil {
+		b.Client.Logger().Error("Error handling autocomplete: ", err)
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package butler
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+// CommandContexts are the places a command can be used in.
+type CommandContexts int
+
+const (
+	CommandContextGuild CommandContexts = 1 << iota
+	CommandContextDM
+
+	CommandContextAll = CommandContextGuild | CommandContextDM
+)
+
+// Has reports whether the contexts contain the given context.
+func (c CommandContexts) Has(context CommandContexts) bool {
+	return c&context == context
+}
+
+// withContexts sets the DM permission of the command according to its contexts. Commands without contexts are
+// registered as declared.
+func (c Command) withContexts(create discord.ApplicationCommandCreate) discord.ApplicationCommandCreate {
+	if c.Contexts == 0 {
+		return create
+	}
+	dmPermission := c.Contexts.Has(CommandContextDM)
+	switch cmd := create.(type) {
+	case discord.SlashCommandCreate:
+		cmd.DMPermission = dmPermission
+		return cmd
+	case discord.UserCommandCreate:
+		cmd.DMPermission = dmPermission
+		return cmd
+	case discord.MessageCommandCreate:
+		cmd.DMPermission = dmPermission
+		return cmd
+	}
+	return create
+}
+
+// contextError returns why the command can't be used where it was invoked, or an empty string if it can.
+// Discord only hides commands from DMs, so commands limited to DMs are guarded here only.
+func (c Command) contextError(e *events.ApplicationCommandInteractionCreate) string {
+	if c.Contexts == 0 {
+		return ""
+	}
+	if e.GuildID() == nil && !c.Contexts.Has(CommandContextDM) {
+		return "This command can only be used in a server."
+	}
+	if e.GuildID() != nil && !c.Contexts.Has(CommandContextGuild) {
+		return "This command can only be used in DMs."
+	}
+	return ""
+}
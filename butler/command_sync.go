@@ -0,0 +1,124 @@
+package butler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var ErrCommandSyncRunning = errors.New("a command sync is already running")
+
+type CommandSyncResult struct {
+	Added   []string
+	Removed []string
+	// Updated are the existing commands whose definition changed, Discord gives them a new version.
+	Updated []string
+}
+
+func (r CommandSyncResult) String() string {
+	format := func(names []string) string {
+		if len(names) == 0 {
+			return "None"
+		}
+		return "`" + strings.Join(names, "`, `") + "`"
+	}
+	return fmt.Sprintf("**Added:** %s\n**Removed:** %s\n**Updated:** %s", format(r.Added), format(r.Removed), format(r.Updated))
+}
+
+type commandSync struct {
+	mu sync.Mutex
+}
+
+// commandCreates returns the commands which should be registered globally or in the given guild.
+func (b *Butler) commandCreates(guildID *snowflake.ID) []discord.ApplicationCommandCreate {
+	var commandCreates []discord.ApplicationCommandCreate
+	devMode, devGuildID := b.DevMode(), b.GuildID()
+	for _, command := range b.Commands {
+		if len(command.GuildIDs) == 0 {
+			if guildID == nil && !devMode || guildID != nil && devMode && *guildID == devGuildID {
+				commandCreates = append(commandCreates, b.withConfiguredChoices(command.withContexts(command.Create)))
+			}
+			continue
+		}
+		if guildID == nil {
+			continue
+		}
+		for _, id := range command.GuildIDs {
+			if id == *guildID {
+				commandCreates = append(commandCreates, b.withConfiguredChoices(command.withContexts(command.Create)))
+				break
+			}
+		}
+	}
+	return commandCreates
+}
+
+// SyncCommands overwrites the global commands or the commands of the given guild and reports what changed.
+func (b *Butler) SyncCommands(guildID *snowflake.ID) (*CommandSyncResult, error) {
+	if !b.commandSync.mu.TryLock() {
+		return nil, ErrCommandSyncRunning
+	}
+	defer b.commandSync.mu.Unlock()
+
+	commandCreates := b.commandCreates(guildID)
+	if err := validateCommandCreates(commandCreates); err != nil {
+		return nil, err
+	}
+
+	var (
+		existing []discord.ApplicationCommand
+		err      error
+	)
+	if guildID == nil {
+		existing, err = b.Client.Rest().GetGlobalCommands(b.Client.ApplicationID(), false)
+	} else {
+		existing, err = b.Client.Rest().GetGuildCommands(b.Client.ApplicationID(), *guildID, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var synced []discord.ApplicationCommand
+	if guildID == nil {
+		synced, err = b.Client.Rest().SetGlobalCommands(b.Client.ApplicationID(), commandCreates)
+	} else {
+		synced, err = b.Client.Rest().SetGuildCommands(b.Client.ApplicationID(), *guildID, commandCreates)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return syncResult(existing, synced), nil
+}
+
+// syncResult compares the commands before and after a sync. Commands with the same name are only updated if their
+// version changed.
+func syncResult(existing []discord.ApplicationCommand, synced []discord.ApplicationCommand) *CommandSyncResult {
+	existingVersions := map[string]snowflake.ID{}
+	for _, command := range existing {
+		existingVersions[command.Name()] = command.Version()
+	}
+	result := &CommandSyncResult{}
+	for _, command := range synced {
+		version, ok := existingVersions[command.Name()]
+		if !ok {
+			result.Added = append(result.Added, command.Name())
+			continue
+		}
+		delete(existingVersions, command.Name())
+		if version != command.Version() {
+			result.Updated = append(result.Updated, command.Name())
+		}
+	}
+	for name := range existingVersions {
+		result.Removed = append(result.Removed, name)
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Updated)
+	return result
+}
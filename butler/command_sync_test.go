@@ -0,0 +1,40 @@
+package butler
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/json"
+)
+
+func testCommand(t *testing.T, name string, version int) discord.ApplicationCommand {
+	var command discord.SlashCommand
+	data := fmt.Sprintf(`{"id":"1","type":1,"name":%q,"version":"%d"}`, name, version)
+	if err := json.Unmarshal([]byte(data), &command); err != nil {
+		t.Fatalf("failed to decode command: %s", err)
+	}
+	return command
+}
+
+func TestSyncResult(t *testing.T) {
+	existing := []discord.ApplicationCommand{
+		testCommand(t, "docs", 1),
+		testCommand(t, "tag", 1),
+		testCommand(t, "old", 1),
+	}
+	synced := []discord.ApplicationCommand{
+		testCommand(t, "docs", 1),
+		testCommand(t, "tag", 2),
+		testCommand(t, "new", 1),
+	}
+	want := &CommandSyncResult{
+		Added:   []string{"new"},
+		Removed: []string{"old"},
+		Updated: []string{"tag"},
+	}
+	if got := syncResult(existing, synced); !reflect.DeepEqual(got, want) {
+		t.Errorf("syncResult() = %+v, want %+v", got, want)
+	}
+}
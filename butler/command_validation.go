@@ -0,0 +1,148 @@
+package butler
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// Discord's limits of application commands, see
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object
+const (
+	maxCommandNameLength        = 32
+	maxCommandDescriptionLength = 100
+	maxCommandOptions           = 25
+	maxCommandChoices           = 25
+	maxCommandChoiceNameLength  = 100
+	maxCommandLength            = 4000
+)
+
+var commandNameRegex = regexp.MustCompile(`^[-_\p{L}\p{N}\p{Devanagari}\p{Thai}]{1,32}$`)
+
+// CommandValidationError lists all problems of the commands which Discord would reject.
+type CommandValidationError struct {
+	Problems []string
+}
+
+func (e *CommandValidationError) Error() string {
+	return fmt.Sprintf("invalid commands:\n%s", strings.Join(e.Problems, "\n"))
+}
+
+// commandOption is the part of every option type which is validated. Options are decoded from their JSON so every
+// option type is covered.
+type commandOption struct {
+	Type        discord.ApplicationCommandOptionType `json:"type"`
+	Name        string                               `json:"name"`
+	Description string                               `json:"description"`
+	Required    bool                                 `json:"required"`
+	Choices     []struct {
+		Name  string `json:"name"`
+		Value any    `json:"value"`
+	} `json:"choices"`
+	Options []commandOption `json:"options"`
+}
+
+// ValidateCommands checks the commands against Discord's limits like they are validated before syncing them.
+func ValidateCommands(commands ...Command) error {
+	commandCreates := make([]discord.ApplicationCommandCreate, len(commands))
+	for i, command := range commands {
+		commandCreates[i] = command.withContexts(command.Create)
+	}
+	return validateCommandCreates(commandCreates)
+}
+
+// validateCommandCreates checks the commands against Discord's limits and returns a *CommandValidationError with the
+// path of each offending field.
+func validateCommandCreates(commandCreates []discord.ApplicationCommandCreate) error {
+	var problems []string
+	add := func(path string, format string, a ...any) {
+		problems = append(problems, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, a...)))
+	}
+
+	names := map[string]struct{}{}
+	for _, commandCreate := range commandCreates {
+		name := commandCreate.Name()
+		key := fmt.Sprintf("%d:%s", commandCreate.Type(), name)
+		if _, ok := names[key]; ok {
+			add(name, "command is registered more than once")
+		}
+		names[key] = struct{}{}
+
+		slashCreate, ok := commandCreate.(discord.SlashCommandCreate)
+		if !ok {
+			if length := utf8.RuneCountInString(name); length == 0 || length > maxCommandNameLength {
+				add(name, "name must be between 1 and %d characters", maxCommandNameLength)
+			}
+			continue
+		}
+
+		data, err := json.Marshal(slashCreate)
+		if err != nil {
+			add(name, "failed to encode command: %s", err)
+			continue
+		}
+		var command commandOption
+		if err = json.Unmarshal(data, &command); err != nil {
+			add(name, "failed to decode command: %s", err)
+			continue
+		}
+		length := validateCommandOption(name, command, add)
+		if length > maxCommandLength {
+			add(name, "names, descriptions and choices have %d characters combined, the maximum is %d", length, maxCommandLength)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CommandValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateCommandOption checks the option and its sub options and returns the length of their names, descriptions and
+// choices.
+func validateCommandOption(path string, option commandOption, add func(path string, format string, a ...any)) int {
+	if !commandNameRegex.MatchString(option.Name) {
+		add(path, "name %q must be 1-%d letters, numbers, - or _", option.Name, maxCommandNameLength)
+	} else if strings.ToLower(option.Name) != option.Name {
+		add(path, "name %q must be lowercase", option.Name)
+	}
+	if length := utf8.RuneCountInString(option.Description); length == 0 || length > maxCommandDescriptionLength {
+		add(path, "description is %d characters, it must be between 1 and %d", length, maxCommandDescriptionLength)
+	}
+	length := utf8.RuneCountInString(option.Name) + utf8.RuneCountInString(option.Description)
+
+	if len(option.Choices) > maxCommandChoices {
+		add(path, "has %d choices, the maximum is %d", len(option.Choices), maxCommandChoices)
+	}
+	for _, choice := range option.Choices {
+		if choiceLength := utf8.RuneCountInString(choice.Name); choiceLength == 0 || choiceLength > maxCommandChoiceNameLength {
+			add(path, "choice name %q must be between 1 and %d characters", choice.Name, maxCommandChoiceNameLength)
+		}
+		length += utf8.RuneCountInString(choice.Name) + utf8.RuneCountInString(fmt.Sprint(choice.Value))
+	}
+
+	if len(option.Options) > maxCommandOptions {
+		add(path, "has %d options, the maximum is %d", len(option.Options), maxCommandOptions)
+	}
+	names := map[string]struct{}{}
+	var optional bool
+	for _, subOption := range option.Options {
+		subPath := path + "/" + subOption.Name
+		if _, ok := names[subOption.Name]; ok {
+			add(subPath, "option name is used more than once")
+		}
+		names[subOption.Name] = struct{}{}
+		if subOption.Type != discord.ApplicationCommandOptionTypeSubCommand && subOption.Type != discord.ApplicationCommandOptionTypeSubCommandGroup {
+			if subOption.Required && optional {
+				add(subPath, "required options must be listed before optional ones")
+			}
+			optional = optional || !subOption.Required
+		}
+		length += validateCommandOption(subPath, subOption, add)
+	}
+	return length
+}
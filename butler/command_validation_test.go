@@ -0,0 +1,115 @@
+package butler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+func TestValidateCommandCreates(t *testing.T) {
+	choices := make([]discord.ApplicationCommandOptionChoiceString, maxCommandChoices+1)
+	for i := range choices {
+		choices[i] = discord.ApplicationCommandOptionChoiceString{Name: "choice", Value: "choice"}
+	}
+
+	tests := []struct {
+		name           string
+		commandCreates []discord.ApplicationCommandCreate
+		wantProblems   []string
+	}{
+		{
+			name: "valid",
+			commandCreates: []discord.ApplicationCommandCreate{
+				discord.SlashCommandCreate{
+					CommandName: "docs",
+					Description: "Shows documentation",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{OptionName: "query", Description: "The query", Required: true},
+						discord.ApplicationCommandOptionBool{OptionName: "ephemeral", Description: "Only show it to you"},
+					},
+				},
+				discord.MessageCommandCreate{CommandName: "Edit Message"},
+			},
+		},
+		{
+			name: "duplicate command",
+			commandCreates: []discord.ApplicationCommandCreate{
+				discord.SlashCommandCreate{CommandName: "ping", Description: "Pong"},
+				discord.SlashCommandCreate{CommandName: "ping", Description: "Pong"},
+			},
+			wantProblems: []string{"ping: command is registered more than once"},
+		},
+		{
+			name: "invalid names and descriptions",
+			commandCreates: []discord.ApplicationCommandCreate{
+				discord.SlashCommandCreate{
+					CommandName: "Docs",
+					Description: strings.Repeat("a", maxCommandDescriptionLength+1),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{OptionName: "my query", Description: "The query"},
+					},
+				},
+			},
+			wantProblems: []string{
+				`Docs: name "Docs" must be lowercase`,
+				"Docs: description is 101 characters, it must be between 1 and 100",
+				`Docs/my query: name "my query" must be 1-32 letters, numbers, - or _`,
+			},
+		},
+		{
+			name: "required after optional",
+			commandCreates: []discord.ApplicationCommandCreate{
+				discord.SlashCommandCreate{
+					CommandName: "tag",
+					Description: "Shows a tag",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionBool{OptionName: "ephemeral", Description: "Only show it to you"},
+						discord.ApplicationCommandOptionString{OptionName: "name", Description: "The tag", Required: true},
+					},
+				},
+			},
+			wantProblems: []string{"tag/name: required options must be listed before optional ones"},
+		},
+		{
+			name: "too many choices",
+			commandCreates: []discord.ApplicationCommandCreate{
+				discord.SlashCommandCreate{
+					CommandName: "pick",
+					Description: "Picks a choice",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{OptionName: "choice", Description: "The choice", Choices: choices},
+					},
+				},
+			},
+			wantProblems: []string{"pick/choice: has 26 choices, the maximum is 25"},
+		},
+		{
+			name: "empty context menu name",
+			commandCreates: []discord.ApplicationCommandCreate{
+				discord.UserCommandCreate{},
+			},
+			wantProblems: []string{": name must be between 1 and 32 characters"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCommandCreates(tt.commandCreates)
+			var validationErr *CommandValidationError
+			if tt.wantProblems == nil {
+				if err != nil {
+					t.Errorf("validateCommandCreates() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("validateCommandCreates() error = %v, want a *CommandValidationError", err)
+			}
+			if !reflect.DeepEqual(validationErr.Problems, tt.wantProblems) {
+				t.Errorf("problems = %q, want %q", validationErr.Problems, tt.wantProblems)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package butler
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// ComponentStates keeps the state of long-lived components by their custom ID prefix, e.g. "pages:123". It is persisted
+// to Config.ComponentStateFile so the components keep working after a restart.
+type ComponentStates struct {
+	mu     sync.Mutex
+	states map[string]componentState
+}
+
+type componentState struct {
+	Data      json.RawMessage `json:"data"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Set stores the state of the component until the ttl passes.
+func (s *ComponentStates) Set(prefix string, state any, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.states == nil {
+		s.states = map[string]componentState{}
+	}
+	s.states[prefix] = componentState{
+		Data:      data,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+// Get decodes the state of the component into v. It returns false if there is no state or it expired.
+func (s *ComponentStates) Get(prefix string, v any) (bool, error) {
+	s.mu.Lock()
+	state, ok := s.states[prefix]
+	if ok && state.ExpiresAt.Before(time.Now()) {
+		delete(s.states, prefix)
+		ok = false
+	}
+	s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(state.Data, v)
+}
+
+func (s *ComponentStates) Delete(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, prefix)
+}
+
+func (s *ComponentStates) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.states)
+}
+
+// LoadComponentStates restores the component states from the state file and skips expired ones.
+func (b *Butler) LoadComponentStates() (int, error) {
+	path := b.componentStateFile()
+	if path == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	var states map[string]componentState
+	if err = json.Unmarshal(data, &states); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	b.ComponentStates.mu.Lock()
+	defer b.ComponentStates.mu.Unlock()
+	if b.ComponentStates.states == nil {
+		b.ComponentStates.states = map[string]componentState{}
+	}
+	var loaded int
+	for prefix, state := range states {
+		if state.ExpiresAt.Before(now) {
+			continue
+		}
+		if _, ok := b.ComponentStates.states[prefix]; ok {
+			continue
+		}
+		b.ComponentStates.states[prefix] = state
+		loaded++
+	}
+	return loaded, nil
+}
+
+// SaveComponentStates writes the component states which did not expire yet to the state file.
+func (b *Butler) SaveComponentStates() error {
+	path := b.componentStateFile()
+	if path == "" {
+		return nil
+	}
+	now := time.Now()
+	b.ComponentStates.mu.Lock()
+	states := make(map[string]componentState, len(b.ComponentStates.states))
+	for prefix, state := range b.ComponentStates.states {
+		if state.ExpiresAt.After(now) {
+			states[prefix] = state
+		}
+	}
+	b.ComponentStates.mu.Unlock()
+
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+func (b *Butler) componentStateFile() string {
+	var path string
+	b.ReadConfig(func(cfg Config) {
+		path = cfg.ComponentStateFile
+	})
+	return path
+}
@@ -1,89 +1,301 @@
 package butler
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/disgoorg/disgo-butler/common"
 	"github.com/disgoorg/disgo-butler/db"
 	"github.com/disgoorg/disgo-butler/mod_mail"
 	"github.com/disgoorg/log"
 	"github.com/disgoorg/snowflake/v2"
 )
 
+// configPath is the config file which was loaded, SaveConfig writes back to it in the same format.
+var configPath = configPaths[0]
+
 func LoadConfig() (*Config, error) {
-	file, err := os.Open("config.json")
-	if os.IsNotExist(err) {
-		if file, err = os.Create("config.json"); err != nil {
+	for _, path := range configPaths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
 			return nil, err
 		}
-		var data []byte
-		if data, err = json.Marshal(Config{}); err != nil {
+		configPath = path
+		cfg, err := unmarshalConfig(path, data)
+		if err != nil {
 			return nil, err
 		}
-		if _, err = file.Write(data); err != nil {
+		applyEnvOverrides(cfg)
+		cfg.ModMail.MigrateLegacy(cfg.GuildID)
+		if err = validateConfig(*cfg); err != nil {
 			return nil, err
 		}
-		return nil, errors.New("config.json not found, created new one")
-	} else if err != nil {
-		return nil, err
+		return cfg, nil
 	}
 
-	var cfg Config
-	if err = json.NewDecoder(file).Decode(&cfg); err != nil {
+	data, err := marshalConfig(configPath, Config{})
+	if err != nil {
 		return nil, err
 	}
-	return &cfg, nil
+	if err = os.WriteFile(configPath, data, 0644); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("config.json not found, created new one")
 }
 
+// validateConfig checks the parts of the config which would break the bot at runtime.
+func validateConfig(cfg Config) error {
+	if err := validateSecrets(cfg); err != nil {
+		return err
+	}
+	if err := cfg.GithubEnterprise.validate(); err != nil {
+		return err
+	}
+	if err := validateCommandChoices(cfg.CommandChoices); err != nil {
+		return err
+	}
+	return validateIDKeys(cfg)
+}
+
+// validateIDKeys checks the keys of the maps by ID are valid IDs.
+func validateIDKeys(cfg Config) error {
+	if _, err := cfg.ModMail.ParsedGuilds(); err != nil {
+		return err
+	}
+	if _, err := common.ParseIDKeys(cfg.AnnouncementWebhooks); err != nil {
+		return fmt.Errorf("invalid announcement webhook channel: %w", err)
+	}
+	if _, err := common.ParseIDKeys(cfg.Docs.GuildAliases); err != nil {
+		return fmt.Errorf("invalid guild of alias overrides: %w", err)
+	}
+	return nil
+}
+
+// SaveConfig rotates the config backups and atomically replaces the config file.
 func SaveConfig(config Config) error {
-	file, err := os.OpenFile("config.json", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	data, err := marshalConfig(configPath, withoutEnvOverrides(config))
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = file.Sync()
-		_ = file.Close()
-	}()
-	data, err := json.MarshalIndent(config, "", "  ")
+	if err = rotateConfigBackups(config.ConfigBackups); err != nil {
+		return err
+	}
+	return writeFileAtomic(configPath, data)
+}
+
+// ConfigPath returns the path of the loaded config file.
+func ConfigPath() string {
+	return configPath
+}
+
+type ConfigBackup struct {
+	Index   int
+	ModTime time.Time
+}
+
+// ListConfigBackups returns all existing config backups ordered from newest to oldest.
+func ListConfigBackups() ([]ConfigBackup, error) {
+	var backups []ConfigBackup
+	for i := 1; ; i++ {
+		info, err := os.Stat(configBackupPath(i))
+		if os.IsNotExist(err) {
+			return backups, nil
+		} else if err != nil {
+			return nil, err
+		}
+		backups = append(backups, ConfigBackup{
+			Index:   i,
+			ModTime: info.ModTime(),
+		})
+	}
+}
+
+// LoadConfigBackup reads the config backup with the given index. It is validated like the config on startup, so an
+// invalid backup can't replace the running config.
+func LoadConfigBackup(index int) (*Config, error) {
+	data, err := os.ReadFile(configBackupPath(index))
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := unmarshalConfig(configPath, data)
 	if err != nil {
+		return nil, err
+	}
+	applyEnvOverrides(cfg)
+	cfg.ModMail.MigrateLegacy(cfg.GuildID)
+	if err = validateConfig(*cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func configBackupPath(index int) string {
+	return configPath + "." + strconv.Itoa(index)
+}
+
+func rotateConfigBackups(count int) error {
+	if count <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err = os.Remove(configBackupPath(count)); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	_, err = file.Write(data)
-	return err
+	for i := count - 1; i > 0; i-- {
+		if err = os.Rename(configBackupPath(i), configBackupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return writeFileAtomic(configBackupPath(1), data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
 }
 
 type (
 	Config struct {
-		DevMode  bool         `json:"dev_mode"`
-		GuildID  snowflake.ID `json:"guild_id"`
-		LogLevel log.Level    `json:"log_level"`
-		Token    string       `json:"token"`
-		Secret   string       `json:"secret"`
-		BaseURL  string       `json:"base_url"`
-
-		Docs                DocsConfig                     `json:"docs"`
-		Database            db.Config                      `json:"database"`
-		GithubWebhookSecret string                         `json:"github_webhook_secret"`
-		GithubReleases      map[string]GithubReleaseConfig `json:"github_releases"`
-		Interactions        InteractionsConfig             `json:"interactions"`
-		ContributorRepos    map[string]snowflake.ID        `json:"contributor_repos"`
-		ModMail             mod_mail.Config                `json:"mod_mail"`
+		DevMode  bool           `json:"dev_mode" yaml:"dev_mode" toml:"dev_mode"`
+		GuildID  snowflake.ID   `json:"guild_id" yaml:"guild_id" toml:"guild_id"`
+		OwnerIDs []snowflake.ID `json:"owner_ids" yaml:"owner_ids" toml:"owner_ids"`
+		LogLevel log.Level      `json:"log_level" yaml:"log_level" toml:"log_level"`
+		Token    string         `json:"token" yaml:"token" toml:"token"`
+		Secret   string         `json:"secret" yaml:"secret" toml:"secret"`
+		BaseURL  string         `json:"base_url" yaml:"base_url" toml:"base_url"`
+		Timezone string         `json:"timezone" yaml:"timezone" toml:"timezone"`
+
+		ConfigBackups int             `json:"config_backups" yaml:"config_backups" toml:"config_backups"`
+		Presence      *PresenceConfig `json:"presence,omitempty" yaml:"presence,omitempty" toml:"presence,omitempty"`
+		// DegradedPresence is shown while the Discord API or the database is unhealthy.
+		DegradedPresence *PresenceConfig `json:"degraded_presence,omitempty" yaml:"degraded_presence,omitempty" toml:"degraded_presence,omitempty"`
+		// StartupPresence is shown while the bot is starting. Defaults to a "loading..." presence.
+		StartupPresence *PresenceConfig     `json:"startup_presence,omitempty" yaml:"startup_presence,omitempty" toml:"startup_presence,omitempty"`
+		Limits          common.LimitsConfig `json:"limits" yaml:"limits" toml:"limits"`
+		// CommandChoices limits string options to the given values. Keys are option paths like "config/aliases/add/module".
+		// Options with more than 25 values use autocomplete instead of choices.
+		CommandChoices map[string][]string `json:"command_choices,omitempty" yaml:"command_choices,omitempty" toml:"command_choices,omitempty"`
+		// AllowedMentions is the policy of which mentions in messages of the bot ping. Only the roles features are
+		// configured to ping are allowed by default.
+		AllowedMentions common.AllowedMentionsConfig `json:"allowed_mentions" yaml:"allowed_mentions" toml:"allowed_mentions"`
+		// FeatureAllowedMentions overrides AllowedMentions for single features, see common.MentionsReleases.
+		FeatureAllowedMentions map[string]common.AllowedMentionsConfig `json:"feature_allowed_mentions,omitempty" yaml:"feature_allowed_mentions,omitempty" toml:"feature_allowed_mentions,omitempty"`
+		// Messages overrides the built-in message templates by their key, see common.DefaultMessages.
+		Messages map[string]string `json:"messages,omitempty" yaml:"messages,omitempty" toml:"messages,omitempty"`
+		// Verbose appends diagnostic details like the IDs of created webhooks to the responses of /config in all servers.
+		Verbose bool `json:"verbose,omitempty" yaml:"verbose,omitempty" toml:"verbose,omitempty"`
+		// VerboseGuildIDs are the servers which enabled the details with /config verbose.
+		VerboseGuildIDs []snowflake.ID `json:"verbose_guild_ids,omitempty" yaml:"verbose_guild_ids,omitempty" toml:"verbose_guild_ids,omitempty"`
+		// ErrorReports lets users report internal errors to the staff.
+		ErrorReports ErrorReportsConfig `json:"error_reports" yaml:"error_reports" toml:"error_reports"`
+		// LogSuppressionSeconds is how long identical errors of mod mail and background jobs are logged only once.
+		// Defaults to 5 minutes, a negative value logs every error.
+		LogSuppressionSeconds int `json:"log_suppression_seconds,omitempty" yaml:"log_suppression_seconds,omitempty" toml:"log_suppression_seconds,omitempty"`
+		// GithubCacheSeconds is how long GitHub lookups are answered from the cache before they are revalidated.
+		// Defaults to 5 minutes, a negative value disables the cache.
+		GithubCacheSeconds int `json:"github_cache_seconds,omitempty" yaml:"github_cache_seconds,omitempty" toml:"github_cache_seconds,omitempty"`
+		// JobIntervalSeconds overrides the intervals of background jobs by their name, set with /admin job-interval.
+		// The contributor sync is still only enabled by ContributorSync.IntervalMinutes.
+		JobIntervalSeconds map[string]int `json:"job_interval_seconds,omitempty" yaml:"job_interval_seconds,omitempty" toml:"job_interval_seconds,omitempty"`
+		// DisableMessageContentIntent runs the bot without the privileged message content intent. Text commands are
+		// disabled then.
+		DisableMessageContentIntent bool `json:"disable_message_content_intent" yaml:"disable_message_content_intent" toml:"disable_message_content_intent"`
+
+		Docs                DocsConfig                     `json:"docs" yaml:"docs" toml:"docs"`
+		Database            db.Config                      `json:"database" yaml:"database" toml:"database"`
+		GithubWebhookSecret string                         `json:"github_webhook_secret" yaml:"github_webhook_secret" toml:"github_webhook_secret"`
+		GithubReleases      map[string]GithubReleaseConfig `json:"github_releases" yaml:"github_releases" toml:"github_releases"`
+		ReleaseDelivery     ReleaseDeliveryConfig          `json:"release_delivery" yaml:"release_delivery" toml:"release_delivery"`
+		GithubEnterprise    GithubEnterpriseConfig         `json:"github_enterprise" yaml:"github_enterprise" toml:"github_enterprise"`
+		Interactions        InteractionsConfig             `json:"interactions" yaml:"interactions" toml:"interactions"`
+		ContributorRepos    map[string]snowflake.ID        `json:"contributor_repos" yaml:"contributor_repos" toml:"contributor_repos"`
+		ContributorSync     ContributorSyncConfig          `json:"contributor_sync" yaml:"contributor_sync" toml:"contributor_sync"`
+		ModMail             mod_mail.Config                `json:"mod_mail" yaml:"mod_mail" toml:"mod_mail"`
+		// ComponentStateFile persists the state of long-lived components like paginated docs across restarts. Empty
+		// disables it.
+		ComponentStateFile string `json:"component_state_file,omitempty" yaml:"component_state_file,omitempty" toml:"component_state_file,omitempty"`
+		// AnnouncementWebhooks are the webhooks /announce posts with by their channel ID.
+		AnnouncementWebhooks map[string]AnnouncementWebhook `json:"announcement_webhooks,omitempty" yaml:"announcement_webhooks,omitempty" toml:"announcement_webhooks,omitempty"`
 	}
 
 	DocsConfig struct {
-		Aliases map[string]string `json:"aliases"`
+		Aliases map[string]string `json:"aliases" yaml:"aliases" toml:"aliases"`
+		// GuildAliases override Aliases in single guilds, see ResolveAlias.
+		GuildAliases map[string]map[string]string `json:"guild_aliases,omitempty" yaml:"guild_aliases,omitempty" toml:"guild_aliases,omitempty"`
+		// StdlibPackages are the standard library packages searched by /docs-find. Defaults to the commonly used ones.
+		StdlibPackages []string `json:"stdlib_packages,omitempty" yaml:"stdlib_packages,omitempty" toml:"stdlib_packages,omitempty"`
+		// CacheFile persists the fetched docs across restarts. Empty disables it.
+		CacheFile string `json:"cache_file,omitempty" yaml:"cache_file,omitempty" toml:"cache_file,omitempty"`
+		// DocSites are docs sites besides pkg.go.dev, godocs.io and the Sources whose links are accepted as module paths,
+		// e.g. "docs.example.com".
+		DocSites []string `json:"doc_sites,omitempty" yaml:"doc_sites,omitempty" toml:"doc_sites,omitempty"`
+		// Sources are documentation sites used instead of the public one for modules with their prefixes.
+		Sources []DocSourceConfig `json:"sources,omitempty" yaml:"sources,omitempty" toml:"sources,omitempty"`
+		// CacheTTLHours is how long persisted docs are used before they are fetched again. Defaults to 24 hours.
+		CacheTTLHours int `json:"cache_ttl_hours,omitempty" yaml:"cache_ttl_hours,omitempty" toml:"cache_ttl_hours,omitempty"`
 	}
 
 	GithubReleaseConfig struct {
-		WebhookID    snowflake.ID `json:"webhook_id"`
-		WebhookToken string       `json:"webhook_token"`
-		PingRole     snowflake.ID `json:"ping_role"`
+		WebhookID    snowflake.ID `json:"webhook_id" yaml:"webhook_id" toml:"webhook_id"`
+		WebhookToken string       `json:"webhook_token" yaml:"webhook_token" toml:"webhook_token"`
+		PingRole     snowflake.ID `json:"ping_role" yaml:"ping_role" toml:"ping_role"`
+		// ThreadID is the thread in the webhook's channel to post in. 0 posts in the channel itself.
+		ThreadID snowflake.ID `json:"thread_id,omitempty" yaml:"thread_id,omitempty" toml:"thread_id,omitempty"`
+		// DiscussionThread creates a thread named after the release tag on each announcement. Ignored when posting in a thread.
+		DiscussionThread bool `json:"discussion_thread,omitempty" yaml:"discussion_thread,omitempty" toml:"discussion_thread,omitempty"`
+		// Digest collects releases and posts them as a single message on a schedule instead of announcing each one.
+		Digest ReleaseDigestConfig `json:"digest" yaml:"digest" toml:"digest"`
+		// Assets lists the downloadable assets of the release in the announcement.
+		Assets ReleaseAssetsConfig `json:"assets" yaml:"assets" toml:"assets"`
+		// Username and AvatarURL replace the name and avatar of the webhook in the announcements. Empty keeps the ones of
+		// the webhook.
+		Username  string `json:"username,omitempty" yaml:"username,omitempty" toml:"username,omitempty"`
+		AvatarURL string `json:"avatar_url,omitempty" yaml:"avatar_url,omitempty" toml:"avatar_url,omitempty"`
+		// Broken is why announcements are paused after the webhook was found to be gone, see ReleaseDeliveryConfig.MarkBroken.
+		Broken string `json:"broken,omitempty" yaml:"broken,omitempty" toml:"broken,omitempty"`
+	}
+
+	ReleaseAssetsConfig struct {
+		// Limit is the maximum amount of assets listed. 0 disables the list.
+		Limit int `json:"limit" yaml:"limit" toml:"limit"`
+		// Order sorts the assets by "name", "size" or "downloads" before they are limited. Defaults to the order of GitHub.
+		Order string `json:"order" yaml:"order" toml:"order"`
 	}
 
 	InteractionsConfig struct {
-		URL       string `json:"url"`
-		Address   string `json:"address"`
-		PublicKey string `json:"public_key"`
+		URL       string `json:"url" yaml:"url" toml:"url"`
+		Address   string `json:"address" yaml:"address" toml:"address"`
+		PublicKey string `json:"public_key" yaml:"public_key" toml:"public_key"`
 	}
 )
@@ -0,0 +1,195 @@
+package butler
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+	"golang.org/x/exp/slices"
+)
+
+// The accessors below guard the Config against concurrent use by commands and background jobs. Setters save the config
+// and return the error of saving it, the change is kept in memory either way.
+
+// UpdateConfig applies the update to the Config and saves it.
+func (b *Butler) UpdateConfig(update func(cfg *Config)) error {
+	b.configMu.Lock()
+	defer b.configMu.Unlock()
+	update(&b.Config)
+	return SaveConfig(b.Config)
+}
+
+// ReadConfig calls read with the Config while no one can change it. read must not keep references to its maps.
+func (b *Butler) ReadConfig(read func(cfg Config)) {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	read(b.Config)
+}
+
+// GuildID returns the ID of the guild the bot manages.
+func (b *Butler) GuildID() snowflake.ID {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return b.Config.GuildID
+}
+
+// DevMode reports whether global commands are only registered in the guild of the bot.
+func (b *Butler) DevMode() bool {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return b.Config.DevMode
+}
+
+// BaseURL returns the public URL the routes of the bot are served at.
+func (b *Butler) BaseURL() string {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return b.Config.BaseURL
+}
+
+// IsOwner reports whether the user is one of the owners of the bot.
+func (b *Butler) IsOwner(userID snowflake.ID) bool {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return slices.Contains(b.Config.OwnerIDs, userID)
+}
+
+// CommandChoices returns a copy of the choices configured for the option at the path, see Config.CommandChoices.
+func (b *Butler) CommandChoices(path string) ([]string, bool) {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	values, ok := b.Config.CommandChoices[path]
+	return slices.Clone(values), ok
+}
+
+// AnnouncementWebhooks returns a copy of the announcement webhooks by their channel ID.
+func (b *Butler) AnnouncementWebhooks() map[string]AnnouncementWebhook {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return copyMap(b.Config.AnnouncementWebhooks)
+}
+
+// Alias returns the module of the alias with the overrides of the guild applied, see DocsConfig.ResolveAlias.
+func (b *Butler) Alias(guildID *snowflake.ID, alias string) (string, bool) {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return b.Config.Docs.ResolveAlias(guildID, alias)
+}
+
+// Aliases returns a copy of all aliases with the overrides of the guild applied. A nil guildID returns the global ones.
+func (b *Butler) Aliases(guildID *snowflake.ID) map[string]string {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return b.Config.Docs.ResolvedAliases(guildID)
+}
+
+// AliasOverrides returns a copy of the aliases overridden in the guild.
+func (b *Butler) AliasOverrides(guildID snowflake.ID) map[string]string {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return copyMap(b.Config.Docs.GuildAliases[guildID.String()])
+}
+
+// SetAlias points the global alias to the module.
+func (b *Butler) SetAlias(alias string, module string) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		if cfg.Docs.Aliases == nil {
+			cfg.Docs.Aliases = map[string]string{}
+		}
+		cfg.Docs.Aliases[alias] = module
+	})
+}
+
+// RemoveAliases removes the global aliases and all their overrides.
+func (b *Butler) RemoveAliases(aliases ...string) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		for _, alias := range aliases {
+			cfg.Docs.RemoveAlias(alias)
+		}
+	})
+}
+
+// SetAliasOverride points the alias to another module in the guild only. An empty module removes the override.
+func (b *Butler) SetAliasOverride(guildID snowflake.ID, alias string, module string) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		cfg.Docs.SetAliasOverride(guildID, alias, module)
+	})
+}
+
+// NormalizeModule returns the canonical module path of the user input, see DocsConfig.NormalizeModule.
+func (b *Butler) NormalizeModule(module string) (string, error) {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return b.Config.Docs.NormalizeModule(module)
+}
+
+// ReleaseConfig returns the release announcement config of the repository.
+func (b *Butler) ReleaseConfig(repo string) (GithubReleaseConfig, bool) {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	cfg, ok := b.Config.GithubReleases[repo]
+	return cfg, ok
+}
+
+// ReleaseConfigs returns a copy of the release announcement configs by their repository.
+func (b *Butler) ReleaseConfigs() map[string]GithubReleaseConfig {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return copyMap(b.Config.GithubReleases)
+}
+
+// SetReleaseConfig adds or replaces the release announcement config of the repository.
+func (b *Butler) SetReleaseConfig(repo string, releaseConfig GithubReleaseConfig) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		if cfg.GithubReleases == nil {
+			cfg.GithubReleases = map[string]GithubReleaseConfig{}
+		}
+		cfg.GithubReleases[repo] = releaseConfig
+	})
+}
+
+// RemoveReleaseConfig removes the release announcement config of the repository.
+func (b *Butler) RemoveReleaseConfig(repo string) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		delete(cfg.GithubReleases, repo)
+	})
+}
+
+// ContributorRepo returns the contributor role of the repository.
+func (b *Butler) ContributorRepo(repo string) (snowflake.ID, bool) {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	roleID, ok := b.Config.ContributorRepos[repo]
+	return roleID, ok
+}
+
+// ContributorRepos returns a copy of the contributor roles by their repository.
+func (b *Butler) ContributorRepos() map[string]snowflake.ID {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	return copyMap(b.Config.ContributorRepos)
+}
+
+// SetContributorRepos maps all repositories to the contributor role.
+func (b *Butler) SetContributorRepos(roleID snowflake.ID, repos ...string) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		if cfg.ContributorRepos == nil {
+			cfg.ContributorRepos = map[string]snowflake.ID{}
+		}
+		for _, repo := range repos {
+			cfg.ContributorRepos[repo] = roleID
+		}
+	})
+}
+
+// RemoveContributorRepo removes the contributor repository.
+func (b *Butler) RemoveContributorRepo(repo string) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		delete(cfg.ContributorRepos, repo)
+	})
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,74 @@
+package butler
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/disgoorg/log"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// TestConfigConcurrentAccess announces while the config is edited, run it with -race to catch unguarded config reads.
+func TestConfigConcurrentAccess(t *testing.T) {
+	path := configPath
+	configPath = filepath.Join(t.TempDir(), "config.json")
+	t.Cleanup(func() {
+		configPath = path
+	})
+
+	const (
+		announcementChannelID snowflake.ID = 817327181659111461
+		releaseWebhookID      snowflake.ID = 817327181659111463
+	)
+	b := New(log.Default(), "test", Config{
+		GuildID:  817327181659111454,
+		OwnerIDs: []snowflake.ID{170939974227591168},
+		GithubReleases: map[string]GithubReleaseConfig{
+			"disgoorg/disgo": {WebhookID: releaseWebhookID, WebhookToken: "token"},
+		},
+		AnnouncementWebhooks: map[string]AnnouncementWebhook{
+			announcementChannelID.String(): {WebhookID: 817327181659111462, WebhookToken: "token"},
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := b.UpdateConfig(func(cfg *Config) {
+				cfg.AnnouncementWebhooks[snowflake.ID(i+1).String()] = AnnouncementWebhook{WebhookID: snowflake.ID(i + 1)}
+				cfg.OwnerIDs = append(cfg.OwnerIDs, snowflake.ID(i+1))
+			}); err != nil {
+				t.Errorf("failed to update config: %s", err)
+			}
+			if err := b.SetReleaseConfig("disgoorg/disgo", GithubReleaseConfig{WebhookID: releaseWebhookID, WebhookToken: "token"}); err != nil {
+				t.Errorf("failed to set release config: %s", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := b.AnnouncementWebhook(announcementChannelID); err != nil {
+				t.Errorf("failed to get announcement webhook: %s", err)
+			}
+			if webhookClient, ok := b.WebhookClient(releaseWebhookID); !ok || webhookClient.ID() != releaseWebhookID {
+				t.Errorf("release webhook %s not found", releaseWebhookID)
+			}
+			for repo, cfg := range b.ReleaseConfigs() {
+				b.ReleaseWebhooks.Client(repo, cfg)
+			}
+			_ = b.AnnouncementWebhooks()
+			_ = b.IsOwner(170939974227591168)
+			_ = b.GuildID()
+		}()
+	}
+	wg.Wait()
+
+	if got := b.ReleaseWebhooks.Len(); got != 1 {
+		t.Errorf("cached release webhooks = %d, want 1", got)
+	}
+	if got := len(b.AnnouncementWebhooks()); got != 21 {
+		t.Errorf("announcement webhooks = %d, want 21", got)
+	}
+}
@@ -0,0 +1,63 @@
+package butler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/disgoorg/disgo-butler/mod_mail"
+)
+
+func TestLoadConfigBackup(t *testing.T) {
+	path := configPath
+	configPath = filepath.Join(t.TempDir(), "config.json")
+	t.Cleanup(func() {
+		configPath = path
+	})
+
+	const secrets = `"token": "token", "secret": "secret", "interactions": {"public_key": "key"}`
+	tests := []struct {
+		name    string
+		backup  string
+		wantErr bool
+	}{
+		{name: "valid", backup: `{` + secrets + `, "mod_mail": {"guilds": {"817327181659111454": {"channel_id": "817327181659111457"}}}}`},
+		{name: "invalid json", backup: `{` + secrets + `, "mod_mail": `, wantErr: true},
+		{name: "missing secrets", backup: `{}`, wantErr: true},
+		{name: "invalid mod mail guild", backup: `{` + secrets + `, "mod_mail": {"guilds": {"general": {}}}}`, wantErr: true},
+		{name: "invalid command choices", backup: `{` + secrets + `, "command_choices": {"docs/query": ["disgo", "disgo"]}}`, wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := i + 1
+			if err := os.WriteFile(configBackupPath(index), []byte(tt.backup), 0644); err != nil {
+				t.Fatalf("failed to write backup: %s", err)
+			}
+			_, err := LoadConfigBackup(index)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfigBackup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModMailWithState(t *testing.T) {
+	restored := mod_mail.Config{
+		Guilds:  map[string]mod_mail.GuildConfig{"817327181659111454": {ChannelID: 817327181659111457}},
+		Threads: []mod_mail.Thread{{ThreadID: 1, ChannelID: 2}},
+	}
+	state := mod_mail.Config{
+		Guilds:   map[string]mod_mail.GuildConfig{"817327181659111454": {ChannelID: 817327181659111458}},
+		Threads:  []mod_mail.Thread{{ThreadID: 3, ChannelID: 4}},
+		Webhooks: []mod_mail.ChannelWebhook{{ChannelID: 5, WebhookID: 6}},
+	}
+
+	got := restored.WithState(state)
+	if !reflect.DeepEqual(got.Guilds, restored.Guilds) {
+		t.Errorf("guilds = %+v, want the restored %+v", got.Guilds, restored.Guilds)
+	}
+	if !reflect.DeepEqual(got.Threads, state.Threads) || !reflect.DeepEqual(got.Webhooks, state.Webhooks) {
+		t.Errorf("threads and webhooks = %+v %+v, want the state %+v %+v", got.Threads, got.Webhooks, state.Threads, state.Webhooks)
+	}
+}
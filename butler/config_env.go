@@ -0,0 +1,70 @@
+package butler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envOverrides are the config fields which can be set via environment variables. Environment variables take precedence
+// over the config file and are never written back to it.
+var envOverrides = []struct {
+	Name  string
+	Field func(cfg *Config) *string
+}{
+	{"BUTLER_TOKEN", func(cfg *Config) *string { return &cfg.Token }},
+	{"BUTLER_SECRET", func(cfg *Config) *string { return &cfg.Secret }},
+	{"BUTLER_GITHUB_WEBHOOK_SECRET", func(cfg *Config) *string { return &cfg.GithubWebhookSecret }},
+	{"BUTLER_INTERACTIONS_PUBLIC_KEY", func(cfg *Config) *string { return &cfg.Interactions.PublicKey }},
+	{"BUTLER_DATABASE_ADDRESS", func(cfg *Config) *string { return &cfg.Database.Address }},
+	{"BUTLER_DATABASE_USER", func(cfg *Config) *string { return &cfg.Database.User }},
+	{"BUTLER_DATABASE_PASSWORD", func(cfg *Config) *string { return &cfg.Database.Password }},
+	{"BUTLER_DATABASE_NAME", func(cfg *Config) *string { return &cfg.Database.Database }},
+}
+
+// fileValues holds the config file values which were replaced by environment variables, keyed by the variable name.
+var fileValues = map[string]string{}
+
+func applyEnvOverrides(cfg *Config) {
+	for _, override := range envOverrides {
+		value, ok := os.LookupEnv(override.Name)
+		if !ok {
+			continue
+		}
+		field := override.Field(cfg)
+		fileValues[override.Name] = *field
+		*field = value
+	}
+}
+
+// withoutEnvOverrides returns the config with the values of the config file instead of the environment variables.
+func withoutEnvOverrides(cfg Config) Config {
+	for _, override := range envOverrides {
+		if value, ok := fileValues[override.Name]; ok {
+			*override.Field(&cfg) = value
+		}
+	}
+	return cfg
+}
+
+func validateSecrets(cfg Config) error {
+	required := []struct {
+		Name  string
+		Env   string
+		Value string
+	}{
+		{"token", "BUTLER_TOKEN", cfg.Token},
+		{"secret", "BUTLER_SECRET", cfg.Secret},
+		{"interactions.public_key", "BUTLER_INTERACTIONS_PUBLIC_KEY", cfg.Interactions.PublicKey},
+	}
+	var missing []string
+	for _, secret := range required {
+		if secret.Value == "" {
+			missing = append(missing, fmt.Sprintf("%s (%s)", secret.Name, secret.Env))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config values, set them in the config file or via environment variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
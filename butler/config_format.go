@@ -0,0 +1,74 @@
+package butler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configPaths are the config files which are looked for in order. The format is picked by the file extension.
+var configPaths = []string{"config.json", "config.yaml", "config.yml", "config.toml"}
+
+type configFormat struct {
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+}
+
+var configFormats = map[string]configFormat{
+	".json": {
+		marshal: func(v any) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		},
+		unmarshal: json.Unmarshal,
+	},
+	".yaml": {
+		marshal:   yaml.Marshal,
+		unmarshal: yaml.Unmarshal,
+	},
+	".yml": {
+		marshal:   yaml.Marshal,
+		unmarshal: yaml.Unmarshal,
+	},
+	".toml": {
+		marshal: func(v any) ([]byte, error) {
+			buf := &bytes.Buffer{}
+			if err := toml.NewEncoder(buf).Encode(v); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		},
+		unmarshal: toml.Unmarshal,
+	},
+}
+
+func formatOf(path string) (configFormat, error) {
+	format, ok := configFormats[filepath.Ext(path)]
+	if !ok {
+		return configFormat{}, fmt.Errorf("unsupported config format: %s", path)
+	}
+	return format, nil
+}
+
+func marshalConfig(path string, config Config) ([]byte, error) {
+	format, err := formatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	return format.marshal(config)
+}
+
+func unmarshalConfig(path string, data []byte) (*Config, error) {
+	format, err := formatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err = format.unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,146 @@
+package butler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/mod_mail"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	openedAt := time.Date(2022, 7, 1, 12, 30, 0, 0, time.UTC)
+	cfg := Config{
+		GuildID:  817327181659111454,
+		OwnerIDs: []snowflake.ID{170939974227591168},
+		Docs: DocsConfig{
+			Aliases: map[string]string{"disgo": "github.com/disgoorg/disgo"},
+			GuildAliases: map[string]map[string]string{
+				"817327181659111454": {"disgo": "github.com/disgoorg/disgo/v2"},
+			},
+		},
+		ContributorRepos: map[string]snowflake.ID{"disgoorg/disgo": 817327181659111455},
+		ModMail: mod_mail.Config{
+			Guilds: map[string]mod_mail.GuildConfig{
+				"817327181659111454": {
+					RoleID:           817327181659111456,
+					ChannelID:        817327181659111457,
+					WebhookID:        817327181659111458,
+					WebhookToken:     "token",
+					ThreadSource:     mod_mail.ThreadSourceForum,
+					AnonymousReplies: true,
+				},
+			},
+			Threads: []mod_mail.Thread{
+				{ThreadID: 817327181659111459, ChannelID: 817327181659111460, GuildID: 817327181659111454},
+			},
+			History: []mod_mail.TicketRecord{
+				{
+					DMChannelID: 817327181659111460,
+					GuildID:     817327181659111454,
+					OpenedAt:    openedAt,
+					Replies:     map[string]int{"170939974227591168": 3},
+				},
+			},
+		},
+		AnnouncementWebhooks: map[string]AnnouncementWebhook{
+			"817327181659111461": {WebhookID: 817327181659111462, WebhookToken: "token"},
+		},
+		JobIntervalSeconds: map[string]int{"contributor-sync": 600},
+	}
+
+	for _, path := range []string{"config.json", "config.yaml", "config.toml"} {
+		t.Run(path, func(t *testing.T) {
+			data, err := marshalConfig(path, cfg)
+			if err != nil {
+				t.Fatalf("failed to save config: %s", err)
+			}
+			loaded, err := unmarshalConfig(path, data)
+			if err != nil {
+				t.Fatalf("failed to load config: %s", err)
+			}
+			// empty lists and maps may come back as nil, so saving again must produce the same file instead
+			resaved, err := marshalConfig(path, *loaded)
+			if err != nil {
+				t.Fatalf("failed to save loaded config: %s", err)
+			}
+			if string(resaved) != string(data) {
+				t.Errorf("saving the loaded config changed it:\n got: %s\nwant: %s", resaved, data)
+			}
+
+			for _, field := range []struct {
+				name      string
+				got, want any
+			}{
+				{"mod_mail.guilds", loaded.ModMail.Guilds, cfg.ModMail.Guilds},
+				{"mod_mail.threads", loaded.ModMail.Threads, cfg.ModMail.Threads},
+				{"mod_mail.history.replies", loaded.ModMail.History[0].Replies, cfg.ModMail.History[0].Replies},
+				{"mod_mail.history.opened_at", loaded.ModMail.History[0].OpenedAt.UTC(), openedAt},
+				{"announcement_webhooks", loaded.AnnouncementWebhooks, cfg.AnnouncementWebhooks},
+				{"docs.guild_aliases", loaded.Docs.GuildAliases, cfg.Docs.GuildAliases},
+				{"contributor_repos", loaded.ContributorRepos, cfg.ContributorRepos},
+				{"job_interval_seconds", loaded.JobIntervalSeconds, cfg.JobIntervalSeconds},
+			} {
+				if !reflect.DeepEqual(field.got, field.want) {
+					t.Errorf("%s = %+v, want %+v", field.name, field.got, field.want)
+				}
+			}
+			if err = validateIDKeys(*loaded); err != nil {
+				t.Errorf("loaded config has invalid ID keys: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidateIDKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "empty", cfg: Config{}},
+		{
+			name: "valid",
+			cfg: Config{
+				ModMail:              mod_mail.Config{Guilds: map[string]mod_mail.GuildConfig{"817327181659111454": {}}},
+				AnnouncementWebhooks: map[string]AnnouncementWebhook{"817327181659111461": {}},
+				Docs:                 DocsConfig{GuildAliases: map[string]map[string]string{"817327181659111454": {}}},
+			},
+		},
+		{
+			name:    "invalid mod mail guild",
+			cfg:     Config{ModMail: mod_mail.Config{Guilds: map[string]mod_mail.GuildConfig{"general": {}}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid announcement channel",
+			cfg:     Config{AnnouncementWebhooks: map[string]AnnouncementWebhook{"#announcements": {}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid alias guild",
+			cfg:     Config{Docs: DocsConfig{GuildAliases: map[string]map[string]string{"": {}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateIDKeys(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateIDKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigLoadIntegerIDKeys(t *testing.T) {
+	// configs saved before the maps by ID were keyed by strings have plain numbers as keys in YAML
+	data := []byte("mod_mail:\n  guilds:\n    817327181659111454:\n      channel_id: 817327181659111457\n")
+	cfg, err := unmarshalConfig("config.yaml", data)
+	if err != nil {
+		t.Fatalf("failed to load config: %s", err)
+	}
+	if got := cfg.ModMail.Guilds["817327181659111454"].ChannelID; got != 817327181659111457 {
+		t.Errorf("channel_id = %s, want 817327181659111457", got)
+	}
+}
@@ -0,0 +1,292 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/eventbus"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/disgo/rest/route"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/google/go-github/v44/github"
+	"golang.org/x/exp/slices"
+)
+
+type ContributorSyncConfig struct {
+	// IntervalMinutes is the amount of minutes between contributor syncs. 0 disables the sync.
+	IntervalMinutes int `json:"interval_minutes" yaml:"interval_minutes" toml:"interval_minutes"`
+	// JitterSeconds is the maximum amount of seconds randomly added to each interval.
+	JitterSeconds int `json:"jitter_seconds" yaml:"jitter_seconds" toml:"jitter_seconds"`
+}
+
+type ContributorSyncStatus struct {
+	LastRun time.Time
+	NextRun time.Time
+	Result  ContributorSyncResult
+	Err     error
+}
+
+type ContributorSyncResult struct {
+	Accounts     int
+	RolesAdded   int
+	RolesRemoved int
+	Errors       int
+}
+
+func (r ContributorSyncResult) String() string {
+	return fmt.Sprintf("checked %d accounts, added %d roles, removed %d roles, %d errors", r.Accounts, r.RolesAdded, r.RolesRemoved, r.Errors)
+}
+
+type ContributorSync struct {
+	mu     sync.Mutex
+	status ContributorSyncStatus
+}
+
+func (s *ContributorSync) Status() ContributorSyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// RepoContributors returns the logins of all contributors of the given repository in the owner/name format.
+func (b *Butler) RepoContributors(ctx context.Context, repo string) ([]string, error) {
+	values := strings.SplitN(repo, "/", 2)
+	if len(values) != 2 {
+		return nil, fmt.Errorf("invalid repository: %s", repo)
+	}
+	var (
+		logins []string
+		opts   = &github.ListContributorsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	)
+	for {
+		contributors, rs, err := b.GitHubClient.Repositories.ListContributors(ctx, values[0], values[1], opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, contributor := range contributors {
+			logins = append(logins, contributor.GetLogin())
+		}
+		if rs.NextPage == 0 {
+			return logins, nil
+		}
+		opts.Page = rs.NextPage
+	}
+}
+
+// contributorSyncJob periodically syncs the contributor roles of all linked accounts. During Discord outages it backs
+// off instead of waiting for the full interval.
+func (b *Butler) contributorSyncJob() Job {
+	var cfg ContributorSyncConfig
+	b.ReadConfig(func(config Config) {
+		cfg = config.ContributorSync
+	})
+	backoff := &common.Backoff{
+		Min: 30 * time.Second,
+	}
+	var outage bool
+	return Job{
+		Name:        "contributor-sync",
+		Interval:    time.Duration(cfg.IntervalMinutes) * time.Minute,
+		MinInterval: time.Minute,
+		NextDelay: func(interval time.Duration, _ error) time.Duration {
+			var delay time.Duration
+			if outage {
+				backoff.Max = interval
+				delay = backoff.Next()
+			} else {
+				delay = interval
+				if cfg.JitterSeconds > 0 {
+					delay += time.Duration(rand.Intn(cfg.JitterSeconds)) * time.Second
+				}
+			}
+			b.ContributorSync.mu.Lock()
+			b.ContributorSync.status.NextRun = time.Now().Add(delay)
+			b.ContributorSync.mu.Unlock()
+			return delay
+		},
+		Run: func(ctx context.Context) error {
+			if outage {
+				// check whether discord is back before doing any GitHub requests
+				if _, err := b.Client.Rest().GetBotApplicationInfo(); common.IsServerError(err) {
+					b.Health.ReportDiscordError(err)
+					return fmt.Errorf("%w: %s", ErrDiscordUnavailable, err)
+				}
+			}
+
+			result, err := b.SyncContributors(ctx)
+			if errors.Is(err, ErrDiscordUnavailable) {
+				if b.Health.ReportDiscordError(err) {
+					b.Logger.Warnf("Discord API seems to be unavailable, pausing contributor sync: %s", err)
+				}
+				outage = true
+				return err
+			}
+			if outage && b.Health.ReportDiscordOK() {
+				b.Logger.Info("Discord API recovered, resuming contributor sync")
+			}
+			outage = false
+			backoff.Reset()
+
+			if err != nil {
+				return fmt.Errorf("failed to sync contributors: %w", err)
+			}
+			b.Logger.Infof("Synced contributors: %s", result)
+			return nil
+		},
+	}
+}
+
+// SyncContributors reconciles the contributor roles of all linked accounts with the configured repositories.
+// Roles granted manually via an override are never removed.
+func (b *Butler) SyncContributors(ctx context.Context) (ContributorSyncResult, error) {
+	result, err := b.syncContributors(ctx)
+
+	b.ContributorSync.mu.Lock()
+	defer b.ContributorSync.mu.Unlock()
+	b.ContributorSync.status.LastRun = time.Now()
+	b.ContributorSync.status.Result = result
+	b.ContributorSync.status.Err = err
+	return result, err
+}
+
+func (b *Butler) syncContributors(ctx context.Context) (ContributorSyncResult, error) {
+	var result ContributorSyncResult
+
+	contributorRepos := b.ContributorRepos()
+	repoContributors := map[string][]string{}
+	for repo := range contributorRepos {
+		logins, err := b.RepoContributors(ctx, repo)
+		if err != nil {
+			return result, fmt.Errorf("failed to list contributors of %s: %w", repo, err)
+		}
+		repoContributors[repo] = logins
+	}
+
+	accounts, err := b.DB.GetAllGithubAccounts()
+	if err != nil {
+		return result, err
+	}
+	overrides, err := b.DB.GetAllContributorOverrides()
+	if err != nil {
+		return result, err
+	}
+	userOverrides := map[snowflake.ID][]string{}
+	for _, override := range overrides {
+		userOverrides[override.UserID] = append(userOverrides[override.UserID], override.Repo)
+	}
+
+	for _, account := range accounts {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Accounts++
+
+		var (
+			repos     []string
+			wantRoles []snowflake.ID
+		)
+		for repo, logins := range repoContributors {
+			if slices.Contains(logins, account.Login) {
+				repos = append(repos, repo)
+				wantRoles = append(wantRoles, contributorRepos[repo])
+			}
+		}
+		for _, repo := range userOverrides[account.UserID] {
+			if roleID, ok := contributorRepos[repo]; ok {
+				wantRoles = append(wantRoles, roleID)
+			}
+		}
+
+		if err = b.DB.SetGithubAccount(account.UserID, account.Login, repos); err != nil {
+			b.LogLimiter.Errorf(b.Logger, "Failed to update github account of %s: %s", account.UserID, err)
+			result.Errors++
+		}
+
+		member, err := b.Client.Rest().GetMember(b.GuildID(), account.UserID)
+		if common.IsServerError(err) {
+			return result, fmt.Errorf("%w: %s", ErrDiscordUnavailable, err)
+		} else if err != nil {
+			var restErr *rest.Error
+			if !errors.As(err, &restErr) || restErr.Response == nil || restErr.Response.StatusCode != 404 {
+				b.LogLimiter.Errorf(b.Logger, "Failed to get member %s: %s", account.UserID, err)
+				result.Errors++
+			}
+			continue
+		}
+
+		var handledRoles []snowflake.ID
+		for _, roleID := range contributorRepos {
+			if slices.Contains(handledRoles, roleID) {
+				continue
+			}
+			handledRoles = append(handledRoles, roleID)
+
+			want, has := slices.Contains(wantRoles, roleID), slices.Contains(member.RoleIDs, roleID)
+			if want && !has {
+				if err = b.Client.Rest().AddMemberRole(b.GuildID(), account.UserID, roleID); common.IsServerError(err) {
+					return result, fmt.Errorf("%w: %s", ErrDiscordUnavailable, err)
+				} else if err != nil {
+					b.LogLimiter.Errorf(b.Logger, "Failed to add contributor role %s to %s: %s", roleID, account.UserID, err)
+					result.Errors++
+					continue
+				}
+				result.RolesAdded++
+				b.Events.Publish(eventbus.ContributorRoleAdded{
+					UserID: account.UserID,
+					RoleID: roleID,
+				})
+			} else if !want && has {
+				if err = b.Client.Rest().RemoveMemberRole(b.GuildID(), account.UserID, roleID); common.IsServerError(err) {
+					return result, fmt.Errorf("%w: %s", ErrDiscordUnavailable, err)
+				} else if err != nil {
+					b.LogLimiter.Errorf(b.Logger, "Failed to remove contributor role %s from %s: %s", roleID, account.UserID, err)
+					result.Errors++
+					continue
+				}
+				result.RolesRemoved++
+			}
+		}
+	}
+	return result, nil
+}
+
+// listMembersRoute is the paginated variant of route.GetMembers.
+var listMembersRoute = route.NewAPIRoute(route.GET, "/guilds/{guild.id}/members", "limit", "after")
+
+// StripRole removes the role from all members of the guild which have it and returns the amount of affected members.
+func (b *Butler) StripRole(ctx context.Context, guildID snowflake.ID, roleID snowflake.ID) (int, error) {
+	var (
+		after    snowflake.ID
+		affected int
+	)
+	for {
+		compiledRoute, err := listMembersRoute.Compile(route.QueryValues{"limit": 1000, "after": after}, guildID)
+		if err != nil {
+			return affected, err
+		}
+		var members []discord.Member
+		if err = b.Client.Rest().Do(compiledRoute, nil, &members, rest.WithCtx(ctx)); err != nil {
+			return affected, err
+		}
+		for _, member := range members {
+			if !slices.Contains(member.RoleIDs, roleID) {
+				continue
+			}
+			if err = b.Client.Rest().RemoveMemberRole(guildID, member.User.ID, roleID, rest.WithCtx(ctx)); err != nil {
+				return affected, fmt.Errorf("failed to remove role from %s: %w", member.User.ID, err)
+			}
+			affected++
+		}
+		if len(members) < 1000 {
+			return affected, nil
+		}
+		after = members[len(members)-1].User.ID
+	}
+}
@@ -0,0 +1,132 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	// dbLoadTimeout limits how long loading a value into a dbCache may take, as it happens while handling interactions
+	// and messages.
+	dbLoadTimeout = 2 * time.Second
+	// dbFailureTTL is how long a failed load is remembered, so a missing table or an unavailable database doesn't cost
+	// a query and an error on every use.
+	dbFailureTTL = 30 * time.Second
+)
+
+// dbCache caches values loaded from the database by ID. The database is queried without holding the lock. A failed
+// load fails all loads for dbFailureTTL, as it is usually caused by the table or database rather than the ID. Cached
+// values must not be modified, use update to replace them.
+type dbCache[V any] struct {
+	mu      sync.Mutex
+	values  map[snowflake.ID]V
+	failure *dbFailure
+	// generation is increased by every change, so values loaded before it are not cached
+	generation uint64
+}
+
+type dbFailure struct {
+	err   error
+	until time.Time
+}
+
+// cachedFailureError is returned by dbCache.get for a failed load which is still cached, so callers can report the
+// failure only once.
+type cachedFailureError struct {
+	err error
+}
+
+func (e *cachedFailureError) Error() string {
+	return "loading failed recently: " + e.err.Error()
+}
+
+func (e *cachedFailureError) Unwrap() error {
+	return e.err
+}
+
+// IsCachedFailure reports whether the error is a failed database load which was already returned before, so it
+// doesn't need to be reported again.
+func IsCachedFailure(err error) bool {
+	var cachedErr *cachedFailureError
+	return errors.As(err, &cachedErr)
+}
+
+// get returns the cached value of the ID or loads it with load.
+func (c *dbCache[V]) get(id snowflake.ID, load func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	if value, ok := c.values[id]; ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+	if c.failure != nil && time.Now().Before(c.failure.until) {
+		err := c.failure.err
+		c.mu.Unlock()
+		var zero V
+		return zero, &cachedFailureError{err: err}
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbLoadTimeout)
+	defer cancel()
+	value, err := load(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		c.failure = &dbFailure{err: err, until: time.Now().Add(dbFailureTTL)}
+		return value, err
+	}
+	c.failure = nil
+	// the value may be outdated if it was changed while loading
+	if c.generation == generation {
+		c.store(id, value)
+	}
+	return value, nil
+}
+
+// set caches the value of the ID.
+func (c *dbCache[V]) set(id snowflake.ID, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.store(id, value)
+}
+
+// update replaces the cached value of the ID with the result of fn. Nothing is cached if the value isn't, it is loaded
+// with the change on its next use.
+func (c *dbCache[V]) update(id snowflake.ID, fn func(value V) V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if value, ok := c.values[id]; ok {
+		c.values[id] = fn(value)
+	}
+}
+
+// forget removes the cached value of the ID, so it is loaded again on its next use.
+func (c *dbCache[V]) forget(id snowflake.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	delete(c.values, id)
+}
+
+// len returns how many values are cached.
+func (c *dbCache[V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.values)
+}
+
+// store caches the value of the ID. mu must be held.
+func (c *dbCache[V]) store(id snowflake.ID, value V) {
+	if c.values == nil {
+		c.values = map[snowflake.ID]V{}
+	}
+	c.values[id] = value
+}
@@ -0,0 +1,65 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDBCache(t *testing.T) {
+	var (
+		c     dbCache[string]
+		loads int
+	)
+	errMissingTable := errors.New("no such table")
+	failing := func(context.Context) (string, error) {
+		loads++
+		return "", errMissingTable
+	}
+	if _, err := c.get(1, failing); !errors.Is(err, errMissingTable) || IsCachedFailure(err) {
+		t.Errorf("get() error = %v, want %v", err, errMissingTable)
+	}
+	if _, err := c.get(1, failing); !errors.Is(err, errMissingTable) || !IsCachedFailure(err) {
+		t.Errorf("get() error = %v, want the cached failure", err)
+	}
+	// the failure is not specific to the ID
+	if _, err := c.get(2, failing); !IsCachedFailure(err) {
+		t.Errorf("get() error = %v, want the cached failure", err)
+	}
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+	c.failure = nil
+
+	c.set(1, "set")
+	if value, err := c.get(1, failing); err != nil || value != "set" {
+		t.Errorf("get() = %q, %v, want the set value", value, err)
+	}
+
+	// the lock is not held while loading and a value changed meanwhile is kept
+	value, err := c.get(2, func(context.Context) (string, error) {
+		c.set(2, "new")
+		return "old", nil
+	})
+	if err != nil || value != "old" {
+		t.Errorf("get() = %q, %v, want the loaded value", value, err)
+	}
+	if value, _ = c.get(2, failing); value != "new" {
+		t.Errorf("cached value = %q, want %q", value, "new")
+	}
+
+	c.update(2, func(value string) string {
+		return value + "er"
+	})
+	c.update(3, func(value string) string {
+		t.Error("update() changed a value which is not cached")
+		return value
+	})
+	if value, _ = c.get(2, failing); value != "newer" {
+		t.Errorf("updated value = %q, want %q", value, "newer")
+	}
+	c.forget(2)
+	if c.len() != 1 {
+		t.Errorf("len() = %d, want 1", c.len())
+	}
+}
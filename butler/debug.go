@@ -0,0 +1,78 @@
+package butler
+
+import (
+	"database/sql"
+	"runtime"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/mod_mail"
+	"github.com/hhhapz/doc"
+)
+
+// DebugSnapshot is a read-only snapshot of the internal state for troubleshooting. It never contains secrets like
+// tokens or passwords.
+type DebugSnapshot struct {
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"version"`
+	Uptime    string    `json:"uptime"`
+
+	Goroutines    int    `json:"goroutines"`
+	HeapAlloc     uint64 `json:"heap_alloc"`
+	HeapObjects   uint64 `json:"heap_objects"`
+	NumGC         uint32 `json:"num_gc"`
+	Commands      int    `json:"commands"`
+	Components    int    `json:"components"`
+	TextCommands  int    `json:"text_commands"`
+	Webhooks      int    `json:"webhooks"`
+	Releases      int    `json:"releases"`
+	Aliases       int    `json:"aliases"`
+	DocCache      int    `json:"doc_cache"`
+	GuildPrefixes int    `json:"guild_prefixes"`
+	// ComponentStates are the stored states of long-lived components like Pages.
+	ComponentStates int `json:"component_states"`
+
+	DiscordDegraded  bool `json:"discord_degraded"`
+	DatabaseDegraded bool `json:"database_degraded"`
+
+	ModMail     mod_mail.Stats   `json:"mod_mail"`
+	Database    sql.DBStats      `json:"database"`
+	GithubCache GithubCacheStats `json:"github_cache"`
+}
+
+// DebugSnapshot collects the current internal state.
+func (b *Butler) DebugSnapshot() DebugSnapshot {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	var docCache int
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		docCache = len(cache)
+	})
+
+	guildPrefixes := b.GuildPrefixes.cache.len()
+
+	health := b.Health.Status()
+	return DebugSnapshot{
+		DiscordDegraded:  health.DiscordDegraded,
+		DatabaseDegraded: health.DatabaseDegraded,
+		CreatedAt:        time.Now(),
+		Version:          b.Version,
+		Uptime:           time.Since(b.startedAt).Round(time.Second).String(),
+		Goroutines:       runtime.NumGoroutine(),
+		HeapAlloc:        memStats.HeapAlloc,
+		HeapObjects:      memStats.HeapObjects,
+		NumGC:            memStats.NumGC,
+		Commands:         len(b.Commands),
+		Components:       len(b.Components),
+		TextCommands:     len(b.TextCommands),
+		Webhooks:         b.ReleaseWebhooks.Len(),
+		Releases:         len(b.ReleaseConfigs()),
+		Aliases:          len(b.Aliases(nil)),
+		DocCache:         docCache,
+		GuildPrefixes:    guildPrefixes,
+		ComponentStates:  b.ComponentStates.Len(),
+		ModMail:          b.ModMail.Stats(),
+		Database:         b.DB.Stats(),
+		GithubCache:      b.GithubCache.Stats(),
+	}
+}
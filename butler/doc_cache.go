@@ -0,0 +1,121 @@
+package butler
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/hhhapz/doc"
+)
+
+const defaultDocCacheTTLHours = 24
+
+func init() {
+	// comments are lists of notes, gob needs to know the concrete types to decode them
+	gob.Register(doc.Comment{})
+	gob.Register(doc.Heading(""))
+	gob.Register(doc.Paragraph(""))
+	gob.Register(doc.Pre(""))
+}
+
+func (c DocsConfig) cacheTTL() time.Duration {
+	if c.CacheTTLHours > 0 {
+		return time.Duration(c.CacheTTLHours) * time.Hour
+	}
+	return defaultDocCacheTTLHours * time.Hour
+}
+
+// SetDocCacheTTL changes how many hours cached docs are used and saves it in the config. Cached docs which are older
+// than the new TTL are dropped right away, so they are fetched again on their next use. It returns how many were
+// dropped.
+func (b *Butler) SetDocCacheTTL(hours int) (int, error) {
+	var ttl time.Duration
+	err := b.UpdateConfig(func(cfg *Config) {
+		cfg.Docs.CacheTTLHours = hours
+		ttl = cfg.Docs.cacheTTL()
+	})
+	return b.expireDocCache(ttl), err
+}
+
+// expireDocCache removes the docs from the doc cache which were fetched longer than the TTL ago.
+func (b *Butler) expireDocCache(ttl time.Duration) int {
+	expiredBefore := time.Now().Add(-ttl)
+	var expired int
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for module, pkg := range cache {
+			if pkg.Created.Before(expiredBefore) {
+				delete(cache, module)
+				expired++
+			}
+		}
+	})
+	return expired
+}
+
+// LoadDocCache fills the doc cache from the cache file. Entries older than the TTL are skipped so they are fetched again.
+func (b *Butler) LoadDocCache() (int, error) {
+	var (
+		path string
+		ttl  time.Duration
+	)
+	b.ReadConfig(func(cfg Config) {
+		path = cfg.Docs.CacheFile
+		ttl = cfg.Docs.cacheTTL()
+	})
+	if path == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	var packages map[string]doc.CachedPackage
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&packages); err != nil {
+		return 0, err
+	}
+
+	expiredBefore := time.Now().Add(-ttl)
+	var loaded int
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for module, pkg := range packages {
+			if pkg.Created.Before(expiredBefore) {
+				continue
+			}
+			if _, ok := cache[module]; ok {
+				continue
+			}
+			pkg := pkg
+			cache[module] = &pkg
+			loaded++
+		}
+	})
+	return loaded, nil
+}
+
+// SaveDocCache writes the doc cache to the cache file.
+func (b *Butler) SaveDocCache() error {
+	var path string
+	b.ReadConfig(func(cfg Config) {
+		path = cfg.Docs.CacheFile
+	})
+	if path == "" {
+		return nil
+	}
+	packages := map[string]doc.CachedPackage{}
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for module, pkg := range cache {
+			packages[module] = *pkg
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(packages); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
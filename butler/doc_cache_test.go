@@ -0,0 +1,46 @@
+package butler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/log"
+	"github.com/hhhapz/doc"
+)
+
+func TestSetDocCacheTTL(t *testing.T) {
+	path := configPath
+	configPath = filepath.Join(t.TempDir(), "config.json")
+	t.Cleanup(func() {
+		configPath = path
+	})
+
+	b := New(log.Default(), "test", Config{})
+	b.DocClient = doc.WithCache(nil)
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		cache["github.com/disgoorg/disgo"] = &doc.CachedPackage{Created: time.Now().Add(-30 * time.Minute)}
+		cache["github.com/disgoorg/log"] = &doc.CachedPackage{Created: time.Now().Add(-3 * time.Hour)}
+	})
+
+	expired, err := b.SetDocCacheTTL(2)
+	if err != nil {
+		t.Fatalf("SetDocCacheTTL() error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("expired = %d, want 1", expired)
+	}
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		if _, ok := cache["github.com/disgoorg/disgo"]; !ok {
+			t.Error("docs within the TTL were dropped")
+		}
+		if _, ok := cache["github.com/disgoorg/log"]; ok {
+			t.Error("docs older than the TTL were kept")
+		}
+	})
+	b.ReadConfig(func(cfg Config) {
+		if cfg.Docs.CacheTTLHours != 2 {
+			t.Errorf("cache_ttl_hours = %d, want 2", cfg.Docs.CacheTTLHours)
+		}
+	})
+}
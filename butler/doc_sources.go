@@ -0,0 +1,125 @@
+package butler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hhhapz/doc"
+	"github.com/hhhapz/doc/godocs"
+)
+
+// DocSourceConfig is a godocs compatible documentation site, like a private instance, used for the modules with one of
+// the prefixes instead of the public one.
+type DocSourceConfig struct {
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// Prefixes are the module paths the source is used for, e.g. "git.example.com/". The longest matching prefix of all
+	// sources wins.
+	Prefixes []string `json:"prefixes" yaml:"prefixes" toml:"prefixes"`
+	// URL is the base URL of the site the module path is appended to, e.g. "https://godocs.example.com/".
+	URL string `json:"url" yaml:"url" toml:"url"`
+	// LinkURL is the base URL of the pages linked in results. Defaults to URL.
+	LinkURL string `json:"link_url,omitempty" yaml:"link_url,omitempty" toml:"link_url,omitempty"`
+}
+
+func (c DocSourceConfig) linkURL() string {
+	if c.LinkURL != "" {
+		return c.LinkURL
+	}
+	return c.URL
+}
+
+// sourceParser parses a godocs compatible site at another URL than the public one.
+type sourceParser struct {
+	doc.Parser
+	url string
+}
+
+func (p sourceParser) URL(module string) string {
+	return strings.TrimSuffix(p.url, "/") + "/" + module
+}
+
+type docSource struct {
+	prefix   string
+	config   DocSourceConfig
+	searcher doc.Searcher
+}
+
+// PackageURL returns the URL of the docs page of the module with the configured DocSources.
+func (b *Butler) PackageURL(module string) string {
+	return b.DocSources.PackageURL(module)
+}
+
+// DocSources searches each module with the source configured for its prefix and falls back to the public source.
+type DocSources struct {
+	public doc.Searcher
+	// sources are ordered by the length of their prefix, the longest first
+	sources []docSource
+}
+
+var _ doc.Searcher = (*DocSources)(nil)
+
+// NewDocSources creates the searchers of the configured sources. Sources without a URL are skipped and returned as
+// error.
+func NewDocSources(client *http.Client, configs []DocSourceConfig) (*DocSources, error) {
+	sources := &DocSources{public: doc.New(client, godocs.Parser)}
+	var invalid []string
+	for _, cfg := range configs {
+		if cfg.URL == "" {
+			invalid = append(invalid, cfg.Name)
+			continue
+		}
+		searcher := doc.New(client, sourceParser{Parser: godocs.Parser, url: cfg.URL})
+		for _, prefix := range cfg.Prefixes {
+			sources.sources = append(sources.sources, docSource{
+				prefix:   prefix,
+				config:   cfg,
+				searcher: searcher,
+			})
+		}
+	}
+	sort.SliceStable(sources.sources, func(i, j int) bool {
+		return len(sources.sources[i].prefix) > len(sources.sources[j].prefix)
+	})
+	if len(invalid) > 0 {
+		return sources, fmt.Errorf("doc sources without url: %s", strings.Join(invalid, ", "))
+	}
+	return sources, nil
+}
+
+func (s *DocSources) Search(ctx context.Context, module string) (doc.Package, error) {
+	if source, ok := s.source(module); ok {
+		return source.searcher.Search(ctx, module)
+	}
+	return s.public.Search(ctx, module)
+}
+
+// Source returns the name of the source used for the module, or an empty string for the public source.
+func (s *DocSources) Source(module string) string {
+	if source, ok := s.source(module); ok {
+		return source.config.Name
+	}
+	return ""
+}
+
+// PackageURL returns the URL of the docs page of the module. A nil DocSources links the public source.
+func (s *DocSources) PackageURL(module string) string {
+	if s == nil {
+		return fmt.Sprintf(embedPackageURLFormat, module)
+	}
+	if source, ok := s.source(module); ok {
+		return strings.TrimSuffix(source.config.linkURL(), "/") + "/" + module
+	}
+	return fmt.Sprintf(embedPackageURLFormat, module)
+}
+
+func (s *DocSources) source(module string) (docSource, bool) {
+	for _, source := range s.sources {
+		if strings.HasPrefix(module, source.prefix) {
+			return source, true
+		}
+	}
+	return docSource{}, false
+}
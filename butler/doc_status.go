@@ -0,0 +1,120 @@
+package butler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hhhapz/doc"
+)
+
+type DocStatus struct {
+	Module    string
+	Err       error
+	CheckedAt time.Time
+}
+
+type DocStatuses struct {
+	mu       sync.Mutex
+	statuses map[string]DocStatus
+}
+
+func (s *DocStatuses) Set(alias string, status DocStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.statuses == nil {
+		s.statuses = map[string]DocStatus{}
+	}
+	s.statuses[alias] = status
+}
+
+func (s *DocStatuses) Delete(alias string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.statuses, alias)
+}
+
+func (s *DocStatuses) All() map[string]DocStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make(map[string]DocStatus, len(s.statuses))
+	for alias, status := range s.statuses {
+		statuses[alias] = status
+	}
+	return statuses
+}
+
+// WarmAlias searches the module of the alias to populate the doc cache and records the result.
+func (b *Butler) WarmAlias(alias string, module string) error {
+	_, err := b.DocClient.Search(context.TODO(), module)
+	b.DocStatuses.Set(alias, DocStatus{
+		Module:    module,
+		Err:       err,
+		CheckedAt: time.Now(),
+	})
+	return err
+}
+
+// RewarmAlias evicts the module of the alias and its subpackages from the doc cache and fetches it again. The
+// previously cached packages are kept if the fetch fails.
+func (b *Butler) RewarmAlias(alias string, module string) (time.Duration, error) {
+	evicted := map[string]*doc.CachedPackage{}
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for key, pkg := range cache {
+			if key == module || strings.HasPrefix(key, module+"/") {
+				evicted[key] = pkg
+				delete(cache, key)
+			}
+		}
+	})
+
+	start := time.Now()
+	err := b.WarmAlias(alias, module)
+	took := time.Since(start)
+	if err != nil {
+		b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+			for key, pkg := range evicted {
+				if _, ok := cache[key]; !ok {
+					cache[key] = pkg
+				}
+			}
+		})
+	}
+	return took, err
+}
+
+// DocCacheEntry is the metadata of a module in the doc cache.
+type DocCacheEntry struct {
+	URL         string
+	Subpackages int
+	Symbols     int
+	Created     time.Time
+	Updated     time.Time
+}
+
+// DocCacheEntry returns the metadata of the module in the doc cache if it is cached.
+func (b *Butler) DocCacheEntry(module string) (entry DocCacheEntry, ok bool) {
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		var pkg *doc.CachedPackage
+		if pkg, ok = cache[module]; !ok {
+			return
+		}
+		entry = DocCacheEntry{
+			URL:         pkg.URL,
+			Subpackages: len(pkg.Subpackages),
+			Symbols:     len(packageSymbols(pkg.Package)),
+			Created:     pkg.Created,
+			Updated:     pkg.Updated,
+		}
+	})
+	return
+}
+
+// Get returns the result of the last warm up of the alias.
+func (s *DocStatuses) Get(alias string) (DocStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.statuses[alias]
+	return status, ok
+}
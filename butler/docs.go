@@ -2,8 +2,10 @@ package butler
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/disgoorg/disgo-butler/common"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/hhhapz/doc"
 )
@@ -13,12 +15,15 @@ const (
 	embedTitleFormat       = "%s: %s"
 	embedColor             = 0x5865f2
 	embedPackageURLFormat  = "https://pkg.go.dev/%s"
-	embedURLFormat         = embedPackageURLFormat + "#%s"
 	exampleFormat          = "\n%s:\n```go\n%s\n```\n```%s```\n"
 	PkgInfo                = "<pkg_info>"
+	PkgSymbols             = "<pkg_symbols>"
+	symbolsPageLength      = 2000
+	collapsedLength        = 1024
+	symbolSignatureLength  = 60
 )
 
-func GetDocsEmbed(pkg doc.Package, query string, expandSignature bool, expandComment bool, expandMethods bool, expandExamples bool) (discord.Embed, discord.SelectMenuComponent) {
+func (b *Butler) GetDocsEmbed(pkg doc.Package, query string, expandSignature bool, expandComment bool, expandMethods bool, expandExamples bool) (discord.Embed, discord.SelectMenuComponent) {
 	var (
 		embed         discord.Embed
 		moreSignature bool
@@ -28,7 +33,7 @@ func GetDocsEmbed(pkg doc.Package, query string, expandSignature bool, expandCom
 	)
 
 	if query == "" || query == PkgInfo {
-		embed, moreComment, moreExamples = EmbedFromPackage(pkg, expandComment, expandExamples)
+		embed, moreComment, moreExamples = b.EmbedFromPackage(pkg, expandComment, expandExamples)
 	} else {
 		values := strings.Split(query, ".")
 		for i := range values {
@@ -37,19 +42,17 @@ func GetDocsEmbed(pkg doc.Package, query string, expandSignature bool, expandCom
 		if t, ok := pkg.Types[values[0]]; ok {
 			if len(values) > 1 {
 				if m, ok := t.Methods[values[1]]; ok {
-					embed, moreSignature, moreComment = EmbedFromMethod(pkg, m, expandSignature, expandComment, expandExamples)
+					embed, moreSignature, moreComment = b.EmbedFromMethod(pkg, m, expandSignature, expandComment, expandExamples)
 				}
 			} else {
-				embed, moreSignature, moreComment = EmbedFromType(pkg, t, expandSignature, expandComment, expandMethods, expandExamples)
+				embed, moreSignature, moreComment = b.EmbedFromType(pkg, t, expandSignature, expandComment, expandMethods, expandExamples)
 				moreMethods = len(t.Methods) > 0 && !expandMethods
 			}
 		} else if f, ok := pkg.Functions[values[0]]; ok {
-			embed, moreSignature, moreComment = EmbedFromFunc(pkg, f, expandSignature, expandComment, expandExamples)
+			embed, moreSignature, moreComment = b.EmbedFromFunc(pkg, f, expandSignature, expandComment, expandExamples)
 		}
 	}
-	if len(embed.Description) > 4096 {
-		embed.Description = embed.Description[:4095] + "…"
-	}
+	embed.Description = common.Truncate(embed.Description, common.Limits.EmbedDescriptionLength)
 
 	var options []discord.SelectMenuOption
 	if moreSignature {
@@ -85,15 +88,73 @@ func GetDocsEmbed(pkg doc.Package, query string, expandSignature bool, expandCom
 	return embed, discord.NewSelectMenu("docs_action", "action", options...)
 }
 
-func EmbedFromPackage(pkg doc.Package, expandComment bool, expandExamples bool) (discord.Embed, bool, bool) {
+// GetCompactDocsEmbed renders the first line of the signature and comment of the symbol, or of the package overview,
+// with an option to show the default rendering.
+func (b *Butler) GetCompactDocsEmbed(pkg doc.Package, query string) (discord.Embed, discord.SelectMenuComponent) {
+	embed := discord.Embed{
+		Title: pkg.URL,
+		URL:   b.PackageURL(pkg.URL),
+		Color: embedColor,
+	}
+	var (
+		signature string
+		comment   doc.Comment
+	)
+	if query == "" || query == PkgInfo {
+		comment = pkg.Overview
+	} else {
+		values := strings.Split(query, ".")
+		for i := range values {
+			values[i] = strings.ToLower(values[i])
+		}
+		if t, ok := pkg.Types[values[0]]; ok {
+			name := t.Name
+			signature, comment = t.Signature, t.Comment
+			if len(values) > 1 {
+				m, ok := t.Methods[values[1]]
+				if !ok {
+					return embed, compactSelectMenu()
+				}
+				name = m.For + "." + m.Name
+				signature, comment = m.Signature, m.Comment
+			}
+			embed.Title = fmt.Sprintf(embedTitleFormat, pkg.URL, name)
+			embed.URL = b.PackageURL(pkg.URL) + "#" + name
+		} else if f, ok := pkg.Functions[values[0]]; ok {
+			signature, comment = f.Signature, f.Comment
+			embed.Title = fmt.Sprintf(embedTitleFormat, pkg.URL, f.Name)
+			embed.URL = b.PackageURL(pkg.URL) + "#" + f.Name
+		}
+	}
+
+	markdown := strings.SplitN(strings.TrimSpace(comment.Markdown()), "\n", 2)[0]
+	if markdown == "" {
+		markdown = "No comments found."
+	}
+	if signature != "" {
+		signature = strings.TrimSuffix(strings.TrimSpace(strings.SplitN(signature, "\n", 2)[0]), "{")
+		markdown = fmt.Sprintf("`%s`\n%s", strings.ReplaceAll(strings.TrimSpace(signature), "`", "'"), markdown)
+	}
+	embed.Description = common.Truncate(markdown, collapsedLength)
+	return embed, compactSelectMenu()
+}
+
+func compactSelectMenu() discord.SelectMenuComponent {
+	return discord.NewSelectMenu("docs_action", "action",
+		discord.NewSelectMenuOption("show details", "expand:details").WithEmoji(discord.ComponentEmoji{Name: "🔼"}),
+		discord.NewSelectMenuOption("delete", "delete").WithEmoji(discord.ComponentEmoji{Name: "❌"}),
+	)
+}
+
+func (b *Butler) EmbedFromPackage(pkg doc.Package, expandComment bool, expandExamples bool) (discord.Embed, bool, bool) {
 	var (
 		moreComment  bool
 		moreExamples bool
 	)
 
 	description := pkg.Overview.Markdown()
-	if !expandComment && len(description) > 1024 {
-		description = description[:1023] + "…"
+	if !expandComment && len(description) > collapsedLength {
+		description = common.Truncate(description, collapsedLength)
 		moreComment = true
 	}
 
@@ -101,54 +162,51 @@ func EmbedFromPackage(pkg doc.Package, expandComment bool, expandExamples bool)
 	for _, e := range pkg.Examples {
 		examples += fmt.Sprintf(exampleFormat, e.Name, e.Code, e.Output)
 	}
-	if !expandExamples && len(examples) > 1024 {
-		examples = examples[:1023] + "…"
+	if !expandExamples && len(examples) > collapsedLength {
+		examples = common.Truncate(examples, collapsedLength)
 		moreExamples = true
 	}
 
 	return discord.Embed{
 		Title:       pkg.URL,
-		URL:         fmt.Sprintf(embedPackageURLFormat, pkg.URL),
+		URL:         b.PackageURL(pkg.URL),
 		Description: description + "\n" + examples,
 		Color:       embedColor,
 	}, moreComment, moreExamples
 }
 
-func EmbedFromMethod(pkg doc.Package, m doc.Method, expandSignature bool, expandComment bool, expandExamples bool) (discord.Embed, bool, bool) {
+func (b *Butler) EmbedFromMethod(pkg doc.Package, m doc.Method, expandSignature bool, expandComment bool, expandExamples bool) (discord.Embed, bool, bool) {
 	description, moreSignature, moreComment := FormatDescription(m.Signature, m.Comment, m.Examples, expandSignature, expandComment, expandExamples)
 	return discord.Embed{
 		Title:       fmt.Sprintf(embedTitleFormat, pkg.URL, m.For+"."+m.Name),
-		URL:         fmt.Sprintf(embedURLFormat, pkg.URL, m.For+"."+m.Name),
+		URL:         b.PackageURL(pkg.URL) + "#" + m.For + "." + m.Name,
 		Description: description,
 		Color:       embedColor,
 	}, moreSignature, moreComment
 }
 
-func EmbedFromFunc(pkg doc.Package, f doc.Function, expandSignature bool, expandComment bool, expandExamples bool) (discord.Embed, bool, bool) {
+func (b *Butler) EmbedFromFunc(pkg doc.Package, f doc.Function, expandSignature bool, expandComment bool, expandExamples bool) (discord.Embed, bool, bool) {
 	description, moreSignature, moreComment := FormatDescription(f.Signature, f.Comment, f.Examples, expandSignature, expandComment, expandExamples)
 	return discord.Embed{
 		Title:       fmt.Sprintf(embedTitleFormat, pkg.URL, f.Name),
-		URL:         fmt.Sprintf(embedURLFormat, pkg.URL, f.Name),
+		URL:         b.PackageURL(pkg.URL) + "#" + f.Name,
 		Description: description,
 		Color:       embedColor,
 	}, moreSignature, moreComment
 }
 
-func EmbedFromType(pkg doc.Package, t doc.Type, expandSignature bool, expandComment bool, expandMethods bool, expandExamples bool) (discord.Embed, bool, bool) {
+func (b *Butler) EmbedFromType(pkg doc.Package, t doc.Type, expandSignature bool, expandComment bool, expandMethods bool, expandExamples bool) (discord.Embed, bool, bool) {
 	description, moreSignature, moreComment := FormatDescription(t.Signature, t.Comment, t.Examples, expandSignature, expandComment, expandExamples)
 	if expandMethods {
 		methods := "```go\n"
 		for _, m := range t.Methods {
 			methods += m.Signature + "\n\n"
 		}
-		description += methods + "\n```"
-		if len(description) > 4096 {
-			description = description[:4082] + "…\n```"
-		}
+		description = common.Truncate(description+methods+"\n```", common.Limits.EmbedDescriptionLength)
 	}
 	return discord.Embed{
 		Title:       fmt.Sprintf(embedTitleFormat, pkg.URL, t.Name),
-		URL:         fmt.Sprintf(embedURLFormat, pkg.URL, t.Name),
+		URL:         b.PackageURL(pkg.URL) + "#" + t.Name,
 		Description: description,
 		Color:       embedColor,
 	}, moreSignature, moreComment
@@ -163,8 +221,8 @@ func FormatDescription(signature string, comment doc.Comment, examples []doc.Exa
 	if !expandSignature && len(lines) > 6 {
 		moreSignature = true
 		signature = lines[0] + "\n…"
-	} else if len(signature) > 4096 {
-		signature = signature[:4082] + "…\n```"
+	} else {
+		signature = common.Truncate(signature, common.Limits.EmbedDescriptionLength-len(embedDescriptionFormat))
 	}
 
 	markdown := comment.Markdown()
@@ -176,9 +234,9 @@ func FormatDescription(signature string, comment doc.Comment, examples []doc.Exa
 		moreComment = true
 		markdown = lines[0] + "\n…"
 	}
-	if !expandComment && len(markdown) > 1024 {
+	if !expandComment && len(markdown) > collapsedLength {
 		moreComment = true
-		markdown = markdown[:1023] + "…"
+		markdown = common.Truncate(markdown, collapsedLength)
 	}
 
 	var examplesStr string
@@ -189,3 +247,64 @@ func FormatDescription(signature string, comment doc.Comment, examples []doc.Exa
 	}
 	return fmt.Sprintf(embedDescriptionFormat, signature, markdown, examplesStr), moreSignature, moreComment
 }
+
+// GetDocsSymbolPages renders the exported types, functions and methods of the package grouped by category into pages.
+func (b *Butler) GetDocsSymbolPages(pkg doc.Package) []string {
+	var types, functions, methods []string
+	for _, t := range pkg.Types {
+		types = append(types, b.formatSymbol(pkg, t.Name, t.Signature))
+		for _, f := range t.TypeFunctions {
+			functions = append(functions, b.formatSymbol(pkg, f.Name, f.Signature))
+		}
+		for _, m := range t.Methods {
+			methods = append(methods, b.formatSymbol(pkg, m.For+"."+m.Name, m.Signature))
+		}
+	}
+	for _, f := range pkg.Functions {
+		functions = append(functions, b.formatSymbol(pkg, f.Name, f.Signature))
+	}
+
+	var (
+		pages   []string
+		curPage string
+	)
+	for _, category := range []struct {
+		name    string
+		symbols []string
+	}{
+		{name: "Types", symbols: types},
+		{name: "Functions", symbols: functions},
+		{name: "Methods", symbols: methods},
+	} {
+		if len(category.symbols) == 0 {
+			continue
+		}
+		sort.Strings(category.symbols)
+		header := fmt.Sprintf("**%s**\n", category.name)
+		if len(curPage) > 0 {
+			header = "\n" + header
+		}
+		curPage += header
+		for _, symbol := range category.symbols {
+			if len(curPage)+len(symbol) > symbolsPageLength {
+				pages = append(pages, curPage)
+				curPage = fmt.Sprintf("**%s (continued)**\n", category.name)
+			}
+			curPage += symbol
+		}
+	}
+	if len(curPage) > 0 {
+		pages = append(pages, curPage)
+	}
+	return pages
+}
+
+func (b *Butler) formatSymbol(pkg doc.Package, name string, signature string) string {
+	signature = strings.SplitN(signature, "\n", 2)[0]
+	signature = strings.TrimSuffix(strings.TrimSpace(signature), "{")
+	signature = strings.ReplaceAll(strings.TrimSpace(signature), "`", "'")
+	if runes := []rune(signature); len(runes) > symbolSignatureLength {
+		signature = string(runes[:symbolSignatureLength-1]) + "…"
+	}
+	return fmt.Sprintf("[`%s`](%s) `%s`\n", name, b.PackageURL(pkg.URL)+"#"+name, signature)
+}
@@ -0,0 +1,97 @@
+package butler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/hhhapz/doc"
+)
+
+// exampleOutputLength is how much of the output of an example is shown below its code.
+const exampleOutputLength = 500
+
+// DocsExample is an example of a package or one of its symbols.
+type DocsExample struct {
+	doc.Example
+	// Symbol is the name of the symbol the example belongs to, empty for examples of the package.
+	Symbol string
+	URL    string
+}
+
+// FindDocsExamples returns the examples of the symbol, or the ones of the package if the symbol is empty. Examples of a
+// type include the ones of its functions and methods. ok is false if the symbol does not exist.
+func (b *Butler) FindDocsExamples(pkg doc.Package, symbol string) (examples []DocsExample, ok bool) {
+	add := func(symbol string, symbolExamples []doc.Example) {
+		for _, example := range symbolExamples {
+			examples = append(examples, DocsExample{
+				Example: example,
+				Symbol:  symbol,
+				URL:     b.PackageURL(pkg.URL) + "#" + exampleAnchor(symbol, example.Name),
+			})
+		}
+	}
+	if symbol == "" {
+		add("", pkg.Examples)
+		return examples, true
+	}
+
+	values := strings.Split(strings.ToLower(symbol), ".")
+	if t, ok := pkg.Types[values[0]]; ok {
+		if len(values) > 1 {
+			m, ok := t.Methods[values[1]]
+			if !ok {
+				return nil, false
+			}
+			add(m.For+"."+m.Name, m.Examples)
+			return examples, true
+		}
+		add(t.Name, t.Examples)
+		for _, name := range sortedKeys(t.TypeFunctions) {
+			add(t.TypeFunctions[name].Name, t.TypeFunctions[name].Examples)
+		}
+		for _, name := range sortedKeys(t.Methods) {
+			m := t.Methods[name]
+			add(m.For+"."+m.Name, m.Examples)
+		}
+		return examples, true
+	}
+	if f, ok := pkg.Functions[values[0]]; ok {
+		add(f.Name, f.Examples)
+		return examples, true
+	}
+	return nil, false
+}
+
+// exampleAnchor returns the anchor of the example on the docs page. Examples are named like "Example" or
+// "Example (Suffix)" and their anchors look like "example-Client.Do-Suffix", or "example-package" for the package.
+func exampleAnchor(symbol string, name string) string {
+	if symbol == "" {
+		symbol = "package"
+	}
+	anchor := "example-" + symbol
+	if start, end := strings.Index(name, "("), strings.LastIndex(name, ")"); start != -1 && end > start {
+		anchor += "-" + strings.TrimSpace(name[start+1:end])
+	}
+	return anchor
+}
+
+// GetDocsExamplePages renders each example on its own page. Code which doesn't fit is cut off, keeping the code block
+// intact.
+func GetDocsExamplePages(examples []DocsExample) []string {
+	pages := make([]string, len(examples))
+	for i, example := range examples {
+		title := example.Symbol
+		if title == "" {
+			title = "package"
+		}
+		header := fmt.Sprintf("[**%s** %s](%s)\n", title, strings.TrimSpace(example.Name), example.URL)
+		var output string
+		if example.Output != "" {
+			output = fmt.Sprintf("Output:\n```\n%s\n```", common.Truncate(strings.TrimSpace(example.Output), exampleOutputLength))
+		}
+		codeLength := common.Limits.EmbedDescriptionLength - len(header) - len(output) - len("```go\n\n```\n")
+		pages[i] = header + "```go\n" + common.Truncate(strings.TrimSpace(example.Code), codeLength) + "\n```\n" + output
+	}
+	return pages
+}
@@ -0,0 +1,162 @@
+package butler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/hhhapz/doc"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	findDocsConcurrency = 4
+	findDocsMaxPackages = 50
+	findDocsMaxResults  = 100
+)
+
+var defaultStdlibPackages = []string{
+	"bytes", "context", "encoding/json", "errors", "fmt", "io", "net/http", "os", "sort", "strconv", "strings", "sync", "time",
+}
+
+// DocsFindResult are the symbols matching a query in a single package.
+type DocsFindResult struct {
+	Package doc.Package
+	Symbols []string
+}
+
+// FindDocs searches all alias modules, their subpackages and optionally the standard library for symbols matching the query.
+// At most findDocsMaxResults symbols are returned, truncated reports whether more were found.
+func (b *Butler) FindDocs(ctx context.Context, query string, stdlib bool) (results []DocsFindResult, truncated bool) {
+	seen := map[string]struct{}{}
+	var modules []string
+	for _, module := range b.Aliases(nil) {
+		if _, ok := seen[module]; !ok {
+			seen[module] = struct{}{}
+			modules = append(modules, module)
+		}
+	}
+	packages := b.searchPackages(ctx, modules)
+
+	// fan out a second time for the subpackages of the modules which are not configured themselves
+	var subpackages []string
+	for _, pkg := range packages {
+		for _, subpackage := range pkg.Subpackages {
+			if _, ok := seen[subpackage]; !ok && len(seen) < findDocsMaxPackages {
+				seen[subpackage] = struct{}{}
+				subpackages = append(subpackages, subpackage)
+			}
+		}
+	}
+	if stdlib {
+		var stdlibPackages []string
+		b.ReadConfig(func(cfg Config) {
+			stdlibPackages = slices.Clone(cfg.Docs.StdlibPackages)
+		})
+		if len(stdlibPackages) == 0 {
+			stdlibPackages = defaultStdlibPackages
+		}
+		for _, pkg := range stdlibPackages {
+			if _, ok := seen[pkg]; !ok {
+				seen[pkg] = struct{}{}
+				subpackages = append(subpackages, pkg)
+			}
+		}
+	}
+	packages = append(packages, b.searchPackages(ctx, subpackages)...)
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].URL < packages[j].URL
+	})
+	var count int
+	for _, pkg := range packages {
+		symbols := common.MatchAutocomplete(packageSymbols(pkg), query)
+		if len(symbols) == 0 {
+			continue
+		}
+		if count+len(symbols) > findDocsMaxResults {
+			symbols = symbols[:findDocsMaxResults-count]
+			truncated = true
+		}
+		count += len(symbols)
+		results = append(results, DocsFindResult{Package: pkg, Symbols: symbols})
+		if truncated {
+			break
+		}
+	}
+	return results, truncated
+}
+
+// searchPackages looks up the given packages with at most findDocsConcurrency requests at once. Packages which fail to load are skipped.
+func (b *Butler) searchPackages(ctx context.Context, paths []string) []doc.Package {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		packages []doc.Package
+	)
+	sem := make(chan struct{}, findDocsConcurrency)
+	for _, path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			pkg, err := b.DocClient.Search(ctx, path)
+			if err != nil {
+				b.Logger.Debugf("failed to search package %s: %s", path, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			packages = append(packages, pkg)
+		}(path)
+	}
+	wg.Wait()
+	return packages
+}
+
+func packageSymbols(pkg doc.Package) []string {
+	var symbols []string
+	for _, t := range pkg.Types {
+		symbols = append(symbols, t.Name)
+		for _, f := range t.TypeFunctions {
+			symbols = append(symbols, f.Name)
+		}
+		for _, m := range t.Methods {
+			symbols = append(symbols, m.For+"."+m.Name)
+		}
+	}
+	for _, f := range pkg.Functions {
+		symbols = append(symbols, f.Name)
+	}
+	return symbols
+}
+
+// GetDocsFindPages renders the results of FindDocs grouped by package into pages.
+func (b *Butler) GetDocsFindPages(results []DocsFindResult) []string {
+	var (
+		pages   []string
+		curPage string
+	)
+	for _, result := range results {
+		header := fmt.Sprintf("**[%s](%s)**\n", result.Package.URL, b.PackageURL(result.Package.URL))
+		if len(curPage) > 0 {
+			header = "\n" + header
+		}
+		curPage += header
+		for _, symbol := range result.Symbols {
+			line := fmt.Sprintf("•[`%s`](%s)\n", symbol, b.PackageURL(result.Package.URL)+"#"+symbol)
+			if len(curPage)+len(line) > symbolsPageLength {
+				pages = append(pages, curPage)
+				curPage = fmt.Sprintf("**%s (continued)**\n", result.Package.URL)
+			}
+			curPage += line
+		}
+	}
+	if len(curPage) > 0 {
+		pages = append(pages, curPage)
+	}
+	return pages
+}
@@ -0,0 +1,68 @@
+package butler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/disgoorg/disgo-butler/db"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	DocsStyleDefault = "default"
+	// DocsStyleCompact shows only the first line of the signature and comment.
+	DocsStyleCompact = "compact"
+	// DocsStyleFull shows the whole comment.
+	DocsStyleFull = "full"
+)
+
+// DocsPrefs is how a user wants /docs results to be shown. The zero value is the default rendering.
+type DocsPrefs struct {
+	Style string
+	// Source shows the whole declaration instead of collapsing long ones.
+	Source bool
+	// Examples shows the examples of symbols.
+	Examples bool
+}
+
+// Expanded returns which parts of a docs result are expanded by default.
+func (p DocsPrefs) Expanded() (signature bool, comment bool, examples bool) {
+	return p.Source, p.Style == DocsStyleFull, p.Examples
+}
+
+// UserDocsPrefs caches the docs preferences of the users.
+type UserDocsPrefs struct {
+	cache dbCache[DocsPrefs]
+}
+
+// DocsPrefs returns the docs preferences of the user.
+func (b *Butler) DocsPrefs(userID snowflake.ID) (DocsPrefs, error) {
+	return b.UserDocsPrefs.cache.get(userID, func(ctx context.Context) (DocsPrefs, error) {
+		row, err := b.DB.GetDocsPrefs(ctx, userID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return DocsPrefs{}, err
+		}
+		return DocsPrefs{
+			Style:    row.Style,
+			Source:   row.Source,
+			Examples: row.Examples,
+		}, nil
+	})
+}
+
+// SetDocsPrefs persists the docs preferences of the user.
+func (b *Butler) SetDocsPrefs(userID snowflake.ID, prefs DocsPrefs) error {
+	if err := b.DB.SetDocsPrefs(db.DocsPrefs{
+		UserID:   userID,
+		Style:    prefs.Style,
+		Source:   prefs.Source,
+		Examples: prefs.Examples,
+	}); err != nil {
+		// the stored preferences are unknown now
+		b.UserDocsPrefs.cache.forget(userID)
+		return err
+	}
+	b.UserDocsPrefs.cache.set(userID, prefs)
+	return nil
+}
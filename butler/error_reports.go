@@ -0,0 +1,116 @@
+package butler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const defaultErrorReportCooldown = 10 * time.Minute
+
+var ErrErrorReported = common.NewUserError("this error has already been reported")
+
+type ErrorReportsConfig struct {
+	// ChannelID is the staff channel users can report internal errors to with the button below them. 0 disables it.
+	ChannelID snowflake.ID `json:"channel_id,omitempty" yaml:"channel_id,omitempty" toml:"channel_id,omitempty"`
+	// CooldownMinutes is how long a user has to wait between reports. Defaults to 10 minutes.
+	CooldownMinutes int `json:"cooldown_minutes,omitempty" yaml:"cooldown_minutes,omitempty" toml:"cooldown_minutes,omitempty"`
+}
+
+func (c ErrorReportsConfig) cooldown() time.Duration {
+	if c.CooldownMinutes <= 0 {
+		return defaultErrorReportCooldown
+	}
+	return time.Duration(c.CooldownMinutes) * time.Minute
+}
+
+// ErrorReports limits how often users can report errors and makes sure each error is only reported once.
+type ErrorReports struct {
+	mu          sync.Mutex
+	lastReports map[snowflake.ID]time.Time
+	reported    map[string]struct{}
+}
+
+// allow records the report of the user if they are not on cooldown and the error was not reported yet.
+func (r *ErrorReports) allow(userID snowflake.ID, reportID string, cooldown time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reported[reportID]; ok {
+		return ErrErrorReported
+	}
+	if next := r.lastReports[userID].Add(cooldown); time.Now().Before(next) {
+		return common.NewUserErrorf("you can report another error %s", common.Timestamp(next))
+	}
+	if r.lastReports == nil {
+		r.lastReports = map[snowflake.ID]time.Time{}
+		r.reported = map[string]struct{}{}
+	}
+	r.lastReports[userID] = time.Now()
+	r.reported[reportID] = struct{}{}
+	return nil
+}
+
+// forget undoes allow after the report could not be sent, so the user can try again.
+func (r *ErrorReports) forget(userID snowflake.ID, reportID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.lastReports, userID)
+	delete(r.reported, reportID)
+}
+
+// ErrorReportContext is where the reported error happened.
+type ErrorReportContext struct {
+	User      discord.User
+	GuildID   *snowflake.ID
+	ChannelID snowflake.ID
+	// Command is the name of the command which failed, if known.
+	Command string
+}
+
+// ReportError forwards the internal error to the configured staff channel. Users can report an error once per cooldown.
+func (b *Butler) ReportError(report common.ErrorReport, ctx ErrorReportContext) error {
+	var cfg ErrorReportsConfig
+	b.ReadConfig(func(config Config) {
+		cfg = config.ErrorReports
+	})
+	if cfg.ChannelID == 0 {
+		return common.NewUserError("error reports are disabled")
+	}
+	if err := b.ErrorReports.allow(ctx.User.ID, report.ID, cfg.cooldown()); err != nil {
+		return err
+	}
+
+	location := "DMs"
+	if ctx.GuildID != nil {
+		location = fmt.Sprintf("%s in guild `%s`", discord.ChannelMention(ctx.ChannelID), *ctx.GuildID)
+	}
+	fields := []discord.EmbedField{
+		{Name: "Error ID", Value: "`" + report.ID + "`"},
+		{Name: "User", Value: fmt.Sprintf("%s (`%s`)", discord.UserMention(ctx.User.ID), ctx.User.ID)},
+		{Name: "Where", Value: location},
+		{Name: "When", Value: common.Timestamp(report.At)},
+	}
+	if ctx.Command != "" {
+		fields = append(fields, discord.EmbedField{Name: "Command", Value: "`/" + ctx.Command + "`"})
+	}
+	fields = append(fields, discord.EmbedField{
+		Name:  "Error",
+		Value: "```\n" + common.Truncate(report.Err, common.Limits.EmbedFieldLength-8) + "\n```",
+	})
+	_, err := b.Client.Rest().CreateMessage(cfg.ChannelID, discord.MessageCreate{
+		Embeds: []discord.Embed{{
+			Title:  "Error report",
+			Fields: fields,
+			Color:  common.ColorError,
+		}},
+		AllowedMentions: &discord.AllowedMentions{},
+	})
+	if err != nil {
+		b.ErrorReports.forget(ctx.User.ID, report.ID)
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/google/go-github/v44/github"
+)
+
+// GithubUser returns the public profile of the GitHub user.
+func (b *Butler) GithubUser(ctx context.Context, login string) (*github.User, error) {
+	user, _, err := b.GitHubClient.Users.Get(ctx, login)
+	if err != nil {
+		return nil, githubError(err, fmt.Sprintf("GitHub user `%s` not found", login))
+	}
+	return user, nil
+}
+
+// GithubRepo returns the public GitHub repository with the given full name in the form of owner/repo.
+func (b *Butler) GithubRepo(ctx context.Context, fullName string) (*github.Repository, error) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || name == "" {
+		return nil, common.NewUserErrorf("`%s` is not a repository, use the form `owner/repo`", fullName)
+	}
+	repo, _, err := b.GitHubClient.Repositories.Get(ctx, owner, name)
+	if err != nil {
+		return nil, githubError(err, fmt.Sprintf("GitHub repository `%s` not found", fullName))
+	}
+	// don't leak private repositories the bot might have access to
+	if repo.GetPrivate() {
+		return nil, common.NewUserErrorf("GitHub repository `%s` not found", fullName)
+	}
+	return repo, nil
+}
+
+// LatestGithubRelease returns the latest release of the repository or nil if it has none.
+func (b *Butler) LatestGithubRelease(ctx context.Context, repo *github.Repository) (*github.RepositoryRelease, error) {
+	release, response, err := b.GitHubClient.Repositories.GetLatestRelease(ctx, repo.GetOwner().GetLogin(), repo.GetName())
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		release, err = nil, nil
+	} else if err != nil {
+		return nil, githubError(err, "")
+	}
+	return release, nil
+}
+
+// SearchGithubUsers returns the logins of up to 25 GitHub users matching the query.
+func (b *Butler) SearchGithubUsers(ctx context.Context, query string) ([]string, error) {
+	result, _, err := b.GitHubClient.Search.Users(ctx, query, &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 25}})
+	if err != nil {
+		return nil, githubError(err, "")
+	}
+	logins := make([]string, 0, len(result.Users))
+	for _, user := range result.Users {
+		logins = append(logins, user.GetLogin())
+	}
+	return logins, nil
+}
+
+// SearchGithubIssues returns up to 100 issues and pull requests matching the query in the given repositories in the form
+// of owner/repo, and the total amount of matches. The search rate limit is much lower than the core one, so searches
+// fail early while it is exhausted.
+func (b *Butler) SearchGithubIssues(ctx context.Context, query string, repos []string) ([]*github.Issue, int, error) {
+	repos = append([]string(nil), repos...)
+	sort.Strings(repos)
+	for _, repo := range repos {
+		query += " repo:" + repo
+	}
+	if bucket, ok := b.RateLimits.GithubBucket("search"); ok && bucket.Remaining == 0 && time.Now().Before(bucket.ResetAt) {
+		return nil, 0, common.NewUserErrorf("the GitHub search rate limit has been reached, try again %s", common.Timestamp(bucket.ResetAt))
+	}
+	result, _, err := b.GitHubClient.Search.Issues(ctx, query, &github.SearchOptions{
+		Sort:        "updated",
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		var responseErr *github.ErrorResponse
+		if errors.As(err, &responseErr) && responseErr.Response.StatusCode == http.StatusUnprocessableEntity {
+			return nil, 0, common.NewUserErrorf("invalid search query: %s", responseErr.Message)
+		}
+		return nil, 0, githubError(err, "")
+	}
+	return result.Issues, result.GetTotal(), nil
+}
+
+// OrgRepos returns the public repositories of the GitHub organization. All pages are fetched, which fails early while
+// the rate limit is exhausted instead of waiting for it to reset.
+func (b *Butler) OrgRepos(ctx context.Context, org string) ([]*github.Repository, error) {
+	var (
+		repos []*github.Repository
+		opts  = &github.RepositoryListByOrgOptions{Type: "public", ListOptions: github.ListOptions{PerPage: 100}}
+	)
+	for {
+		if bucket, ok := b.RateLimits.GithubBucket("core"); ok && bucket.Remaining == 0 && time.Now().Before(bucket.ResetAt) {
+			return nil, common.NewUserErrorf("the GitHub rate limit has been reached, try again %s", common.Timestamp(bucket.ResetAt))
+		}
+		page, rs, err := b.GitHubClient.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, githubError(err, fmt.Sprintf("GitHub organization `%s` not found", org))
+		}
+		repos = append(repos, page...)
+		if rs.NextPage == 0 {
+			return repos, nil
+		}
+		opts.Page = rs.NextPage
+	}
+}
+
+// githubError turns not found and rate limit errors into user errors.
+func githubError(err error, notFound string) error {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return common.NewUserErrorf("the GitHub rate limit has been reached, try again %s", common.Timestamp(rateLimitErr.Rate.Reset.Time))
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return common.NewUserError("the GitHub rate limit has been reached, try again later")
+	}
+	var responseErr *github.ErrorResponse
+	if notFound != "" && errors.As(err, &responseErr) && responseErr.Response.StatusCode == http.StatusNotFound {
+		return common.NewUserError(notFound)
+	}
+	return err
+}
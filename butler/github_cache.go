@@ -0,0 +1,201 @@
+package butler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultGithubCacheTTL = 5 * time.Minute
+	// githubCacheSize is how many responses are kept at most. Expired ones are dropped first.
+	githubCacheSize = 1000
+)
+
+// GithubCache caches the responses of GitHub lookups for a short time to spare the rate limit. It sits between the
+// GitHub client and the network, so all commands share it. Once a response expired it is revalidated with its ETag,
+// which doesn't count against the rate limit if it didn't change. The zero value caches for DefaultGithubCacheTTL.
+type GithubCache struct {
+	// TTL is how long responses are used without asking GitHub. A negative TTL disables the cache.
+	TTL time.Duration
+
+	mu          sync.Mutex
+	entries     map[string]*githubCacheEntry
+	hits        int
+	revalidated int
+	misses      int
+}
+
+type githubCacheEntry struct {
+	url      *url.URL
+	response *http.Response
+	body     []byte
+	storedAt time.Time
+}
+
+// GithubCacheStats are the counters of the GithubCache since the start.
+type GithubCacheStats struct {
+	Entries int `json:"entries"`
+	// Hits are the lookups answered without asking GitHub.
+	Hits int `json:"hits"`
+	// Revalidated are the lookups GitHub confirmed to be unchanged.
+	Revalidated int `json:"revalidated"`
+	Misses      int `json:"misses"`
+}
+
+// HitRate returns the share of lookups which didn't use the rate limit.
+func (s GithubCacheStats) HitRate() float64 {
+	total := s.Hits + s.Revalidated + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.Revalidated) / float64(total)
+}
+
+// Transport wraps the given transport to answer GET requests from the cache.
+func (c *GithubCache) Transport(next http.RoundTripper) http.RoundTripper {
+	return githubCacheTransport{cache: c, next: next}
+}
+
+type githubCacheTransport struct {
+	cache *GithubCache
+	next  http.RoundTripper
+}
+
+func (t githubCacheTransport) RoundTrip(rq *http.Request) (*http.Response, error) {
+	ttl := t.cache.ttl()
+	if rq.Method != http.MethodGet || ttl < 0 {
+		return t.next.RoundTrip(rq)
+	}
+	key := rq.URL.String() + " " + rq.Header.Get("Accept")
+
+	entry, ok := t.cache.get(key)
+	if ok && time.Since(entry.storedAt) < ttl {
+		t.cache.count(&t.cache.hits)
+		return entry.cachedResponse(rq), nil
+	}
+	if ok {
+		if etag := entry.response.Header.Get("ETag"); etag != "" {
+			rq = rq.Clone(rq.Context())
+			rq.Header.Set("If-None-Match", etag)
+		}
+	}
+
+	rs, err := t.next.RoundTrip(rq)
+	if err != nil {
+		return nil, err
+	}
+	if ok && rs.StatusCode == http.StatusNotModified {
+		_ = rs.Body.Close()
+		t.cache.count(&t.cache.revalidated)
+		t.cache.set(key, &githubCacheEntry{url: entry.url, response: entry.response, body: entry.body, storedAt: time.Now()})
+		return entry.cachedResponse(rq), nil
+	}
+	t.cache.count(&t.cache.misses)
+	// missing repositories and releases are looked up as often as existing ones
+	if rs.StatusCode != http.StatusOK && rs.StatusCode != http.StatusNotFound {
+		return rs, nil
+	}
+	body, err := io.ReadAll(rs.Body)
+	_ = rs.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rs.Body = io.NopCloser(bytes.NewReader(body))
+	stored := *rs
+	stored.Body = nil
+	t.cache.set(key, &githubCacheEntry{url: rq.URL, response: &stored, body: body, storedAt: time.Now()})
+	return rs, nil
+}
+
+// cachedResponse returns a copy of the stored response for the request.
+func (e *githubCacheEntry) cachedResponse(rq *http.Request) *http.Response {
+	rs := *e.response
+	rs.Header = e.response.Header.Clone()
+	rs.Body = io.NopCloser(bytes.NewReader(e.body))
+	rs.Request = rq
+	return &rs
+}
+
+// Invalidate drops the cached responses about the repository in the form of owner/repo, including searches in it.
+func (c *GithubCache) Invalidate(fullName string) {
+	fullName = strings.ToLower(fullName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		path := strings.ToLower(entry.url.Path) + "/"
+		query := strings.ToLower(entry.url.Query().Get("q"))
+		if strings.Contains(path, "/repos/"+fullName+"/") || strings.Contains(query, "repo:"+fullName) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Stats returns the counters of the cache.
+func (c *GithubCache) Stats() GithubCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return GithubCacheStats{
+		Entries:     len(c.entries),
+		Hits:        c.hits,
+		Revalidated: c.revalidated,
+		Misses:      c.misses,
+	}
+}
+
+func (c *GithubCache) ttl() time.Duration {
+	if c.TTL == 0 {
+		return DefaultGithubCacheTTL
+	}
+	return c.TTL
+}
+
+func (c *GithubCache) get(key string) (*githubCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *GithubCache) set(key string, entry *githubCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]*githubCacheEntry{}
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= githubCacheSize {
+		c.evict()
+	}
+	c.entries[key] = entry
+}
+
+// evict drops the expired entries which can't be revalidated, or the oldest entry if there are none. mu must be held.
+func (c *GithubCache) evict() {
+	ttl := c.ttl()
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for key, entry := range c.entries {
+		if time.Since(entry.storedAt) >= ttl && entry.response.Header.Get("ETag") == "" {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.storedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.storedAt
+		}
+	}
+	if len(c.entries) >= githubCacheSize {
+		delete(c.entries, oldestKey)
+	}
+}
+
+func (c *GithubCache) count(counter *int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*counter++
+}
@@ -0,0 +1,51 @@
+package butler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v44/github"
+)
+
+// GithubEnterpriseConfig points the GitHub client at a GitHub Enterprise Server instead of the public API.
+type GithubEnterpriseConfig struct {
+	// BaseURL is the API URL of the server, e.g. https://github.example.com/api/v3/. Empty uses public GitHub.
+	BaseURL string `json:"base_url" yaml:"base_url" toml:"base_url"`
+	// UploadURL is the upload URL of the server, e.g. https://github.example.com/api/uploads/. Defaults to BaseURL.
+	UploadURL string `json:"upload_url" yaml:"upload_url" toml:"upload_url"`
+}
+
+func (c GithubEnterpriseConfig) validate() error {
+	if c.BaseURL == "" {
+		if c.UploadURL != "" {
+			return fmt.Errorf("github_enterprise.upload_url requires github_enterprise.base_url to be set")
+		}
+		return nil
+	}
+	for name, rawURL := range map[string]string{"base_url": c.BaseURL, "upload_url": c.UploadURL} {
+		if rawURL == "" {
+			continue
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid github_enterprise.%s: %w", name, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid github_enterprise.%s %q: must be an absolute http(s) URL", name, rawURL)
+		}
+	}
+	return nil
+}
+
+// newGithubClient returns a client for public GitHub or the configured GitHub Enterprise Server.
+func newGithubClient(httpClient *http.Client, config GithubEnterpriseConfig) (*github.Client, error) {
+	if config.BaseURL == "" {
+		return github.NewClient(httpClient), nil
+	}
+	uploadURL := config.UploadURL
+	if uploadURL == "" {
+		uploadURL = config.BaseURL
+	}
+	return github.NewEnterpriseClient(config.BaseURL, uploadURL, httpClient)
+}
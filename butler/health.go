@@ -0,0 +1,137 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDiscordUnavailable is returned by background jobs which stopped because the Discord API is unavailable.
+var ErrDiscordUnavailable = errors.New("discord api unavailable")
+
+const healthCheckInterval = time.Minute
+
+type HealthStatus struct {
+	DiscordDegraded      bool
+	DiscordDegradedSince time.Time
+	DiscordErr           error
+
+	DatabaseDegraded      bool
+	DatabaseDegradedSince time.Time
+	DatabaseErr           error
+}
+
+func (s HealthStatus) Degraded() bool {
+	return s.DiscordDegraded || s.DatabaseDegraded
+}
+
+// Health tracks whether background jobs are currently seeing a Discord API outage and whether the database is reachable.
+type Health struct {
+	mu       sync.Mutex
+	status   HealthStatus
+	onChange func(status HealthStatus)
+}
+
+func (h *Health) Status() HealthStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// OnChange sets a function which is called whenever the overall degraded state changes.
+func (h *Health) OnChange(onChange func(status HealthStatus)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onChange = onChange
+}
+
+// update applies the change to the status and calls onChange if the overall degraded state changed.
+func (h *Health) update(change func(status *HealthStatus)) {
+	h.mu.Lock()
+	wasDegraded := h.status.Degraded()
+	change(&h.status)
+	status := h.status
+	onChange := h.onChange
+	h.mu.Unlock()
+
+	if onChange != nil && wasDegraded != status.Degraded() {
+		onChange(status)
+	}
+}
+
+// ReportDiscordError marks the Discord API as degraded and returns true if it was not degraded before.
+func (h *Health) ReportDiscordError(err error) bool {
+	var changed bool
+	h.update(func(status *HealthStatus) {
+		status.DiscordErr = err
+		if status.DiscordDegraded {
+			return
+		}
+		changed = true
+		status.DiscordDegraded = true
+		status.DiscordDegradedSince = time.Now()
+	})
+	return changed
+}
+
+// ReportDiscordOK marks the Discord API as healthy and returns true if it was degraded before.
+func (h *Health) ReportDiscordOK() bool {
+	var changed bool
+	h.update(func(status *HealthStatus) {
+		changed = status.DiscordDegraded
+		status.DiscordDegraded = false
+		status.DiscordDegradedSince = time.Time{}
+		status.DiscordErr = nil
+	})
+	return changed
+}
+
+// ReportDatabaseError marks the database as degraded and returns true if it was not degraded before.
+func (h *Health) ReportDatabaseError(err error) bool {
+	var changed bool
+	h.update(func(status *HealthStatus) {
+		status.DatabaseErr = err
+		if status.DatabaseDegraded {
+			return
+		}
+		changed = true
+		status.DatabaseDegraded = true
+		status.DatabaseDegradedSince = time.Now()
+	})
+	return changed
+}
+
+// ReportDatabaseOK marks the database as healthy and returns true if it was degraded before.
+func (h *Health) ReportDatabaseOK() bool {
+	var changed bool
+	h.update(func(status *HealthStatus) {
+		changed = status.DatabaseDegraded
+		status.DatabaseDegraded = false
+		status.DatabaseDegradedSince = time.Time{}
+		status.DatabaseErr = nil
+	})
+	return changed
+}
+
+// healthCheckJob periodically checks whether the database is reachable.
+func (b *Butler) healthCheckJob() Job {
+	return Job{
+		Name:     "health-check",
+		Interval: healthCheckInterval,
+		Run: func(ctx context.Context) error {
+			pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			if err := b.DB.Ping(pingCtx); err != nil {
+				if b.Health.ReportDatabaseError(err) {
+					b.Logger.Warnf("Database seems to be unavailable: %s", err)
+				}
+				return err
+			}
+			if b.Health.ReportDatabaseOK() {
+				b.Logger.Info("Database recovered")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package butler
+
+import (
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+)
+
+// The message content application flags are not known to disgo yet.
+const (
+	applicationFlagGatewayMessageContent        discord.ApplicationFlags = 1 << 18
+	applicationFlagGatewayMessageContentLimited discord.ApplicationFlags = 1 << 19
+)
+
+// Intents returns the gateway intents the enabled features need.
+func (b *Butler) Intents() gateway.Intents {
+	var intents gateway.Intents
+	modMail, messageContent := b.intentFeatures()
+	if modMail {
+		intents = intents.Add(gateway.IntentGuildMessages, gateway.IntentDirectMessages, gateway.IntentGuildMessageTyping, gateway.IntentDirectMessageTyping)
+	}
+	if messageContent {
+		// text commands are always available with the message content intent
+		intents = intents.Add(gateway.IntentGuildMessages, gateway.IntentMessageContent)
+	}
+	return intents
+}
+
+// logDisabledFeatures logs the features which don't work without the message content intent.
+func (b *Butler) logDisabledFeatures() {
+	modMail, messageContent := b.intentFeatures()
+	if messageContent {
+		return
+	}
+	disabled := []string{"text commands"}
+	if modMail {
+		disabled = append(disabled, "mod mail replies of staff which don't mention the bot")
+	}
+	b.Logger.Warnf("Running without the message content intent, disabled features: %s", strings.Join(disabled, ", "))
+}
+
+// intentFeatures reports whether mod mail is set up and whether the message content intent is enabled.
+func (b *Butler) intentFeatures() (modMail bool, messageContent bool) {
+	b.ReadConfig(func(cfg Config) {
+		modMail = len(cfg.ModMail.Guilds) > 0
+		messageContent = !cfg.DisableMessageContentIntent
+	})
+	return
+}
+
+// checkPrivilegedIntents warns about privileged intents which are needed but not granted to the application. Discord
+// refuses the gateway connection in that case.
+func (b *Butler) checkPrivilegedIntents(intents gateway.Intents) {
+	if !intents.Has(gateway.IntentMessageContent) {
+		return
+	}
+	application, err := b.Client.Rest().GetBotApplicationInfo()
+	if err != nil {
+		b.Logger.Warnf("Failed to check the privileged intents of the application: %s", err)
+		return
+	}
+	if application.Flags.Missing(applicationFlagGatewayMessageContent) && application.Flags.Missing(applicationFlagGatewayMessageContentLimited) {
+		b.Logger.Error("The message content intent is not enabled for this application. Enable it in the developer portal or set disable_message_content_intent to run without it.")
+	}
+}
@@ -0,0 +1,360 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/log"
+)
+
+const (
+	cachePersistInterval = 15 * time.Minute
+	// jobErrorsKept is how many of the latest errors are kept per job.
+	jobErrorsKept = 20
+	// defaultMinJobInterval is the shortest interval SetInterval accepts for jobs without a MinInterval.
+	defaultMinJobInterval = 30 * time.Second
+)
+
+var (
+	ErrJobNotFound = common.NewUserError("job not found")
+	ErrJobRunning  = common.NewUserError("job is already running")
+)
+
+// Job is a background task which runs periodically until the bot shuts down.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	// MinInterval is the shortest interval the job can be set to at runtime. Defaults to 30 seconds.
+	MinInterval time.Duration
+	// NextDelay optionally replaces the Interval before every wait with a delay based on the current interval and the
+	// error of the last run, e.g. to back off or add jitter. err is nil before the first run.
+	NextDelay func(interval time.Duration, err error) time.Duration
+	Run       func(ctx context.Context) error
+}
+
+type JobStatus struct {
+	Name         string
+	Interval     time.Duration
+	Running      bool
+	Runs         int
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastErr      error
+	NextRun      time.Time
+	// Errors is the amount of kept errors, see Jobs.Errors.
+	Errors int
+}
+
+// JobError is a failed run of a job.
+type JobError struct {
+	At  time.Time
+	Err error
+}
+
+// Jobs runs the registered jobs and keeps track of their status.
+type Jobs struct {
+	mu     sync.Mutex
+	jobs   map[string]*job
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+type job struct {
+	Job
+	trigger chan struct{}
+	// reschedule wakes the waiting job to wait until the NextRun of its status instead
+	reschedule chan struct{}
+	// waiting is set while the job waits for its next run, since waitStart
+	waiting   bool
+	waitStart time.Time
+	status    JobStatus
+	// errors are the latest errors of the job, the oldest first
+	errors []JobError
+}
+
+// Add registers the job. Jobs added after Start are not run.
+func (j *Jobs) Add(newJob Job) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.jobs == nil {
+		j.jobs = map[string]*job{}
+	}
+	j.jobs[newJob.Name] = &job{
+		Job:        newJob,
+		trigger:    make(chan struct{}, 1),
+		reschedule: make(chan struct{}, 1),
+		status: JobStatus{
+			Name:     newJob.Name,
+			Interval: newJob.Interval,
+		},
+	}
+}
+
+// Start runs all registered jobs until Stop is called. Repeated failures are logged through logs.
+func (j *Jobs) Start(logger log.Logger, logs *common.LogLimiter) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cancel = cancel
+	for _, registered := range j.jobs {
+		j.wg.Add(1)
+		go j.run(ctx, logger, logs, registered)
+	}
+}
+
+// Stop cancels all jobs and waits for the running ones to return.
+func (j *Jobs) Stop() {
+	j.mu.Lock()
+	cancel := j.cancel
+	j.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	j.wg.Wait()
+}
+
+// Trigger runs the job now instead of waiting for its next run.
+func (j *Jobs) Trigger(name string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	registered, ok := j.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if registered.status.Running {
+		return ErrJobRunning
+	}
+	select {
+	case registered.trigger <- struct{}{}:
+	default:
+		// already triggered
+	}
+	return nil
+}
+
+// SetInterval changes the interval of the job. A waiting job is rescheduled as if it had waited for the new interval
+// since its last run, so it runs right away if that time already passed. The returned status has the new next run.
+func (j *Jobs) SetInterval(name string, interval time.Duration) (JobStatus, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	registered, ok := j.jobs[name]
+	if !ok {
+		return JobStatus{}, ErrJobNotFound
+	}
+	minInterval := registered.MinInterval
+	if minInterval == 0 {
+		minInterval = defaultMinJobInterval
+	}
+	if interval < minInterval {
+		return JobStatus{}, common.NewUserErrorf("the interval of %s must be at least %s", name, minInterval)
+	}
+	registered.Interval = interval
+	registered.status.Interval = interval
+	if registered.waiting {
+		registered.status.NextRun = registered.waitStart.Add(registered.delay(registered.status.LastErr))
+		select {
+		case registered.reschedule <- struct{}{}:
+		default:
+			// already rescheduled
+		}
+	}
+	return registered.status, nil
+}
+
+// delay returns how long to wait before the next run. j.mu must be held.
+func (registered *job) delay(err error) time.Duration {
+	if registered.NextDelay != nil {
+		return registered.NextDelay(registered.Interval, err)
+	}
+	return registered.Interval
+}
+
+// Statuses returns the status of all jobs ordered by name.
+func (j *Jobs) Statuses() []JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	statuses := make([]JobStatus, 0, len(j.jobs))
+	for _, registered := range j.jobs {
+		statuses = append(statuses, registered.status)
+	}
+	sort.Slice(statuses, func(i, k int) bool {
+		return statuses[i].Name < statuses[k].Name
+	})
+	return statuses
+}
+
+// Errors returns the latest errors of the job, the newest first.
+func (j *Jobs) Errors(name string) ([]JobError, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	registered, ok := j.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	errs := make([]JobError, len(registered.errors))
+	for i, jobErr := range registered.errors {
+		errs[len(errs)-1-i] = jobErr
+	}
+	return errs, nil
+}
+
+// ClearErrors forgets the kept errors of the job and returns how many there were.
+func (j *Jobs) ClearErrors(name string) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	registered, ok := j.jobs[name]
+	if !ok {
+		return 0, ErrJobNotFound
+	}
+	cleared := len(registered.errors)
+	registered.errors = nil
+	registered.status.Errors = 0
+	return cleared, nil
+}
+
+// Names returns the names of all jobs.
+func (j *Jobs) Names() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return sortedKeys(j.jobs)
+}
+
+func (j *Jobs) run(ctx context.Context, logger log.Logger, logs *common.LogLimiter, registered *job) {
+	defer j.wg.Done()
+	var err error
+	for {
+		j.mu.Lock()
+		delay := registered.delay(err)
+		registered.waiting = true
+		registered.waitStart = time.Now()
+		registered.status.NextRun = registered.waitStart.Add(delay)
+		j.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-registered.trigger:
+				timer.Stop()
+				break wait
+			case <-registered.reschedule:
+				j.mu.Lock()
+				nextRun := registered.status.NextRun
+				j.mu.Unlock()
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(time.Until(nextRun))
+			case <-timer.C:
+				break wait
+			}
+		}
+
+		j.mu.Lock()
+		registered.waiting = false
+		registered.status.Running = true
+		j.mu.Unlock()
+
+		start := time.Now()
+		err = j.runSafe(ctx, registered)
+		// outages are already reported by the jobs through the Health
+		if err != nil && ctx.Err() == nil && !errors.Is(err, ErrDiscordUnavailable) {
+			logs.Errorf(logger, "Job %s failed: %s", registered.Name, err)
+		}
+
+		j.mu.Lock()
+		registered.status.Running = false
+		registered.status.Runs++
+		registered.status.LastRun = start
+		registered.status.LastDuration = time.Since(start)
+		registered.status.LastErr = err
+		// runs cancelled by the shutdown didn't fail
+		if err != nil && ctx.Err() == nil {
+			registered.errors = append(registered.errors, JobError{At: start, Err: err})
+			if len(registered.errors) > jobErrorsKept {
+				registered.errors = registered.errors[len(registered.errors)-jobErrorsKept:]
+			}
+			registered.status.Errors = len(registered.errors)
+		}
+		j.mu.Unlock()
+	}
+}
+
+// RegisterJobs adds all background jobs of the bot which are enabled in the config.
+func (b *Butler) RegisterJobs() {
+	var contributorSync, cachePersist bool
+	b.ReadConfig(func(cfg Config) {
+		contributorSync = cfg.ContributorSync.IntervalMinutes > 0
+		cachePersist = cfg.Docs.CacheFile != "" || cfg.ComponentStateFile != ""
+	})
+	b.addJob(b.healthCheckJob())
+	if contributorSync {
+		b.addJob(b.contributorSyncJob())
+	}
+	b.addJob(b.releaseDigestJob())
+	if cachePersist {
+		b.addJob(b.cachePersistJob())
+	}
+}
+
+// addJob adds the job with the interval set through SetJobInterval, if any.
+func (b *Butler) addJob(newJob Job) {
+	var seconds int
+	b.ReadConfig(func(cfg Config) {
+		seconds = cfg.JobIntervalSeconds[newJob.Name]
+	})
+	if seconds > 0 {
+		newJob.Interval = time.Duration(seconds) * time.Second
+	}
+	b.Jobs.Add(newJob)
+}
+
+// SetJobInterval changes the interval of the running job and saves it in the config, so it is kept across restarts.
+// See Jobs.SetInterval.
+func (b *Butler) SetJobInterval(name string, interval time.Duration) (JobStatus, error) {
+	status, err := b.Jobs.SetInterval(name, interval)
+	if err != nil {
+		return status, err
+	}
+	return status, b.UpdateConfig(func(cfg *Config) {
+		if cfg.JobIntervalSeconds == nil {
+			cfg.JobIntervalSeconds = map[string]int{}
+		}
+		cfg.JobIntervalSeconds[name] = int(interval / time.Second)
+	})
+}
+
+// cachePersistJob periodically saves the doc cache and component states so they survive crashes.
+func (b *Butler) cachePersistJob() Job {
+	return Job{
+		Name:     "cache-persist",
+		Interval: cachePersistInterval,
+		Run: func(context.Context) error {
+			if err := b.SaveDocCache(); err != nil {
+				return fmt.Errorf("failed to save doc cache: %w", err)
+			}
+			if err := b.SaveComponentStates(); err != nil {
+				return fmt.Errorf("failed to save component states: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// runSafe runs the job and turns panics into errors so a single run can't stop the job.
+func (j *Jobs) runSafe(ctx context.Context, registered *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return registered.Run(ctx)
+}
@@ -0,0 +1,31 @@
+package butler
+
+import (
+	"errors"
+
+	"github.com/disgoorg/log"
+)
+
+type levelSetter interface {
+	SetLevel(level log.Level)
+}
+
+// LogLevel returns the level the logger currently outputs at.
+func (b *Butler) LogLevel() log.Level {
+	b.logLevelMu.Lock()
+	defer b.logLevelMu.Unlock()
+	return b.logLevel
+}
+
+// SetLogLevel changes the level of the logger until the next restart.
+func (b *Butler) SetLogLevel(level log.Level) error {
+	logger, ok := b.Logger.(levelSetter)
+	if !ok {
+		return errors.New("the logger does not support changing its level")
+	}
+	b.logLevelMu.Lock()
+	defer b.logLevelMu.Unlock()
+	logger.SetLevel(level)
+	b.logLevel = level
+	return nil
+}
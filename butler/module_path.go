@@ -0,0 +1,88 @@
+package butler
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/common"
+)
+
+// defaultDocSites are the docs sites whose links are accepted in place of a module path.
+var defaultDocSites = []string{"pkg.go.dev", "godocs.io", "godoc.org"}
+
+// NormalizeModule turns the module path or docs link the user entered into the canonical module path, e.g.
+// "https://pkg.go.dev/github.com/x/y/" into "github.com/x/y". Links of the DocSites and the doc Sources are accepted.
+// Versions are dropped as the docs are always searched at the latest version. Invalid paths return a user error.
+func (c DocsConfig) NormalizeModule(module string) (string, error) {
+	normalized := strings.TrimSpace(module)
+	if i := strings.IndexAny(normalized, "?#"); i != -1 {
+		normalized = normalized[:i]
+	}
+	for _, scheme := range []string{"https://", "http://"} {
+		normalized, _ = cutPrefixFold(normalized, scheme)
+	}
+	normalized = strings.TrimSuffix(normalized, "/")
+
+	for _, site := range c.docSites() {
+		if rest, ok := cutPrefixFold(normalized, site+"/"); ok {
+			normalized = rest
+			break
+		}
+	}
+	// github.com/x/y@v1.0.0/z -> github.com/x/y/z
+	if i := strings.IndexByte(normalized, '@'); i != -1 {
+		end := strings.IndexByte(normalized[i:], '/')
+		if end == -1 {
+			normalized = normalized[:i]
+		} else {
+			normalized = normalized[:i] + normalized[i+end:]
+		}
+	}
+
+	if !validModulePath(normalized) {
+		return "", common.NewUserErrorf("`%s` is not a valid module path", module)
+	}
+	return normalized, nil
+}
+
+// docSites returns the hosts and paths of all docs sites without scheme and trailing slash.
+func (c DocsConfig) docSites() []string {
+	sites := append([]string(nil), defaultDocSites...)
+	sites = append(sites, c.DocSites...)
+	for _, source := range c.Sources {
+		sites = append(sites, source.URL, source.linkURL())
+	}
+	for i, site := range sites {
+		if parsed, err := url.Parse(site); err == nil && parsed.Host != "" {
+			site = parsed.Host + parsed.Path
+		}
+		sites[i] = strings.TrimSuffix(site, "/")
+	}
+	return sites
+}
+
+func cutPrefixFold(s string, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// validModulePath reports whether the path consists of non-empty elements of the characters allowed in import paths.
+// Single element paths with a dot like "pkg.go.dev" are hosts without a module, unlike standard library packages.
+func validModulePath(path string) bool {
+	if path == "" || (strings.Contains(path, ".") && !strings.Contains(path, "/")) {
+		return false
+	}
+	for _, element := range strings.Split(path, "/") {
+		if element == "" || element == "." || element == ".." {
+			return false
+		}
+		for _, r := range element {
+			if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || strings.ContainsRune("-._~+", r)) {
+				return false
+			}
+		}
+	}
+	return true
+}
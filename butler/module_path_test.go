@@ -0,0 +1,57 @@
+package butler
+
+import (
+	"testing"
+
+	"github.com/disgoorg/disgo-butler/common"
+)
+
+func TestDocsConfigNormalizeModule(t *testing.T) {
+	cfg := DocsConfig{
+		DocSites: []string{"https://docs.example.com/go/"},
+		Sources: []DocSourceConfig{
+			{Name: "internal", URL: "http://godocs.internal:8080/", LinkURL: "https://godocs.example.com"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		module  string
+		want    string
+		wantErr bool
+	}{
+		{name: "module path", module: "github.com/disgoorg/disgo", want: "github.com/disgoorg/disgo"},
+		{name: "surrounding spaces and trailing slash", module: "  github.com/disgoorg/disgo/ ", want: "github.com/disgoorg/disgo"},
+		{name: "standard library", module: "net/http", want: "net/http"},
+		{name: "pkg.go.dev link", module: "https://pkg.go.dev/github.com/disgoorg/disgo/", want: "github.com/disgoorg/disgo"},
+		{name: "link without scheme", module: "pkg.go.dev/github.com/disgoorg/disgo", want: "github.com/disgoorg/disgo"},
+		{name: "scheme and site are case insensitive", module: "HTTPS://Pkg.Go.Dev/github.com/disgoorg/disgo", want: "github.com/disgoorg/disgo"},
+		{name: "query and fragment", module: "https://godocs.io/github.com/disgoorg/disgo?tab=doc#Client", want: "github.com/disgoorg/disgo"},
+		{name: "version", module: "github.com/disgoorg/disgo@v0.13.5", want: "github.com/disgoorg/disgo"},
+		{name: "version with package", module: "https://pkg.go.dev/github.com/disgoorg/disgo@v0.13.5/discord", want: "github.com/disgoorg/disgo/discord"},
+		{name: "configured docs site", module: "https://docs.example.com/go/github.com/disgoorg/disgo", want: "github.com/disgoorg/disgo"},
+		{name: "doc source URL", module: "http://godocs.internal:8080/git.example.com/team/lib", want: "git.example.com/team/lib"},
+		{name: "doc source link URL", module: "https://godocs.example.com/git.example.com/team/lib", want: "git.example.com/team/lib"},
+		{name: "empty", module: " ", wantErr: true},
+		{name: "site without module", module: "https://pkg.go.dev/", wantErr: true},
+		{name: "invalid characters", module: "github.com/disgoorg/dis go", wantErr: true},
+		{name: "relative element", module: "github.com/../disgo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.NormalizeModule(tt.module)
+			if tt.wantErr {
+				if !common.IsUserError(err) {
+					t.Errorf("NormalizeModule(%q) error = %v, want a user error", tt.module, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeModule(%q) error = %v", tt.module, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeModule(%q) = %q, want %q", tt.module, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package butler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	// PagesTTL is how long the buttons of Pages keep working after the last interaction.
+	PagesTTL = 7 * 24 * time.Hour
+	// pageLength is the maximum length of a page built from entries.
+	pageLength = 2000
+)
+
+// Pages is a paginated embed like the ones of the paginator. Its state is kept in the ComponentStates, so unlike the
+// paginator its buttons keep working after a restart. It is handled by the pages component.
+type Pages struct {
+	Title string   `json:"title"`
+	URL   string   `json:"url"`
+	Pages []string `json:"pages"`
+	// Entries are the lines of a list. If set, the pages are built from the entries matching the Filter instead of
+	// using Pages, and the list can be filtered with a button.
+	Entries []string `json:"entries,omitempty"`
+	// Filter limits the Entries to the ones containing it, ignoring the case.
+	Filter string `json:"filter,omitempty"`
+	// Creator is the only user who can use the buttons if set.
+	Creator   snowflake.ID `json:"creator"`
+	Ephemeral bool         `json:"-"`
+}
+
+// FilteredEntries returns the Entries matching the Filter.
+func (p Pages) FilteredEntries() []string {
+	if p.Filter == "" {
+		return p.Entries
+	}
+	filter := strings.ToLower(p.Filter)
+	var entries []string
+	for _, entry := range p.Entries {
+		if strings.Contains(strings.ToLower(entry), filter) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Filterable returns whether the pages are built from Entries.
+func (p Pages) Filterable() bool {
+	return p.Entries != nil
+}
+
+// AllPages returns the Pages, or the pages built from the filtered Entries if the pages are Filterable.
+func (p Pages) AllPages() []string {
+	if !p.Filterable() {
+		return p.Pages
+	}
+	var (
+		pages   []string
+		curPage string
+	)
+	for _, entry := range p.FilteredEntries() {
+		if len(curPage)+len(entry)+1 > pageLength {
+			pages = append(pages, curPage)
+			curPage = ""
+		}
+		curPage += entry + "\n"
+	}
+	if curPage != "" {
+		pages = append(pages, curPage)
+	}
+	if len(pages) == 0 {
+		pages = append(pages, fmt.Sprintf("No entries contain `%s`.", p.Filter))
+	}
+	return pages
+}
+
+// PagesStatePrefix returns the ComponentStates prefix of the Pages with the given ID.
+func PagesStatePrefix(id string) string {
+	return "pages:" + id
+}
+
+// CreatePages responds with the first page and stores the Pages under the given ID, which must be unique like an
+// interaction ID.
+func (b *Butler) CreatePages(responder events.InteractionResponderFunc, id string, pages Pages) error {
+	if err := b.ComponentStates.Set(PagesStatePrefix(id), pages, PagesTTL); err != nil {
+		return err
+	}
+	embed, components := pages.Render(id, 0)
+	return responder(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreateBuilder().
+		SetEmbeds(embed).
+		SetContainerComponents(components...).
+		SetEphemeral(pages.Ephemeral).
+		Build(),
+	)
+}
+
+// Render returns the embed of the page and the buttons to navigate from it. The page is clamped to the existing pages.
+func (p Pages) Render(id string, page int) (discord.Embed, []discord.ContainerComponent) {
+	pages := p.AllPages()
+	last := len(pages) - 1
+	if page > last {
+		page = last
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	footer := "Page " + strconv.Itoa(page+1) + "/" + strconv.Itoa(len(pages))
+	if p.Filter != "" {
+		footer += fmt.Sprintf(" • %d/%d entries contain \"%s\"", len(p.FilteredEntries()), len(p.Entries), p.Filter)
+	}
+	embed := discord.NewEmbedBuilder().
+		SetTitle(p.Title).
+		SetURL(p.URL).
+		SetDescription(pages[page]).
+		SetFooterText(footer).
+		SetColor(0x4c50c1).
+		Build()
+
+	button := func(style discord.ButtonStyle, emoji string, action string, disabled bool) discord.InteractiveComponent {
+		return discord.NewButton(style, "", discord.CustomID(PagesStatePrefix(id)+":"+action+":"+strconv.Itoa(page)), "").
+			WithEmoji(discord.ComponentEmoji{Name: emoji}).
+			WithDisabled(disabled)
+	}
+	var components []discord.ContainerComponent
+	if last > 0 {
+		components = append(components, discord.NewActionRow(
+			button(discord.ButtonStylePrimary, "⏮", "first", page == 0),
+			button(discord.ButtonStylePrimary, "◀", "back", page == 0),
+			button(discord.ButtonStyleDanger, "🗑", "stop", false),
+			button(discord.ButtonStylePrimary, "▶", "next", page == last),
+			button(discord.ButtonStylePrimary, "⏩", "last", page == last),
+		))
+	}
+	if p.Filterable() {
+		components = append(components, discord.NewActionRow(
+			button(discord.ButtonStyleSecondary, "🔍", "filter", false),
+			button(discord.ButtonStyleSecondary, "✖", "clear", p.Filter == ""),
+		))
+	}
+	return embed, components
+}
@@ -0,0 +1,133 @@
+package butler
+
+import (
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/mod_mail"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var permissionNames = []struct {
+	Permission discord.Permissions
+	Name       string
+}{
+	{discord.PermissionAdministrator, "Administrator"},
+	{discord.PermissionViewChannel, "View Channel"},
+	{discord.PermissionSendMessages, "Send Messages"},
+	{discord.PermissionSendMessagesInThreads, "Send Messages in Threads"},
+	{discord.PermissionEmbedLinks, "Embed Links"},
+	{discord.PermissionAttachFiles, "Attach Files"},
+	{discord.PermissionReadMessageHistory, "Read Message History"},
+	{discord.PermissionMentionEveryone, "Mention Everyone"},
+	{discord.PermissionManageMessages, "Manage Messages"},
+	{discord.PermissionManageChannels, "Manage Channels"},
+	{discord.PermissionManageThreads, "Manage Threads"},
+	{discord.PermissionCreatePublicThread, "Create Public Threads"},
+	{discord.PermissionManageWebhooks, "Manage Webhooks"},
+	{discord.PermissionManageRoles, "Manage Roles"},
+}
+
+// PermissionNames returns the names of the permissions the bot cares about which are included in the given permissions.
+func PermissionNames(permissions discord.Permissions) []string {
+	var names []string
+	for _, permission := range permissionNames {
+		if permissions.Has(permission.Permission) {
+			names = append(names, permission.Name)
+		}
+	}
+	return names
+}
+
+// FeaturePermissions are the permissions an enabled feature needs to work.
+type FeaturePermissions struct {
+	Feature     string
+	ChannelID   *snowflake.ID
+	Permissions discord.Permissions
+}
+
+// RequiredPermissions returns the permissions needed by the features enabled in the given guild.
+func (b *Butler) RequiredPermissions(guildID snowflake.ID) []FeaturePermissions {
+	required := []FeaturePermissions{
+		{
+			Feature:     "Commands",
+			Permissions: discord.PermissionViewChannel | discord.PermissionSendMessages | discord.PermissionEmbedLinks,
+		},
+	}
+	if len(b.ReleaseConfigs()) > 0 {
+		required = append(required, FeaturePermissions{
+			Feature:     "Releases",
+			Permissions: discord.PermissionManageWebhooks,
+		})
+	}
+	if modMailGuild, ok := b.ModMail.Guild(guildID); ok {
+		channelID := modMailGuild.ChannelID
+		// forum posts are created by sending a message
+		createThread := discord.PermissionCreatePublicThread
+		if modMailGuild.ThreadSource == mod_mail.ThreadSourceForum {
+			createThread = discord.PermissionSendMessages
+		}
+		required = append(required, FeaturePermissions{
+			Feature:   "Mod Mail",
+			ChannelID: &channelID,
+			Permissions: discord.PermissionViewChannel | discord.PermissionManageThreads | createThread |
+				discord.PermissionSendMessagesInThreads | discord.PermissionManageWebhooks | discord.PermissionAttachFiles,
+		})
+	}
+	if len(b.ContributorRepos()) > 0 {
+		required = append(required, FeaturePermissions{
+			Feature:     "Contributors",
+			Permissions: discord.PermissionManageRoles,
+		})
+	}
+	return required
+}
+
+// SelfPermissions computes the effective permissions of the bot in the given guild or channel from the cache.
+func (b *Butler) SelfPermissions(guildID snowflake.ID, channelID *snowflake.ID) (discord.Permissions, error) {
+	caches := b.Client.Caches()
+	member, ok := caches.GetSelfMember(guildID)
+	if !ok {
+		return discord.PermissionsNone, common.NewUserError("the bot member is not cached for this guild")
+	}
+	permissions := caches.GetMemberPermissions(member)
+	if channelID == nil || permissions.Has(discord.PermissionAdministrator) {
+		return permissions, nil
+	}
+
+	channel, ok := caches.Channels().GetGuildChannel(*channelID)
+	if !ok {
+		return discord.PermissionsNone, common.NewUserError("the channel is not cached")
+	}
+	if channel.GuildID() != guildID {
+		return discord.PermissionsNone, common.NewUserError("the channel is not part of this guild")
+	}
+	overwrites := channel.PermissionOverwrites()
+
+	if overwrite, ok := overwrites.Role(guildID); ok {
+		permissions = permissions.Remove(overwrite.Deny).Add(overwrite.Allow)
+	}
+	var allow, deny discord.Permissions
+	for _, roleID := range member.RoleIDs {
+		if overwrite, ok := overwrites.Role(roleID); ok {
+			allow = allow.Add(overwrite.Allow)
+			deny = deny.Add(overwrite.Deny)
+		}
+	}
+	permissions = permissions.Remove(deny).Add(allow)
+	if overwrite, ok := overwrites.Member(member.User.ID); ok {
+		permissions = permissions.Remove(overwrite.Deny).Add(overwrite.Allow)
+	}
+	return permissions, nil
+}
+
+// FormatPermissions joins the names of the given permissions.
+func FormatPermissions(permissions discord.Permissions) string {
+	names := PermissionNames(permissions)
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,96 @@
+package butler
+
+import (
+	"context"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+)
+
+var (
+	loadingPresence = PresenceConfig{
+		Status:       discord.OnlineStatusDND,
+		ActivityType: discord.ActivityTypeGame,
+		ActivityName: "loading...",
+	}
+	defaultPresence = PresenceConfig{
+		Status:       discord.OnlineStatusOnline,
+		ActivityType: discord.ActivityTypeListening,
+		ActivityName: "you in DMs",
+	}
+	defaultDegradedPresence = PresenceConfig{
+		Status:       discord.OnlineStatusIdle,
+		ActivityType: discord.ActivityTypeWatching,
+		ActivityName: "⚠️ degraded",
+	}
+)
+
+type PresenceConfig struct {
+	Status       discord.OnlineStatus `json:"status" yaml:"status" toml:"status"`
+	ActivityType discord.ActivityType `json:"activity_type" yaml:"activity_type" toml:"activity_type"`
+	ActivityName string               `json:"activity_name" yaml:"activity_name" toml:"activity_name"`
+}
+
+func (c PresenceConfig) PresenceUpdate() gateway.MessageDataPresenceUpdate {
+	presenceUpdate := gateway.MessageDataPresenceUpdate{
+		Status: c.Status,
+	}
+	if c.ActivityName != "" {
+		presenceUpdate.Activities = []discord.Activity{
+			{
+				Name: c.ActivityName,
+				Type: c.ActivityType,
+			},
+		}
+	}
+	return presenceUpdate
+}
+
+// SetPresence updates the presence of the bot and persists it so it is restored on the next start.
+// While the bot is degraded the degraded presence stays visible until it recovers.
+func (b *Butler) SetPresence(presence PresenceConfig) error {
+	if !b.Health.Status().Degraded() {
+		if err := b.Client.SetPresence(context.TODO(), presence.PresenceUpdate()); err != nil {
+			return err
+		}
+	}
+	return b.UpdateConfig(func(cfg *Config) {
+		cfg.Presence = &presence
+	})
+}
+
+// startupPresence returns the presence shown until the bot is ready.
+func (b *Butler) startupPresence() PresenceConfig {
+	presence := loadingPresence
+	b.ReadConfig(func(cfg Config) {
+		if cfg.StartupPresence != nil {
+			presence = *cfg.StartupPresence
+		} else if cfg.Presence != nil {
+			presence = *cfg.Presence
+		}
+	})
+	return presence
+}
+
+// currentPresence returns the presence matching the health of the bot.
+func (b *Butler) currentPresence() PresenceConfig {
+	degraded := b.Health.Status().Degraded()
+	presence := defaultPresence
+	if degraded {
+		presence = defaultDegradedPresence
+	}
+	b.ReadConfig(func(cfg Config) {
+		if degraded && cfg.DegradedPresence != nil {
+			presence = *cfg.DegradedPresence
+		} else if !degraded && cfg.Presence != nil {
+			presence = *cfg.Presence
+		}
+	})
+	return presence
+}
+
+func (b *Butler) updatePresence() {
+	if err := b.Client.SetPresence(context.TODO(), b.currentPresence().PresenceUpdate()); err != nil {
+		b.Logger.Errorf("Failed to set presence: %s", err)
+	}
+}
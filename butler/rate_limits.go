@@ -0,0 +1,165 @@
+package butler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const docRequestWindow = time.Hour
+
+// RateLimits records the rate limit headers of the APIs the bot uses from the last responses seen.
+type RateLimits struct {
+	mu             sync.Mutex
+	discordBuckets map[string]RateLimitBucket
+	discord429s    int
+	discordGlobal  time.Time
+	github         map[string]RateLimitBucket
+	docRequests    []time.Time
+	docErrors      int
+}
+
+// RateLimitBucket is the last seen state of a rate limit.
+type RateLimitBucket struct {
+	Name      string
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RateLimitsSnapshot is a copy of the recorded rate limits.
+type RateLimitsSnapshot struct {
+	// DiscordBuckets are ordered by their remaining requests, the most exhausted first.
+	DiscordBuckets []RateLimitBucket
+	Discord429s    int
+	// DiscordGlobal is when the bot last hit the global rate limit.
+	DiscordGlobal time.Time
+	Github        []RateLimitBucket
+	// DocRequests is the amount of requests to pkg.go.dev in the last hour.
+	DocRequests int
+	DocErrors   int
+}
+
+// Transport wraps the given transport to record the rate limits of all responses.
+func (r *RateLimits) Transport(next http.RoundTripper) http.RoundTripper {
+	return rateLimitTransport{limits: r, next: next}
+}
+
+type rateLimitTransport struct {
+	limits *RateLimits
+	next   http.RoundTripper
+}
+
+func (t rateLimitTransport) RoundTrip(rq *http.Request) (*http.Response, error) {
+	rs, err := t.next.RoundTrip(rq)
+	t.limits.observe(rq, rs)
+	return rs, err
+}
+
+func (r *RateLimits) observe(rq *http.Request, rs *http.Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+
+	if rq.URL.Host == "pkg.go.dev" {
+		r.trimDocRequests(now)
+		r.docRequests = append(r.docRequests, now)
+		if rs == nil || rs.StatusCode >= http.StatusInternalServerError || rs.StatusCode == http.StatusTooManyRequests {
+			r.docErrors++
+		}
+		return
+	}
+	if rs == nil {
+		return
+	}
+
+	header := rs.Header
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+
+	// only GitHub sends the resource
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" {
+		reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+		if r.github == nil {
+			r.github = map[string]RateLimitBucket{}
+		}
+		r.github[resource] = RateLimitBucket{
+			Name:      resource,
+			Limit:     limit,
+			Remaining: remaining,
+			ResetAt:   time.Unix(reset, 0),
+		}
+		return
+	}
+
+	if rs.StatusCode == http.StatusTooManyRequests && header.Get("X-RateLimit-Scope") != "" {
+		r.discord429s++
+		if header.Get("X-RateLimit-Global") == "true" {
+			r.discordGlobal = now
+		}
+	}
+	if bucket := header.Get("X-RateLimit-Bucket"); bucket != "" {
+		resetAfter, _ := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64)
+		if r.discordBuckets == nil {
+			r.discordBuckets = map[string]RateLimitBucket{}
+		}
+		r.discordBuckets[bucket] = RateLimitBucket{
+			Name:      rq.Method + " " + rq.URL.Path,
+			Limit:     limit,
+			Remaining: remaining,
+			ResetAt:   now.Add(time.Duration(resetAfter * float64(time.Second))),
+		}
+	}
+}
+
+// Snapshot returns the recorded rate limits. Discord buckets which already reset are left out.
+func (r *RateLimits) Snapshot() RateLimitsSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+
+	snapshot := RateLimitsSnapshot{
+		Discord429s:   r.discord429s,
+		DiscordGlobal: r.discordGlobal,
+		DocErrors:     r.docErrors,
+	}
+	for id, bucket := range r.discordBuckets {
+		if bucket.ResetAt.Before(now) {
+			delete(r.discordBuckets, id)
+			continue
+		}
+		snapshot.DiscordBuckets = append(snapshot.DiscordBuckets, bucket)
+	}
+	sort.Slice(snapshot.DiscordBuckets, func(i, j int) bool {
+		return snapshot.DiscordBuckets[i].Remaining < snapshot.DiscordBuckets[j].Remaining
+	})
+	for _, bucket := range r.github {
+		snapshot.Github = append(snapshot.Github, bucket)
+	}
+	sort.Slice(snapshot.Github, func(i, j int) bool {
+		return snapshot.Github[i].Name < snapshot.Github[j].Name
+	})
+
+	r.trimDocRequests(now)
+	snapshot.DocRequests = len(r.docRequests)
+	return snapshot
+}
+
+// GithubBucket returns the last seen state of the GitHub rate limit of the given resource like "core" or "search".
+func (r *RateLimits) GithubBucket(resource string) (RateLimitBucket, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bucket, ok := r.github[resource]
+	return bucket, ok
+}
+
+// trimDocRequests drops the requests older than the docRequestWindow. mu must be held.
+func (r *RateLimits) trimDocRequests(now time.Time) {
+	windowStart := now.Add(-docRequestWindow)
+	i := sort.Search(len(r.docRequests), func(i int) bool {
+		return r.docRequests[i].After(windowStart)
+	})
+	r.docRequests = r.docRequests[i:]
+}
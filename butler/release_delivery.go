@@ -0,0 +1,153 @@
+package butler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	defaultReleaseDeliveryAttempts = 3
+	defaultReleaseDeliveryTimeout  = 10 * time.Second
+)
+
+type ReleaseDeliveryConfig struct {
+	// Attempts is how often posting a release announcement is tried when Discord is unavailable. Defaults to 3.
+	Attempts int `json:"attempts,omitempty" yaml:"attempts,omitempty" toml:"attempts,omitempty"`
+	// TimeoutSeconds limits how long a single attempt may take. Defaults to 10 seconds. Timed out attempts are not
+	// retried as the announcement may have been posted anyway.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty" toml:"timeout_seconds,omitempty"`
+	// AlertChannelID is notified when a release announcement could not be delivered. 0 disables it.
+	AlertChannelID snowflake.ID `json:"alert_channel_id,omitempty" yaml:"alert_channel_id,omitempty" toml:"alert_channel_id,omitempty"`
+	// MarkBroken pauses the announcements of repositories whose webhook was deleted until they are added again.
+	MarkBroken bool `json:"mark_broken,omitempty" yaml:"mark_broken,omitempty" toml:"mark_broken,omitempty"`
+}
+
+func (c ReleaseDeliveryConfig) attempts() int {
+	if c.Attempts <= 0 {
+		return defaultReleaseDeliveryAttempts
+	}
+	return c.Attempts
+}
+
+func (c ReleaseDeliveryConfig) timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return defaultReleaseDeliveryTimeout
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// WebhookDelivery is the outcome of the last release announcement posted with a webhook.
+type WebhookDelivery struct {
+	Repo     string
+	At       time.Time
+	Attempts int
+	// Err is set if the announcement could not be delivered.
+	Err error
+}
+
+// ReleaseDeliveries records the last delivery of each release webhook for /admin webhooks.
+type ReleaseDeliveries struct {
+	mu         sync.Mutex
+	deliveries map[snowflake.ID]WebhookDelivery
+}
+
+func (d *ReleaseDeliveries) record(webhookID snowflake.ID, delivery WebhookDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.deliveries == nil {
+		d.deliveries = map[snowflake.ID]WebhookDelivery{}
+	}
+	d.deliveries[webhookID] = delivery
+}
+
+// Get returns the last delivery of the given webhook if it was used to announce a release since the start.
+func (d *ReleaseDeliveries) Get(webhookID snowflake.ID) (WebhookDelivery, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delivery, ok := d.deliveries[webhookID]
+	return delivery, ok
+}
+
+// isWebhookGone reports whether the webhook was deleted or its token is no longer valid, so retrying won't help.
+func isWebhookGone(err error) bool {
+	var restErr *rest.Error
+	if !errors.As(err, &restErr) || restErr.Response == nil {
+		return false
+	}
+	return restErr.Response.StatusCode == http.StatusNotFound || restErr.Response.StatusCode == http.StatusUnauthorized
+}
+
+// DeliverRelease posts a release announcement of the repository with the given webhook. Attempts are retried with
+// backoff while Discord is unavailable. If the announcement can't be delivered the alert channel is notified and, if
+// the webhook is gone, the release config is marked as broken when configured.
+func (b *Butler) DeliverRelease(repo string, webhookClient webhook.Client, threadID snowflake.ID, messageCreate discord.WebhookMessageCreate) (*discord.Message, error) {
+	var cfg ReleaseDeliveryConfig
+	b.ReadConfig(func(config Config) {
+		cfg = config.ReleaseDelivery
+	})
+	var (
+		msg      *discord.Message
+		attempts int
+	)
+	backoff := &common.Backoff{Min: time.Second, Max: 10 * time.Second}
+	err := common.Retry(context.Background(), backoff, cfg.attempts(), func() error {
+		attempts++
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout())
+		defer cancel()
+		var err error
+		msg, err = webhookClient.CreateMessageInThread(messageCreate, threadID, rest.WithCtx(ctx))
+		if common.IsTimeout(err) {
+			// the announcement may have been posted anyway, retrying could post it twice
+			return &common.PermanentError{Err: err}
+		}
+		return err
+	})
+	b.ReleaseDeliveries.record(webhookClient.ID(), WebhookDelivery{
+		Repo:     repo,
+		At:       time.Now(),
+		Attempts: attempts,
+		Err:      err,
+	})
+	if err == nil {
+		return msg, nil
+	}
+
+	alert := fmt.Sprintf("A release announcement of `%s` could not be delivered after %d attempt(s): `%s`", repo, attempts, err)
+	if isWebhookGone(err) && cfg.MarkBroken {
+		if markErr := b.markReleaseBroken(repo, err); markErr != nil {
+			b.Logger.Errorf("Failed to mark release config of %s as broken: %s", repo, markErr)
+		} else {
+			alert += "\nIts webhook is gone, so announcements are paused until it is added again with `/config releases add`."
+		}
+	}
+	if cfg.AlertChannelID != 0 {
+		if _, alertErr := b.Client.Rest().CreateMessage(cfg.AlertChannelID, discord.MessageCreate{
+			Embeds: []discord.Embed{{Description: alert, Color: common.ColorError}},
+		}); alertErr != nil {
+			b.Logger.Errorf("Failed to send release delivery alert: %s", alertErr)
+		}
+	}
+	return nil, err
+}
+
+// markReleaseBroken pauses the announcements of the repository and persists it.
+func (b *Butler) markReleaseBroken(repo string, err error) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		releaseConfig, ok := cfg.GithubReleases[repo]
+		if !ok {
+			return
+		}
+		releaseConfig.Broken = err.Error()
+		cfg.GithubReleases[repo] = releaseConfig
+	})
+}
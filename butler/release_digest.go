@@ -0,0 +1,125 @@
+package butler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/webhook"
+)
+
+const releaseDigestCheckInterval = time.Minute
+
+type ReleaseDigestConfig struct {
+	// IntervalHours is the amount of hours between digests, e.g. 24 for daily or 168 for weekly digests. Digests are
+	// aligned to midnight UTC. 0 announces every release on its own.
+	IntervalHours int `json:"interval_hours" yaml:"interval_hours" toml:"interval_hours"`
+	// PostEmpty posts a digest even if no releases were published in the period.
+	PostEmpty bool `json:"post_empty" yaml:"post_empty" toml:"post_empty"`
+}
+
+func (c ReleaseDigestConfig) Enabled() bool {
+	return c.IntervalHours > 0
+}
+
+func (c ReleaseDigestConfig) interval() time.Duration {
+	return time.Duration(c.IntervalHours) * time.Hour
+}
+
+// releaseDigestJob posts the digests of all repositories with a digest configured when they are due.
+func (b *Butler) releaseDigestJob() Job {
+	// repo -> time the next digest is due
+	nextDigests := map[string]time.Time{}
+	return Job{
+		Name:     "release-digests",
+		Interval: releaseDigestCheckInterval,
+		Run: func(ctx context.Context) error {
+			var (
+				now    = time.Now()
+				failed int
+				err    error
+			)
+			for repo, cfg := range b.ReleaseConfigs() {
+				if !cfg.Digest.Enabled() {
+					delete(nextDigests, repo)
+					continue
+				}
+				if cfg.Broken != "" {
+					// releases stay pending until the webhook is fixed
+					continue
+				}
+				next, ok := nextDigests[repo]
+				if !ok {
+					nextDigests[repo] = now.Truncate(cfg.Digest.interval()).Add(cfg.Digest.interval())
+					continue
+				}
+				if now.Before(next) {
+					continue
+				}
+				nextDigests[repo] = now.Truncate(cfg.Digest.interval()).Add(cfg.Digest.interval())
+				if postErr := b.PostReleaseDigest(repo, cfg, next.Add(-cfg.Digest.interval())); postErr != nil {
+					b.LogLimiter.Errorf(b.Logger, "Failed to post release digest for %s: %s", repo, postErr)
+					failed++
+					err = postErr
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to post %d release digests, last error: %w", failed, err)
+			}
+			return nil
+		},
+	}
+}
+
+// PostReleaseDigest announces all pending releases of the repository in a single message and removes them in the same
+// transaction, so they aren't announced again if removing them fails.
+func (b *Butler) PostReleaseDigest(repo string, cfg GithubReleaseConfig, since time.Time) error {
+	releases, err := b.DB.GetPendingReleases(repo)
+	if err != nil {
+		return err
+	}
+	if len(releases) == 0 && !cfg.Digest.PostEmpty {
+		return nil
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetAuthor(fmt.Sprintf("%s releases since %s", repo, common.FormatTime(since)), "https://github.com/"+repo, "").
+		SetColor(0x5865f2).
+		SetTimestamp(time.Now())
+	var description string
+	ids := make([]int64, len(releases))
+	for i, release := range releases {
+		ids[i] = release.ID
+		description += fmt.Sprintf("•[%s](%s) by [%s](https://github.com/%s) %s\n", release.TagName, release.URL, release.Author, release.Author, common.Timestamp(release.PublishedAt))
+	}
+	if description == "" {
+		description = "No releases were published in this period."
+	}
+	embed.SetDescription(common.Truncate(description, common.Limits.EmbedDescriptionLength))
+
+	messageCreate := discord.NewWebhookMessageCreateBuilder().
+		SetEmbeds(embed.Build()).
+		SetUsername(cfg.Username).
+		SetAvatarURL(cfg.AvatarURL)
+	if len(releases) > 0 {
+		messageCreate.
+			SetContent(discord.RoleMention(cfg.PingRole)).
+			SetAllowedMentions(common.AllowedMentions(common.MentionsReleases, cfg.PingRole))
+	}
+	var msg *discord.Message
+	if err = b.DB.DeletePendingReleases(ids, func() error {
+		var postErr error
+		msg, postErr = b.DeliverRelease(repo, webhook.New(cfg.WebhookID, cfg.WebhookToken), cfg.ThreadID, messageCreate.Build())
+		return postErr
+	}); err != nil {
+		return err
+	}
+	if cfg.ThreadID != 0 {
+		// messages in threads can't be crossposted
+		return nil
+	}
+	_, err = b.Client.Rest().CrosspostMessage(msg.ChannelID, msg.ID)
+	return err
+}
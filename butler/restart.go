@@ -0,0 +1,75 @@
+package butler
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// restartEnv passes the interaction to update once the bot is back up to the restarted process.
+const restartEnv = "BUTLER_RESTART_INTERACTION"
+
+// Restart shuts the bot down like on CTRL-C and starts the binary again with the same arguments. The response of the
+// given interaction is updated once the bot is ready again. It does nothing if a restart is already pending.
+func (b *Butler) Restart(applicationID snowflake.ID, token string) {
+	select {
+	case b.restart <- fmt.Sprintf("%s:%d:%s", applicationID, time.Now().Unix(), token):
+	default:
+	}
+}
+
+// exec replaces the process with a new instance of the binary.
+func (b *Butler) exec(interaction string) {
+	executable, err := os.Executable()
+	if err != nil {
+		b.Logger.Errorf("Failed to restart: %s", err)
+		return
+	}
+	env := []string{restartEnv + "=" + interaction}
+	for _, v := range os.Environ() {
+		if !strings.HasPrefix(v, restartEnv+"=") {
+			env = append(env, v)
+		}
+	}
+	b.Logger.Info("Restarting...")
+	if err = syscall.Exec(executable, os.Args, env); err != nil {
+		b.Logger.Errorf("Failed to restart: %s", err)
+	}
+}
+
+// finishRestart updates the response of the interaction which requested the restart, if this process was started by
+// one.
+func (b *Butler) finishRestart() {
+	interaction := os.Getenv(restartEnv)
+	if interaction == "" {
+		return
+	}
+	_ = os.Unsetenv(restartEnv)
+
+	values := strings.SplitN(interaction, ":", 3)
+	if len(values) != 3 {
+		b.Logger.Warnf("Invalid %s: %s", restartEnv, interaction)
+		return
+	}
+	applicationID, err := snowflake.Parse(values[0])
+	if err != nil {
+		b.Logger.Warnf("Invalid %s: %s", restartEnv, interaction)
+		return
+	}
+	content := "Restarted."
+	if requestedAt, err := strconv.ParseInt(values[1], 10, 64); err == nil {
+		content = fmt.Sprintf("Restarted, back up after %s.", time.Since(time.Unix(requestedAt, 0)).Round(time.Second))
+	}
+	if _, err = b.Client.Rest().UpdateInteractionResponse(applicationID, values[2], discord.MessageUpdate{
+		Content: &content,
+	}); err != nil {
+		// the interaction token expires after 15 minutes
+		b.Logger.Warnf("Failed to update restart response: %s", err)
+	}
+}
@@ -0,0 +1,89 @@
+package butler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type (
+	// TextHandleFunc handles a message based command. args is the message content after the command name.
+	TextHandleFunc func(b *Butler, e *events.GuildMessageCreate, args string) error
+	TextCommand    struct {
+		Name    string
+		Handler TextHandleFunc
+	}
+)
+
+// GuildPrefixes caches the text command prefixes of the guilds. An empty prefix disables text commands.
+type GuildPrefixes struct {
+	cache dbCache[string]
+}
+
+func (b *Butler) SetupTextCommands(commands ...TextCommand) {
+	var disabled bool
+	b.ReadConfig(func(cfg Config) {
+		disabled = cfg.DisableMessageContentIntent
+	})
+	if disabled {
+		return
+	}
+	for _, command := range commands {
+		b.TextCommands[command.Name] = command
+	}
+}
+
+// GuildPrefix returns the text command prefix of the guild.
+func (b *Butler) GuildPrefix(guildID snowflake.ID) (string, error) {
+	return b.GuildPrefixes.cache.get(guildID, func(ctx context.Context) (string, error) {
+		settings, err := b.DB.GetGuildSettings(ctx, guildID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return "", err
+		}
+		return settings.Prefix, nil
+	})
+}
+
+// SetGuildPrefix persists the text command prefix of the guild. An empty prefix disables text commands.
+func (b *Butler) SetGuildPrefix(guildID snowflake.ID, prefix string) error {
+	if err := b.DB.SetGuildPrefix(guildID, prefix); err != nil {
+		return err
+	}
+	b.GuildPrefixes.cache.set(guildID, prefix)
+	return nil
+}
+
+func (b *Butler) OnGuildMessageCreate(e *events.GuildMessageCreate) {
+	if len(b.TextCommands) == 0 || e.Message.WebhookID != nil || e.Message.Author.Bot {
+		return
+	}
+	prefix, err := b.GuildPrefix(e.GuildID)
+	if err != nil {
+		if !IsCachedFailure(err) {
+			b.Logger.Errorf("Failed to get prefix of guild %s: %s", e.GuildID, err)
+		}
+		return
+	}
+	if prefix == "" || !strings.HasPrefix(e.Message.Content, prefix) {
+		return
+	}
+
+	name, args, _ := strings.Cut(strings.TrimPrefix(e.Message.Content, prefix), " ")
+	command, ok := b.TextCommands[strings.ToLower(name)]
+	if !ok {
+		return
+	}
+	// text commands can't respond ephemerally, so they are ignored in disallowed channels
+	if allowed, _, err := b.ChannelAllowed(e.GuildID, command.Name, e.ChannelID); err != nil {
+		b.Logger.Errorf("Failed to get channel allowlist of guild %s: %s", e.GuildID, err)
+	} else if !allowed {
+		return
+	}
+	if err = command.Handler(b, e, strings.TrimSpace(args)); err != nil {
+		b.Logger.Error("Error handling text command: ", err)
+	}
+}
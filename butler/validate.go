@@ -0,0 +1,128 @@
+package butler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ConfigProblem is a part of the config which does not match the state on Discord.
+type ConfigProblem struct {
+	Feature string
+	Message string
+}
+
+// ValidateConfig checks the config against Discord and returns all problems found. Permissions are checked for the
+// given guild.
+func (b *Butler) ValidateConfig(guildID snowflake.ID) []ConfigProblem {
+	var problems []ConfigProblem
+	add := func(feature string, format string, a ...any) {
+		problems = append(problems, ConfigProblem{Feature: feature, Message: fmt.Sprintf(format, a...)})
+	}
+
+	guildRoles := map[snowflake.ID]map[snowflake.ID]struct{}{}
+	roleExists := func(guildID snowflake.ID, roleID snowflake.ID) (bool, error) {
+		roles, ok := guildRoles[guildID]
+		if !ok {
+			rawRoles, err := b.Client.Rest().GetRoles(guildID)
+			if err != nil {
+				return false, err
+			}
+			roles = make(map[snowflake.ID]struct{}, len(rawRoles))
+			for _, role := range rawRoles {
+				roles[role.ID] = struct{}{}
+			}
+			guildRoles[guildID] = roles
+		}
+		_, ok = roles[roleID]
+		return ok, nil
+	}
+	checkRole := func(feature string, guildID snowflake.ID, roleID snowflake.ID) {
+		if ok, err := roleExists(guildID, roleID); err != nil {
+			add(feature, "failed to get the roles of guild `%s`: `%s`", guildID, err)
+		} else if !ok {
+			add(feature, "role `%s` does not exist", roleID)
+		}
+	}
+	checkWebhook := func(feature string, webhookID snowflake.ID) {
+		if _, err := b.Client.Rest().GetWebhook(webhookID); err != nil {
+			add(feature, "webhook `%s` is not available: `%s`", webhookID, err)
+		}
+	}
+	checkChannel := func(feature string, channelID snowflake.ID) {
+		if _, err := b.Client.Rest().GetChannel(channelID); err != nil {
+			add(feature, "channel `%s` is not available: `%s`", channelID, err)
+		}
+	}
+
+	botGuildID := b.GuildID()
+	if _, err := b.Client.Rest().GetGuild(botGuildID, false); err != nil {
+		add("Config", "guild `%s` is not available: `%s`", botGuildID, err)
+	}
+
+	releases := b.ReleaseConfigs()
+	for _, name := range sortedKeys(releases) {
+		cfg := releases[name]
+		feature := "Releases: " + name
+		checkWebhook(feature, cfg.WebhookID)
+		if cfg.ThreadID != 0 {
+			checkChannel(feature, cfg.ThreadID)
+		}
+		if cfg.PingRole != 0 {
+			checkRole(feature, botGuildID, cfg.PingRole)
+		}
+		if err := common.ValidateWebhookUsername(cfg.Username); err != nil {
+			add(feature, "%s", err)
+		}
+		if err := common.ValidateAvatarURL(cfg.AvatarURL); err != nil {
+			add(feature, "%s", err)
+		}
+	}
+
+	contributorRepos := b.ContributorRepos()
+	for _, name := range sortedKeys(contributorRepos) {
+		checkRole("Contributors: "+name, botGuildID, contributorRepos[name])
+	}
+
+	modMailGuilds := b.ModMail.Guilds()
+	for _, modMailGuildID := range sortedKeys(modMailGuilds) {
+		cfg := modMailGuilds[modMailGuildID]
+		feature := "Mod Mail: " + modMailGuildID.String()
+		if err := cfg.ValidateChannel(b.Client); err != nil {
+			add(feature, "channel `%s` is not usable: `%s`", cfg.ChannelID, err)
+		}
+		checkWebhook(feature, cfg.WebhookID)
+		checkRole(feature, modMailGuildID, cfg.RoleID)
+	}
+
+	announcementWebhooks := b.AnnouncementWebhooks()
+	for _, channelID := range sortedKeys(announcementWebhooks) {
+		checkWebhook("Announcements: "+channelID, announcementWebhooks[channelID].WebhookID)
+	}
+
+	for _, required := range b.RequiredPermissions(guildID) {
+		permissions, err := b.SelfPermissions(guildID, required.ChannelID)
+		if err != nil {
+			add(required.Feature, "failed to check permissions: `%s`", err)
+			continue
+		}
+		if missing := required.Permissions.Remove(permissions); missing != discord.PermissionsNone {
+			add(required.Feature, "missing permissions: %s", FormatPermissions(missing))
+		}
+	}
+	return problems
+}
+
+func sortedKeys[K string | snowflake.ID, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,29 @@
+package butler
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+	"golang.org/x/exp/slices"
+)
+
+// Verbose reports whether responses in the guild should include diagnostic details, see common.RespondVerbose.
+func (b *Butler) Verbose(guildID *snowflake.ID) bool {
+	b.configMu.RLock()
+	defer b.configMu.RUnlock()
+	if b.Config.Verbose {
+		return true
+	}
+	return guildID != nil && slices.Contains(b.Config.VerboseGuildIDs, *guildID)
+}
+
+// SetVerbose enables or disables the diagnostic details in the guild and persists it.
+func (b *Butler) SetVerbose(guildID snowflake.ID, verbose bool) error {
+	return b.UpdateConfig(func(cfg *Config) {
+		i := slices.Index(cfg.VerboseGuildIDs, guildID)
+		switch {
+		case verbose && i == -1:
+			cfg.VerboseGuildIDs = append(cfg.VerboseGuildIDs, guildID)
+		case !verbose && i != -1:
+			cfg.VerboseGuildIDs = slices.Delete(cfg.VerboseGuildIDs, i, i+1)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package butler
+
+import (
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ReleaseWebhooks caches the webhook clients used to announce releases by their repository.
+type ReleaseWebhooks struct {
+	mu      sync.Mutex
+	clients map[string]webhook.Client
+}
+
+// Client returns the cached webhook client of the repository or creates one if the webhook of the config changed.
+func (w *ReleaseWebhooks) Client(repo string, cfg GithubReleaseConfig) webhook.Client {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if webhookClient, ok := w.clients[repo]; ok && webhookClient.ID() == cfg.WebhookID {
+		return webhookClient
+	}
+	if w.clients == nil {
+		w.clients = map[string]webhook.Client{}
+	}
+	webhookClient := webhook.New(cfg.WebhookID, cfg.WebhookToken)
+	w.clients[repo] = webhookClient
+	return webhookClient
+}
+
+// Len returns the number of cached webhook clients.
+func (w *ReleaseWebhooks) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.clients)
+}
+
+// WebhookClient returns a client for one of the webhooks the bot knows the token of.
+func (b *Butler) WebhookClient(webhookID snowflake.ID) (webhook.Client, bool) {
+	for repo, cfg := range b.ReleaseConfigs() {
+		if cfg.WebhookID == webhookID {
+			return b.ReleaseWebhooks.Client(repo, cfg), true
+		}
+	}
+	for _, cfg := range b.AnnouncementWebhooks() {
+		if cfg.WebhookID == webhookID {
+			return webhook.New(cfg.WebhookID, cfg.WebhookToken), true
+		}
+	}
+	return b.ModMail.WebhookClient(webhookID)
+}
+
+// ManagedWebhook is a webhook created by the bot, either referenced in the config or orphaned.
+type ManagedWebhook struct {
+	ID        snowflake.ID
+	Name      string
+	ChannelID snowflake.ID
+	// Err is set if the webhook could not be fetched, e.g. because it was deleted.
+	Err    error
+	Orphan bool
+	// Delivery is the last release announcement posted with the webhook, if any.
+	Delivery *WebhookDelivery
+	// Broken is why the release announcements of the webhook are paused, see GithubReleaseConfig.Broken.
+	Broken string
+}
+
+// ManagedWebhooks returns the webhooks referenced in the config and the ones the bot created in the guild which are not.
+// The webhooks of the guild are fetched at once, only referenced webhooks of other guilds are fetched one by one.
+func (b *Butler) ManagedWebhooks(guildID snowflake.ID) ([]ManagedWebhook, error) {
+	guildWebhooks, err := b.Client.Rest().GetAllWebhooks(guildID)
+	if err != nil {
+		return nil, err
+	}
+	guildWebhookIDs := make(map[snowflake.ID]discord.Webhook, len(guildWebhooks))
+	for _, wh := range guildWebhooks {
+		guildWebhookIDs[wh.ID()] = wh
+	}
+
+	var webhooks []ManagedWebhook
+	known := map[snowflake.ID]struct{}{}
+	add := func(id snowflake.ID, name string) {
+		known[id] = struct{}{}
+		managed := ManagedWebhook{ID: id, Name: name}
+		wh, ok := guildWebhookIDs[id]
+		if !ok {
+			wh, managed.Err = b.Client.Rest().GetWebhook(id)
+		}
+		if incoming, ok := wh.(discord.IncomingWebhook); ok {
+			managed.ChannelID = incoming.ChannelID
+		}
+		webhooks = append(webhooks, managed)
+	}
+
+	releaseConfigs := b.ReleaseConfigs()
+	for _, name := range sortedKeys(releaseConfigs) {
+		cfg := releaseConfigs[name]
+		add(cfg.WebhookID, "Releases: "+name)
+		managed := &webhooks[len(webhooks)-1]
+		managed.Broken = cfg.Broken
+		if delivery, ok := b.ReleaseDeliveries.Get(cfg.WebhookID); ok {
+			managed.Delivery = &delivery
+		}
+	}
+	for _, cfg := range b.AnnouncementWebhooks() {
+		add(cfg.WebhookID, "Announcements")
+	}
+	for _, modMailWebhook := range b.ModMail.Webhooks() {
+		add(modMailWebhook.WebhookID, "Mod Mail")
+	}
+
+	for _, wh := range guildWebhooks {
+		incoming, ok := wh.(discord.IncomingWebhook)
+		if !ok || incoming.User.ID != b.Client.ID() {
+			continue
+		}
+		if _, ok = known[incoming.ID()]; ok {
+			continue
+		}
+		webhooks = append(webhooks, ManagedWebhook{
+			ID:        incoming.ID(),
+			Name:      incoming.Name(),
+			ChannelID: incoming.ChannelID,
+			Orphan:    true,
+		})
+	}
+	return webhooks, nil
+}
+
+// SkippedWebhook is a webhook which can't be imported and why.
+type SkippedWebhook struct {
+	ID     snowflake.ID
+	Name   string
+	Reason string
+}
+
+// ImportableWebhooks returns the webhooks in the channel which the bot can post release announcements with and are not
+// used by the config yet. Webhooks without a token, like the ones of other applications or followed channels, and
+// webhooks whose token doesn't work are skipped.
+func (b *Butler) ImportableWebhooks(channelID snowflake.ID) ([]discord.IncomingWebhook, []SkippedWebhook, error) {
+	webhooks, err := b.Client.Rest().GetWebhooks(channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	configured := map[snowflake.ID]struct{}{}
+	for _, cfg := range b.ReleaseConfigs() {
+		configured[cfg.WebhookID] = struct{}{}
+	}
+	for _, cfg := range b.AnnouncementWebhooks() {
+		configured[cfg.WebhookID] = struct{}{}
+	}
+	for _, modMailWebhook := range b.ModMail.Webhooks() {
+		configured[modMailWebhook.WebhookID] = struct{}{}
+	}
+
+	var (
+		importable []discord.IncomingWebhook
+		skipped    []SkippedWebhook
+	)
+	for _, wh := range webhooks {
+		incoming, ok := wh.(discord.IncomingWebhook)
+		if !ok {
+			skipped = append(skipped, SkippedWebhook{ID: wh.ID(), Name: wh.Name(), Reason: "not an incoming webhook"})
+			continue
+		}
+		if _, ok = configured[incoming.ID()]; ok {
+			continue
+		}
+		if incoming.Token == "" || (incoming.ApplicationID != nil && *incoming.ApplicationID != b.Client.ApplicationID()) {
+			skipped = append(skipped, SkippedWebhook{ID: incoming.ID(), Name: incoming.Name(), Reason: "owned by another application"})
+			continue
+		}
+		if _, err = webhook.New(incoming.ID(), incoming.Token).GetWebhook(); err != nil {
+			skipped = append(skipped, SkippedWebhook{ID: incoming.ID(), Name: incoming.Name(), Reason: "token is invalid: " + err.Error()})
+			continue
+		}
+		importable = append(importable, incoming)
+	}
+	return importable, skipped, nil
+}
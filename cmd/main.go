@@ -5,6 +5,7 @@ import (
 
 	"github.com/disgoorg/disgo-butler/butler"
 	"github.com/disgoorg/disgo-butler/commands"
+	"github.com/disgoorg/disgo-butler/common"
 	"github.com/disgoorg/disgo-butler/components"
 	"github.com/disgoorg/disgo-butler/routes"
 	"github.com/disgoorg/log"
@@ -30,13 +31,23 @@ func main() {
 		panic("failed to load config: " + err.Error())
 	}
 
+	if err = common.SetTimezone(cfg.Timezone); err != nil {
+		panic("failed to load timezone: " + err.Error())
+	}
+	common.SetLimits(cfg.Limits)
+	common.SetMessages(cfg.Messages)
+	common.SetAllowedMentions(cfg.AllowedMentions, cfg.FeatureAllowedMentions)
+	common.SetErrorReports(cfg.ErrorReports.ChannelID != 0)
+
 	logger := log.New(log.LstdFlags | log.Lshortfile)
 	logger.SetLevel(cfg.LogLevel)
+	common.SetLogger(logger)
 	logger.Info("starting Disgo-Butler...")
 
 	b := butler.New(logger, version, *cfg)
 
 	r := chi.NewRouter()
+	r.Get("/health", routes.HandleHealth(b))
 	r.Route("/github", func(r chi.Router) {
 		r.Get("/", routes.HandleGithub(b))
 		r.Get("/login", routes.HandleLogin(b))
@@ -50,13 +61,30 @@ func main() {
 		commands.PingCommand,
 		commands.InfoCommand,
 		commands.DocsCommand,
+		commands.DocsFindCommand,
+		commands.DocsExamplesCommand,
+		commands.DocsPrefsCommand,
 		commands.TagCommand,
 		commands.TagsCommand,
+		commands.WhoisCommand,
+		commands.GithubCommand,
+		commands.IssuesCommand,
+		commands.EditMessageCommand,
+		commands.AnnounceCommand,
 		commands.ConfigCommand,
+		commands.AdminCommand.WithGuildIDs(cfg.GuildID),
 		commands.TicketCommand(b.ModMail),
+		commands.ModMailCommand(b.ModMail),
 	)
 	b.SetupComponents(
 		components.DocsActionComponent,
+		components.WebhookDeleteComponent,
+		components.ModMailComponent,
+		components.PagesComponent,
+		components.ReleasesImportComponent,
+		components.AdminRestartComponent,
+		components.ErrorReportComponent,
 	)
+	b.SetupTextCommands(commands.TextCommands...)
 	b.StartAndBlock()
 }
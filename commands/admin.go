@@ -0,0 +1,814 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
+	"github.com/disgoorg/log"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var AdminCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName: "admin",
+		Description: "Used by the bot owners to inspect the bot.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "doc-status",
+				Description: "Shows which module aliases resolve and which are failing.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "config-backups",
+				Description: "Lists all available config backups.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "config-save",
+				Description: "Saves the current config and rotates the backups.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "config-restore",
+				Description: "Restores the config from a backup.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						OptionName:  "backup",
+						Description: "The number of the backup to restore.",
+						Required:    true,
+						MinValue:    json.NewPtr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "contributor-sync",
+				Description: "Shows when the contributor roles were last synced and when the next sync is due.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "permcheck",
+				Description: "Shows the effective permissions of the bot and which ones are missing.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionChannel{
+						OptionName:  "channel",
+						Description: "The channel to check the permissions in.",
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "loglevel",
+				Description: "Shows or changes the log level until the next restart.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						OptionName:  "level",
+						Description: "The new log level.",
+						Choices: []discord.ApplicationCommandOptionChoiceInt{
+							{Name: log.LevelTrace.String(), Value: int(log.LevelTrace)},
+							{Name: log.LevelDebug.String(), Value: int(log.LevelDebug)},
+							{Name: log.LevelInfo.String(), Value: int(log.LevelInfo)},
+							{Name: log.LevelWarn.String(), Value: int(log.LevelWarn)},
+							{Name: log.LevelError.String(), Value: int(log.LevelError)},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "resync-commands",
+				Description: "Re-registers the global commands or the commands of a guild.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:  "guild",
+						Description: "The ID of the guild to re-register the commands of. Re-registers the global commands if not set.",
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "alias-info",
+				Description: "Shows how a doc alias resolves and its cache status",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "alias",
+						Description:  "The alias to inspect",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "doc-rewarm",
+				Description: "Fetches the docs of an alias again",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "alias",
+						Description:  "The alias to fetch again",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "debug",
+				Description: "Dumps a snapshot of the internal state",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "webhooks",
+				Description: "Lists all webhooks managed by the bot",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "ratelimits",
+				Description: "Shows the rate limits of Discord, GitHub and pkg.go.dev",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "jobs",
+				Description: "Shows the status of the background jobs and optionally runs one now",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "trigger",
+						Description:  "The job to run now",
+						Autocomplete: true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "job-errors",
+				Description: "Shows the latest errors of a background job",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "job",
+						Description:  "The job to show the errors of",
+						Required:     true,
+						Autocomplete: true,
+					},
+					discord.ApplicationCommandOptionBool{
+						OptionName:  "clear",
+						Description: "Whether to clear the errors instead",
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "job-interval",
+				Description: "Changes the interval of a background job without restarting it",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "job",
+						Description:  "The job to change the interval of",
+						Required:     true,
+						Autocomplete: true,
+					},
+					discord.ApplicationCommandOptionString{
+						OptionName:  "interval",
+						Description: "The new interval, e.g. 90s, 15m or 2h",
+						Required:    true,
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "doc-cache-ttl",
+				Description: "Changes how long cached docs are used before they are fetched again",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						OptionName:  "hours",
+						Description: "The amount of hours",
+						Required:    true,
+						MinValue:    json.NewPtr(1),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "validate",
+				Description: "Checks the config against the current state on Discord",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "restart",
+				Description: "Shuts the bot down cleanly and starts it again.",
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "presence",
+				Description: "Sets the presence of the bot.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:  "status",
+						Description: "The online status of the bot.",
+						Required:    true,
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "Online", Value: string(discord.OnlineStatusOnline)},
+							{Name: "Idle", Value: string(discord.OnlineStatusIdle)},
+							{Name: "Do Not Disturb", Value: string(discord.OnlineStatusDND)},
+							{Name: "Invisible", Value: string(discord.OnlineStatusInvisible)},
+						},
+					},
+					discord.ApplicationCommandOptionInt{
+						OptionName:  "activity-type",
+						Description: "The type of the activity.",
+						Choices: []discord.ApplicationCommandOptionChoiceInt{
+							{Name: "Playing", Value: int(discord.ActivityTypeGame)},
+							{Name: "Listening to", Value: int(discord.ActivityTypeListening)},
+							{Name: "Watching", Value: int(discord.ActivityTypeWatching)},
+							{Name: "Competing in", Value: int(discord.ActivityTypeCompeting)},
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						OptionName:  "activity-name",
+						Description: "The name of the activity.",
+					},
+				},
+			},
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"doc-status":       ownerOnly(handleAdminDocStatus),
+		"config-backups":   ownerOnly(handleAdminConfigBackups),
+		"config-save":      ownerOnly(handleAdminConfigSave),
+		"config-restore":   ownerOnly(handleAdminConfigRestore),
+		"presence":         ownerOnly(handleAdminPresence),
+		"contributor-sync": ownerOnly(handleAdminContributorSync),
+		"permcheck":        ownerOnly(handleAdminPermCheck),
+		"loglevel":         ownerOnly(handleAdminLogLevel),
+		"resync-commands":  ownerOnly(handleAdminResyncCommands),
+		"webhooks":         ownerOnly(handleAdminWebhooks),
+		"alias-info":       ownerOnly(handleAdminAliasInfo),
+		"doc-rewarm":       ownerOnly(handleAdminDocRewarm),
+		"debug":            ownerOnly(handleAdminDebug),
+		"validate":         ownerOnly(handleAdminValidate),
+		"ratelimits":       ownerOnly(handleAdminRateLimits),
+		"jobs":             ownerOnly(handleAdminJobs),
+		"job-errors":       ownerOnly(handleAdminJobErrors),
+		"job-interval":     ownerOnly(handleAdminJobInterval),
+		"doc-cache-ttl":    ownerOnly(handleAdminDocCacheTTL),
+		"restart":          ownerOnly(handleAdminRestart),
+	},
+	AutocompleteHandlers: map[string]butler.AutocompleteHandleFunc{
+		"alias-info":   handleAdminAliasInfoAutocomplete,
+		"doc-rewarm":   handleAdminAliasInfoAutocomplete,
+		"jobs":         handleAdminJobsAutocomplete,
+		"job-errors":   handleAdminJobErrorsAutocomplete,
+		"job-interval": handleAdminJobErrorsAutocomplete,
+	},
+}
+
+func ownerOnly(handler butler.HandleFunc) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		if !b.IsOwner(e.User().ID) {
+			return common.RespondErrMessage(e.Respond, common.Message(common.MessageOwnerOnly))
+		}
+		return handler(b, e)
+	}
+}
+
+func handleAdminDocStatus(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	statuses := b.DocStatuses.All()
+
+	modules := b.Aliases(nil)
+	aliases := make([]string, 0, len(modules))
+	for alias := range modules {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var resolved, failing string
+	for _, alias := range aliases {
+		module := modules[alias]
+		status, ok := statuses[alias]
+		if !ok {
+			failing += fmt.Sprintf("•`%s` -> `%s`: not loaded yet\n", alias, module)
+			continue
+		}
+		if status.Err != nil {
+			failing += fmt.Sprintf("•`%s` -> `%s` %s: `%s`\n", alias, module, common.Timestamp(status.CheckedAt), status.Err)
+			continue
+		}
+		resolved += fmt.Sprintf("•`%s` -> `%s`\n", alias, module)
+	}
+	if resolved == "" {
+		resolved = "None"
+	}
+	if failing == "" {
+		failing = "None"
+	}
+	return common.Respondf(e.Respond, "**Resolving:**\n%s\n**Failing:**\n%s", resolved, failing)
+}
+
+func handleAdminConfigBackups(_ *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	backups, err := butler.ListConfigBackups()
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if len(backups) == 0 {
+		return common.Respond(e.Respond, "No config backups found.")
+	}
+
+	var message string
+	for _, backup := range backups {
+		message += fmt.Sprintf("•`%d` %s\n", backup.Index, common.Timestamp(backup.ModTime))
+	}
+	return common.Respondf(e.Respond, "Config backups:\n%s", message)
+}
+
+func handleAdminConfigSave(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	modMailState := b.ModMail.Close()
+	path := butler.ConfigPath()
+	var configBackups int
+	if err := b.UpdateConfig(func(cfg *butler.Config) {
+		cfg.ModMail = cfg.ModMail.WithState(modMailState)
+		configBackups = cfg.ConfigBackups
+	}); err != nil {
+		return common.RespondErrMessagef(e.Respond, "Failed to save config to `%s`: `%s`", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return common.RespondErrMessagef(e.Respond, "Saved config to `%s` but failed to read it back: `%s`", path, err)
+	}
+	return common.Respondf(e.Respond, "Saved config to `%s` (%s), keeping %d backups.", path, common.FormatBytes(int(info.Size())), configBackups)
+}
+
+func handleAdminConfigRestore(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	index := e.SlashCommandInteractionData().Int("backup")
+
+	cfg, err := butler.LoadConfigBackup(index)
+	if os.IsNotExist(err) {
+		return common.RespondErrMessagef(e.Respond, "config backup `%d` does not exist", index)
+	} else if err != nil {
+		return common.RespondErrMessagef(e.Respond, "Failed to load config backup `%d`: `%s`", index, err)
+	}
+
+	// keep the open tickets, blocked users and history, the mod mail setup of the backup applies after a restart
+	cfg.ModMail = cfg.ModMail.WithState(b.ModMail.Close())
+	common.SetMessages(cfg.Messages)
+	common.SetAllowedMentions(cfg.AllowedMentions, cfg.FeatureAllowedMentions)
+	common.SetErrorReports(cfg.ErrorReports.ChannelID != 0)
+	if err = b.UpdateConfig(func(config *butler.Config) {
+		*config = *cfg
+	}); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return common.Respondf(e.Respond, "Restored config backup `%d`. Some changes only take effect after a restart.", index)
+}
+
+func handleAdminRestart(_ *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent("Restart the bot? Running jobs are waited for and the config is saved first.").
+		AddActionRow(
+			discord.NewDangerButton("Restart", "admin_restart:confirm"),
+			discord.NewSecondaryButton("Cancel", "admin_restart:cancel"),
+		).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+func handleAdminPresence(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	presence := butler.PresenceConfig{
+		Status:       discord.OnlineStatus(data.String("status")),
+		ActivityType: discord.ActivityType(data.Int("activity-type")),
+		ActivityName: data.String("activity-name"),
+	}
+	if err := b.SetPresence(presence); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return common.Respond(e.Respond, "Presence updated.")
+}
+
+func handleAdminContributorSync(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	var enabled bool
+	b.ReadConfig(func(cfg butler.Config) {
+		enabled = cfg.ContributorSync.IntervalMinutes > 0
+	})
+	if !enabled {
+		return common.Respond(e.Respond, "The contributor sync is disabled.")
+	}
+	status := b.ContributorSync.Status()
+
+	lastRun := "Never"
+	if !status.LastRun.IsZero() {
+		lastRun = common.Timestamp(status.LastRun)
+		if status.Err != nil {
+			lastRun += fmt.Sprintf("\nFailed: `%s`", status.Err)
+		} else {
+			lastRun += "\n" + status.Result.String()
+		}
+	}
+	nextRun := "Unknown"
+	if !status.NextRun.IsZero() {
+		nextRun = common.Timestamp(status.NextRun)
+	}
+	return common.Respondf(e.Respond, "**Last sync:** %s\n**Next sync:** %s", lastRun, nextRun)
+}
+
+func handleAdminPermCheck(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.GuildID() == nil {
+		return common.RespondErrMessage(e.Respond, "This command can only be used in a guild.")
+	}
+	guildID := *e.GuildID()
+
+	var channelID *snowflake.ID
+	if channel, ok := e.SlashCommandInteractionData().OptChannel("channel"); ok {
+		channelID = &channel.ID
+	}
+	permissions, err := b.SelfPermissions(guildID, channelID)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	message := fmt.Sprintf("**Effective permissions:** %s\n\n", butler.FormatPermissions(permissions))
+	for _, required := range b.RequiredPermissions(guildID) {
+		featurePermissions := permissions
+		var where string
+		if required.ChannelID != nil {
+			where = fmt.Sprintf(" in <#%s>", *required.ChannelID)
+			if channelID == nil || *channelID != *required.ChannelID {
+				if featurePermissions, err = b.SelfPermissions(guildID, required.ChannelID); err != nil {
+					message += fmt.Sprintf("❔ **%s**%s: `%s`\n", required.Feature, where, err)
+					continue
+				}
+			}
+		}
+		missing := required.Permissions.Remove(featurePermissions)
+		if missing == discord.PermissionsNone {
+			message += fmt.Sprintf("✅ **%s**%s\n", required.Feature, where)
+			continue
+		}
+		message += fmt.Sprintf("❌ **%s**%s is missing: %s\n", required.Feature, where, butler.FormatPermissions(missing))
+	}
+	return common.Respond(e.Respond, message)
+}
+
+func handleAdminLogLevel(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	level, ok := e.SlashCommandInteractionData().OptInt("level")
+	if !ok {
+		return common.Respondf(e.Respond, "The current log level is `%s`.", b.LogLevel())
+	}
+	if err := b.SetLogLevel(log.Level(level)); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	b.Logger.Warnf("log level changed to %s by %s", log.Level(level), e.User().Tag())
+	return common.Respondf(e.Respond, "Log level set to `%s` until the next restart.", log.Level(level))
+}
+
+func handleAdminResyncCommands(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	var guildID *snowflake.ID
+	scope := "global"
+	if rawGuildID, ok := e.SlashCommandInteractionData().OptString("guild"); ok {
+		id, err := snowflake.Parse(rawGuildID)
+		if err != nil {
+			return common.RespondErr(e.Respond, common.NewUserErrorf("invalid guild ID `%s`", rawGuildID))
+		}
+		guildID = &id
+		scope = fmt.Sprintf("guild `%s`", id)
+	}
+
+	if err := e.DeferCreateMessage(true); err != nil {
+		return err
+	}
+	var message string
+	result, err := b.SyncCommands(guildID)
+	if errors.Is(err, butler.ErrCommandSyncRunning) {
+		message = "A command sync is already running. Please try again later."
+	} else if err != nil {
+		b.Logger.Errorf("Failed to sync %s commands: %s", scope, err)
+		message = fmt.Sprintf("Failed to sync %s commands: `%s`", scope, err)
+	} else {
+		message = fmt.Sprintf("Synced %s commands.\n%s", scope, result)
+	}
+	_, err = e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), discord.MessageUpdate{Content: &message})
+	return err
+}
+
+func handleAdminWebhooks(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.GuildID() == nil {
+		return common.RespondErrMessage(e.Respond, "This command can only be used in a guild.")
+	}
+	// webhooks of other guilds are fetched one by one
+	if err := e.DeferCreateMessage(true); err != nil {
+		return err
+	}
+	responder := common.DeferredResponder(e.Client(), e, true)
+
+	webhooks, err := b.ManagedWebhooks(*e.GuildID())
+	if err != nil {
+		return common.RespondErr(responder, err)
+	}
+	if len(webhooks) == 0 {
+		return common.Respond(responder, "No webhooks found.")
+	}
+
+	var (
+		entries []string
+		orphans []discord.InteractiveComponent
+	)
+	for _, wh := range webhooks {
+		channel := "unknown channel"
+		if wh.ChannelID != 0 {
+			channel = discord.ChannelMention(wh.ChannelID)
+		}
+		var entry string
+		switch {
+		case wh.Err != nil:
+			entry = fmt.Sprintf("❌ **%s** `%s`: `%s`", wh.Name, wh.ID, wh.Err)
+		case wh.Orphan:
+			entry = fmt.Sprintf("⚠ **%s** `%s` in %s is not referenced in the config", wh.Name, wh.ID, channel)
+			if len(orphans) < 25 {
+				orphans = append(orphans, discord.NewDangerButton("Delete "+wh.ID.String(), discord.CustomID("webhook_delete:"+wh.ID.String())))
+			}
+		default:
+			entry = fmt.Sprintf("✅ **%s** `%s` in %s", wh.Name, wh.ID, channel)
+		}
+		if wh.Broken != "" {
+			entry += fmt.Sprintf("\n  ⛔ announcements paused: `%s`", wh.Broken)
+		}
+		if wh.Delivery != nil {
+			if wh.Delivery.Err != nil {
+				entry += fmt.Sprintf("\n  last announcement failed %s after %d attempt(s): `%s`", common.Timestamp(wh.Delivery.At), wh.Delivery.Attempts, wh.Delivery.Err)
+			} else {
+				entry += fmt.Sprintf("\n  last announcement delivered %s", common.Timestamp(wh.Delivery.At))
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = b.CreatePages(responder, e.ID().String(), butler.Pages{
+		Title:     "Managed Webhooks",
+		Entries:   entries,
+		Creator:   e.User().ID,
+		Ephemeral: true,
+	}); err != nil || len(orphans) == 0 {
+		return err
+	}
+
+	var rows []discord.ContainerComponent
+	for i := 0; i < len(orphans); i += 5 {
+		end := i + 5
+		if end > len(orphans) {
+			end = len(orphans)
+		}
+		rows = append(rows, discord.NewActionRow(orphans[i:end]...))
+	}
+	_, err = e.Client().Rest().CreateFollowupMessage(e.ApplicationID(), e.Token(), discord.MessageCreate{
+		Content:    fmt.Sprintf("Found %d orphaned webhook(s). Delete them?", len(orphans)),
+		Components: rows,
+		Flags:      discord.MessageFlagEphemeral,
+	})
+	return err
+}
+
+func handleAdminAliasInfo(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	alias := e.SlashCommandInteractionData().String("alias")
+	module, ok := b.Alias(nil, alias)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "alias `%s` does not exist", alias)
+	}
+
+	lastWarmed := "Never"
+	if status, ok := b.DocStatuses.Get(alias); ok {
+		lastWarmed = common.Timestamp(status.CheckedAt)
+		if status.Err != nil {
+			lastWarmed += fmt.Sprintf("\nFailed: `%s`", status.Err)
+		}
+	}
+
+	cached := "No"
+	if entry, ok := b.DocCacheEntry(module); ok {
+		cached = fmt.Sprintf("Yes, %d symbols and %d subpackages\n**Fetched:** %s\n**Updated:** %s", entry.Symbols, entry.Subpackages, common.Timestamp(entry.Created), common.Timestamp(entry.Updated))
+	}
+
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetEmbeds(discord.NewEmbedBuilder().
+			SetTitle(alias).
+			SetURL(b.PackageURL(module)).
+			SetDescriptionf("**Module:** `%s`\n**Last warmed:** %s\n**Cached:** %s", module, lastWarmed, cached).
+			SetColor(common.ColorSuccess).
+			Build(),
+		).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+func handleAdminDocRewarm(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	alias := e.SlashCommandInteractionData().String("alias")
+	module, ok := b.Alias(nil, alias)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "alias `%s` does not exist", alias)
+	}
+	if err := e.DeferCreateMessage(true); err != nil {
+		return err
+	}
+	responder := common.DeferredResponder(e.Client(), e, true)
+
+	took, err := b.RewarmAlias(alias, module)
+	if err != nil {
+		return common.RespondErrMessagef(responder, "Failed to fetch `%s` after %s: `%s`", module, took.Round(time.Millisecond), err)
+	}
+	return common.Respondf(responder, "Fetched `%s` for alias `%s` in %s", module, alias, took.Round(time.Millisecond))
+}
+
+func handleAdminAliasInfoAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	modules := b.Aliases(nil)
+	aliases := make([]string, 0, len(modules))
+	for alias := range modules {
+		aliases = append(aliases, alias)
+	}
+	return e.Result(common.AutocompleteChoices(aliases, e.Data.String("alias")))
+}
+
+func handleAdminDebug(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	snapshot := b.DebugSnapshot()
+	data, err := json.MarshalIndent(snapshot, "", "\t")
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContentf("Debug snapshot %s", common.Timestamp(snapshot.CreatedAt)).
+		AddFile(fmt.Sprintf("debug-%d.json", snapshot.CreatedAt.Unix()), "", bytes.NewReader(data)).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+func handleAdminValidate(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.GuildID() == nil {
+		return common.RespondErrMessage(e.Respond, "This command can only be used in a guild.")
+	}
+	if err := e.DeferCreateMessage(true); err != nil {
+		return err
+	}
+	responder := common.DeferredResponder(e.Client(), e, true)
+
+	problems := b.ValidateConfig(*e.GuildID())
+	if len(problems) == 0 {
+		return common.Respond(responder, "✅ No problems found.")
+	}
+
+	entries := make([]string, len(problems))
+	for i, problem := range problems {
+		entries[i] = fmt.Sprintf("❌ **%s**: %s", problem.Feature, problem.Message)
+	}
+
+	return b.CreatePages(responder, e.ID().String(), butler.Pages{
+		Title:     fmt.Sprintf("%d Config Problems", len(problems)),
+		Entries:   entries,
+		Creator:   e.User().ID,
+		Ephemeral: true,
+	})
+}
+
+func handleAdminRateLimits(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	snapshot := b.RateLimits.Snapshot()
+
+	discordMessage := fmt.Sprintf("%d active buckets, %d rate limited requests", len(snapshot.DiscordBuckets), snapshot.Discord429s)
+	if !snapshot.DiscordGlobal.IsZero() {
+		discordMessage += "\nLast global rate limit " + common.Timestamp(snapshot.DiscordGlobal)
+	}
+	for i, bucket := range snapshot.DiscordBuckets {
+		if i == 5 {
+			break
+		}
+		discordMessage += fmt.Sprintf("\n`%s` %d/%d resets %s", bucket.Name, bucket.Remaining, bucket.Limit, discord.TimestampStyleRelative.FormatTime(bucket.ResetAt))
+	}
+
+	githubMessage := "No requests seen yet"
+	if len(snapshot.Github) > 0 {
+		githubMessage = ""
+		for _, bucket := range snapshot.Github {
+			githubMessage += fmt.Sprintf("`%s` %d/%d resets %s\n", bucket.Name, bucket.Remaining, bucket.Limit, discord.TimestampStyleRelative.FormatTime(bucket.ResetAt))
+		}
+	}
+
+	cache := b.GithubCache.Stats()
+	githubMessage = strings.TrimSuffix(githubMessage, "\n") + fmt.Sprintf("\nCache: %.0f%% hit rate, %d hits, %d revalidated, %d misses, %d entries", cache.HitRate()*100, cache.Hits, cache.Revalidated, cache.Misses, cache.Entries)
+
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetEmbeds(discord.NewEmbedBuilder().
+			SetTitle("Rate Limits").
+			AddField("Discord", discordMessage, false).
+			AddField("GitHub", githubMessage, false).
+			AddField("pkg.go.dev", fmt.Sprintf("%d requests in the last hour, %d failed since start", snapshot.DocRequests, snapshot.DocErrors), false).
+			SetColor(common.ColorSuccess).
+			Build(),
+		).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+func handleAdminJobs(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	var triggered string
+	if name, ok := e.SlashCommandInteractionData().OptString("trigger"); ok {
+		if err := b.Jobs.Trigger(name); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+		b.Logger.Infof("job %s triggered by %s", name, e.User().Tag())
+		triggered = fmt.Sprintf("Triggered `%s`.", name)
+	}
+
+	statuses := b.Jobs.Statuses()
+	if len(statuses) == 0 {
+		return common.Respond(e.Respond, "No background jobs are running.")
+	}
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Background Jobs").
+		SetColor(common.ColorSuccess)
+	for _, status := range statuses {
+		lastRun := "Never"
+		if !status.LastRun.IsZero() {
+			lastRun = fmt.Sprintf("%s in %s", common.Timestamp(status.LastRun), status.LastDuration.Round(time.Millisecond))
+		}
+		message := fmt.Sprintf("**Interval:** %s\n**Runs:** %d\n**Last run:** %s", status.Interval, status.Runs, lastRun)
+		if status.Running {
+			message += "\n**Next run:** running now"
+		} else if !status.NextRun.IsZero() {
+			message += "\n**Next run:** " + discord.TimestampStyleRelative.FormatTime(status.NextRun)
+		}
+		if status.LastErr != nil {
+			message += fmt.Sprintf("\n**Last error:** `%s`", common.Truncate(status.LastErr.Error(), 500))
+			embed.SetColor(common.ColorError)
+		}
+		if status.Errors > 0 {
+			message += fmt.Sprintf("\n**Errors:** %d, see `/admin job-errors`", status.Errors)
+		}
+		embed.AddField(status.Name, message, true)
+	}
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(triggered).
+		SetEmbeds(embed.Build()).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+func handleAdminJobsAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	return e.Result(common.AutocompleteChoices(b.Jobs.Names(), e.Data.String("trigger")))
+}
+
+func handleAdminJobErrors(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	name := data.String("job")
+	if data.Bool("clear") {
+		cleared, err := b.Jobs.ClearErrors(name)
+		if err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+		b.Logger.Infof("errors of job %s cleared by %s", name, e.User().Tag())
+		return common.Respondf(e.Respond, "Cleared %d errors of `%s`.", cleared, name)
+	}
+
+	errs, err := b.Jobs.Errors(name)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if len(errs) == 0 {
+		return common.Respondf(e.Respond, "`%s` has no errors.", name)
+	}
+	entries := make([]string, len(errs))
+	for i, jobErr := range errs {
+		entries[i] = fmt.Sprintf("%s `%s`", common.Timestamp(jobErr.At), common.Truncate(jobErr.Err.Error(), 300))
+	}
+	return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+		Title:     fmt.Sprintf("%d Errors of %s", len(errs), name),
+		Entries:   entries,
+		Creator:   e.User().ID,
+		Ephemeral: true,
+	})
+}
+
+func handleAdminJobErrorsAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	return e.Result(common.AutocompleteChoices(b.Jobs.Names(), e.Data.String("job")))
+}
+
+func handleAdminJobInterval(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	name := data.String("job")
+	interval, err := time.ParseDuration(data.String("interval"))
+	if err != nil {
+		return common.RespondErrMessagef(e.Respond, "Invalid interval `%s`, use a duration like 90s, 15m or 2h", data.String("interval"))
+	}
+	status, err := b.SetJobInterval(name, interval)
+	if common.IsUserError(err) {
+		return common.RespondErr(e.Respond, err)
+	} else if err != nil {
+		return common.RespondMessageErr(e.Respond, "The interval is in effect but could not be saved: %s", err)
+	}
+	b.Logger.Infof("interval of job %s set to %s by %s", name, interval, e.User().Tag())
+
+	nextRun := "after the current run"
+	if !status.Running && !status.NextRun.IsZero() {
+		nextRun = discord.TimestampStyleRelative.FormatTime(status.NextRun)
+	}
+	return common.Respondf(e.Respond, "`%s` now runs every %s.\n**Next run:** %s", name, status.Interval, nextRun)
+}
+
+func handleAdminDocCacheTTL(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	hours := e.SlashCommandInteractionData().Int("hours")
+	expired, err := b.SetDocCacheTTL(hours)
+	if err != nil {
+		return common.RespondMessageErr(e.Respond, "The doc cache TTL is applied but failed to save it: %s", err)
+	}
+	b.Logger.Infof("doc cache ttl set to %d hours by %s", hours, e.User().Tag())
+	return common.Respondf(e.Respond, "Cached docs are now used for %d hours, %d older modules will be fetched again.", hours, expired)
+}
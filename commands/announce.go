@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+var AnnounceCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName:              "announce",
+		Description:              "Used to post and edit announcements.",
+		DefaultMemberPermissions: discord.PermissionManageMessages,
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "post",
+				Description: "Posts a new announcement.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionChannel{
+						OptionName:   "channel",
+						Description:  "The channel to post the announcement in.",
+						Required:     true,
+						ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews},
+					},
+					discord.ApplicationCommandOptionBool{
+						OptionName:  "webhook",
+						Description: "Whether to post the announcement with a webhook instead of the bot.",
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "edit",
+				Description: "Edits an announcement.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:  "message-link",
+						Description: "The link to the announcement to edit.",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	Contexts: butler.CommandContextGuild,
+	CommandHandlers: map[string]butler.HandleFunc{
+		"post": handleAnnouncePost,
+		"edit": handleAnnounceEdit,
+	},
+}
+
+func handleAnnouncePost(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	channelID := data.Snowflake("channel")
+	useWebhook := data.Bool("webhook")
+
+	if err := common.ValidateGuildChannel(e.Client(), e.GuildID(), channelID, discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	return announcementModal(b, e, discord.Embed{}, func(embed discord.Embed) error {
+		if !useWebhook {
+			_, err := b.Client.Rest().CreateMessage(channelID, discord.MessageCreate{Embeds: []discord.Embed{embed}})
+			return err
+		}
+		webhookClient, err := b.AnnouncementWebhook(channelID)
+		if err != nil {
+			return err
+		}
+		_, err = webhookClient.CreateMessage(discord.WebhookMessageCreate{Embeds: []discord.Embed{embed}})
+		return err
+	})
+}
+
+func handleAnnounceEdit(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	guildID, channelID, messageID, err := common.ParseMessageLink(e.SlashCommandInteractionData().String("message-link"))
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if e.GuildID() == nil || *e.GuildID() != guildID {
+		return common.RespondErrMessage(e.Respond, "You can only edit announcements of this server.")
+	}
+
+	message, err := b.Client.Rest().GetMessage(channelID, messageID)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if len(message.Embeds) != 1 {
+		return common.RespondErrMessage(e.Respond, "This message is not an announcement.")
+	}
+
+	return announcementModal(b, e, message.Embeds[0], func(embed discord.Embed) error {
+		return updateOwnMessage(b, channelID, messageID, nil, &[]discord.Embed{embed})
+	})
+}
+
+// announcementModal asks for the announcement with a modal prefilled from the given embed and passes the result to post.
+func announcementModal(b *butler.Butler, e *events.ApplicationCommandInteractionCreate, embed discord.Embed, post func(embed discord.Embed) error) error {
+	var color string
+	if embed.Color != 0 {
+		color = "#" + strconv.FormatInt(int64(embed.Color), 16)
+	}
+	// text inputs are shorter than embed descriptions
+	bodyLength := common.Limits.EmbedDescriptionLength
+	if bodyLength > common.MaxTextInputLength {
+		bodyLength = common.MaxTextInputLength
+	}
+	customID := discord.CustomID("announce:" + e.ID().String())
+	if err := e.CreateModal(discord.NewModalCreateBuilder().
+		SetCustomID(customID).
+		SetTitle("Announcement").
+		AddActionRow(discord.NewShortTextInput("title", "Title").
+			WithRequired(false).
+			WithMaxLength(256).
+			WithValue(embed.Title),
+		).
+		AddActionRow(discord.NewParagraphTextInput("body", "Body").
+			WithRequired(true).
+			WithMaxLength(bodyLength).
+			WithValue(common.Truncate(embed.Description, bodyLength)),
+		).
+		AddActionRow(discord.NewShortTextInput("color", "Color").
+			WithRequired(false).
+			WithMaxLength(7).
+			WithPlaceholder("#5c5fea").
+			WithValue(color),
+		).
+		Build(),
+	); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		bot.WaitForEvent(e.Client(), ctx, func(me *events.ModalSubmitInteractionCreate) bool {
+			return me.Data.CustomID == customID
+		}, func(me *events.ModalSubmitInteractionCreate) {
+			embed.Title = me.Data.Text("title")
+			embed.Description = me.Data.Text("body")
+			embed.Color = common.ColorSuccess
+			if colorText := strings.TrimPrefix(strings.TrimSpace(me.Data.Text("color")), "#"); colorText != "" {
+				parsed, err := strconv.ParseInt(colorText, 16, 32)
+				if err != nil || parsed < 0 || parsed > 0xFFFFFF {
+					_ = common.RespondErrMessagef(me.Respond, "`%s` is not a valid hex color", me.Data.Text("color"))
+					return
+				}
+				embed.Color = int(parsed)
+			}
+
+			if err := post(embed); err != nil {
+				_ = common.RespondErr(me.Respond, err)
+				return
+			}
+			if err := me.CreateMessage(discord.NewMessageCreateBuilder().
+				SetEmbeds(discord.Embed{Description: "Announcement saved.", Color: common.ColorSuccess}).
+				SetEphemeral(true).
+				Build(),
+			); err != nil {
+				b.Logger.Error("failed to respond to announcement: ", err)
+			}
+		}, func() {})
+	}()
+	return nil
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/disgoorg/disgo-butler/butler"
+)
+
+// TestCommandsValid checks all commands against Discord's limits, so invalid definitions fail before syncing them.
+func TestCommandsValid(t *testing.T) {
+	if err := butler.ValidateCommands(
+		PingCommand,
+		InfoCommand,
+		DocsCommand,
+		DocsFindCommand,
+		DocsExamplesCommand,
+		DocsPrefsCommand,
+		TagCommand,
+		TagsCommand,
+		WhoisCommand,
+		GithubCommand,
+		IssuesCommand,
+		EditMessageCommand,
+		AnnounceCommand,
+		ConfigCommand,
+		AdminCommand,
+		TicketCommand(nil),
+		ModMailCommand(nil),
+	); err != nil {
+		t.Error(err)
+	}
+}
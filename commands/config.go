@@ -3,12 +3,20 @@ package commands
 import (
 	"context"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo-butler/butler"
 	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/eventbus"
+	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
 	"github.com/disgoorg/snowflake/v2"
+	"golang.org/x/exp/slices"
 )
 
 var ConfigCommand = butler.Command{
@@ -16,6 +24,28 @@ var ConfigCommand = butler.Command{
 		CommandName: "config",
 		Description: "Used to configure aliases and release announcements.",
 		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "prefix",
+				Description: "Used to set the prefix of text commands in this server. Text commands are disabled without one.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:  "prefix",
+						Description: "The prefix of text commands. Leave empty to disable text commands.",
+						MaxLength:   json.NewPtr(5),
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "verbose",
+				Description: "Used to show diagnostic details like created IDs in the responses of /config in this server.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionBool{
+						OptionName:  "enabled",
+						Description: "Whether to show the details.",
+						Required:    true,
+					},
+				},
+			},
 			discord.ApplicationCommandOptionSubCommandGroup{
 				GroupName:   "aliases",
 				Description: "Used to configure module aliases.",
@@ -51,6 +81,31 @@ var ConfigCommand = butler.Command{
 						CommandName: "list",
 						Description: "Used to list all module aliases.",
 					},
+					{
+						CommandName: "override",
+						Description: "Used to point an alias to another module in this server only.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								OptionName:  "alias",
+								Description: "The alias to override.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "module",
+								Description: "The module to use in this server. Leave empty to use the global alias again.",
+							},
+						},
+					},
+					{
+						CommandName: "dedupe",
+						Description: "Used to find aliases pointing to the same module.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionBool{
+								OptionName:  "prune",
+								Description: "Whether to remove all but the shortest alias of each module.",
+							},
+						},
+					},
 				},
 			},
 			discord.ApplicationCommandOptionSubCommandGroup{
@@ -67,15 +122,42 @@ var ConfigCommand = butler.Command{
 								Required:    true,
 							},
 							discord.ApplicationCommandOptionChannel{
-								OptionName:  "channel",
-								Description: "The channel to release the announcement in.",
-								Required:    true,
+								OptionName:   "channel",
+								Description:  "The channel to release the announcement in.",
+								Required:     true,
+								ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews},
 							},
 							discord.ApplicationCommandOptionRole{
 								OptionName:  "ping-role",
 								Description: "The role you want to ping when a new release is available.",
 								Required:    true,
 							},
+							discord.ApplicationCommandOptionChannel{
+								OptionName:   "thread",
+								Description:  "The thread in the channel to release the announcement in.",
+								ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildPublicThread, discord.ChannelTypeGuildNewsThread},
+							},
+							discord.ApplicationCommandOptionInt{
+								OptionName:  "digest",
+								Description: "Collect releases and post them as a single digest instead of one message each.",
+								Choices: []discord.ApplicationCommandOptionChoiceInt{
+									{Name: "Daily", Value: 24},
+									{Name: "Weekly", Value: 168},
+								},
+							},
+							discord.ApplicationCommandOptionBool{
+								OptionName:  "discussion-thread",
+								Description: "Whether to create a thread for discussion on each announcement.",
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "username",
+								Description: "The name to post the announcements with instead of the webhook's.",
+								MaxLength:   json.NewPtr(80),
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "avatar-url",
+								Description: "The URL of the avatar to post the announcements with instead of the webhook's.",
+							},
 						},
 					},
 					{
@@ -89,10 +171,59 @@ var ConfigCommand = butler.Command{
 							},
 						},
 					},
+					{
+						CommandName: "move",
+						Description: "Used to move a release announcement to another channel.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								OptionName:  "name",
+								Description: "The release announcement you want to move.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionChannel{
+								OptionName:   "channel",
+								Description:  "The channel to move the release announcement to.",
+								Required:     true,
+								ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews},
+							},
+						},
+					},
+					{
+						CommandName: "identity",
+						Description: "Used to change the name and avatar a release announcement is posted with.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								OptionName:  "name",
+								Description: "The release announcement you want to change.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "username",
+								Description: "The name to post the announcements with instead of the webhook's.",
+								MaxLength:   json.NewPtr(80),
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "avatar-url",
+								Description: "The URL of the avatar to post the announcements with instead of the webhook's.",
+							},
+						},
+					},
 					{
 						CommandName: "list",
 						Description: "Used to list all release announcements.",
 					},
+					{
+						CommandName: "import",
+						Description: "Used to import release announcements from existing webhooks in a channel.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionChannel{
+								OptionName:   "channel",
+								Description:  "The channel to import the webhooks of.",
+								Required:     true,
+								ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews},
+							},
+						},
+					},
 				},
 			},
 			discord.ApplicationCommandOptionSubCommandGroup{
@@ -115,6 +246,26 @@ var ConfigCommand = butler.Command{
 							},
 						},
 					},
+					{
+						CommandName: "add-org",
+						Description: "Used to add all repositories of a GitHub organization as contributor repositories.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								OptionName:  "org",
+								Description: "The GitHub organization.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionRole{
+								OptionName:  "role",
+								Description: "The role to assign if a user is a contributor to any of the repositories.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "exclude",
+								Description: "Comma separated patterns of repository names to skip, e.g. *-archive,docs",
+							},
+						},
+					},
 					{
 						CommandName: "remove",
 						Description: "Used to remove a contributor repositories.",
@@ -124,150 +275,866 @@ var ConfigCommand = butler.Command{
 								Description: "The contributor repository you want to remove.",
 								Required:    true,
 							},
+							discord.ApplicationCommandOptionBool{
+								OptionName:  "strip-role",
+								Description: "Whether to also remove the role of the repository from all members who have it.",
+							},
 						},
 					},
 					{
 						CommandName: "list",
 						Description: "Used to list all contributor repositories.",
 					},
+					{
+						CommandName: "grant",
+						Description: "Used to manually grant the contributor role of a repository to a user.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionUser{
+								OptionName:  "user",
+								Description: "The user to grant the contributor role to.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "name",
+								Description: "The contributor repository.",
+								Required:    true,
+							},
+						},
+					},
+					{
+						CommandName: "revoke",
+						Description: "Used to revoke a manually granted contributor role from a user.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionUser{
+								OptionName:  "user",
+								Description: "The user to revoke the contributor role from.",
+								Required:    true,
+							},
+							discord.ApplicationCommandOptionString{
+								OptionName:  "name",
+								Description: "The contributor repository.",
+								Required:    true,
+							},
+						},
+					},
+				},
+			},
+			discord.ApplicationCommandOptionSubCommandGroup{
+				GroupName:   "channels",
+				Description: "Used to limit commands to channels.",
+				Options: []discord.ApplicationCommandOptionSubCommand{
+					{
+						CommandName: "allow",
+						Description: "Used to allow a command in a channel. Commands without allowed channels work anywhere.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								OptionName:   "command",
+								Description:  "The command to allow.",
+								Required:     true,
+								Autocomplete: true,
+							},
+							discord.ApplicationCommandOptionChannel{
+								OptionName:   "channel",
+								Description:  "The channel or category to allow the command in.",
+								Required:     true,
+								ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews, discord.ChannelTypeGuildCategory},
+							},
+						},
+					},
+					{
+						CommandName: "disallow",
+						Description: "Used to remove a channel from the allowed channels of a command.",
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								OptionName:   "command",
+								Description:  "The command to disallow.",
+								Required:     true,
+								Autocomplete: true,
+							},
+							discord.ApplicationCommandOptionChannel{
+								OptionName:   "channel",
+								Description:  "The channel or category to disallow the command in.",
+								Required:     true,
+								ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews, discord.ChannelTypeGuildCategory},
+							},
+						},
+					},
+					{
+						CommandName: "list",
+						Description: "Used to list the allowed channels of all commands.",
+					},
 				},
 			},
 		},
 	},
+	Contexts:  butler.CommandContextGuild,
+	Ephemeral: true,
 	CommandHandlers: map[string]butler.HandleFunc{
-		"aliases/add":              handleAliasesAdd,
-		"aliases/remove":           handleAliasesRemove,
-		"aliases/list":             handleAliasesList,
-		"releases/add":             handleReleasesAdd,
-		"releases/remove":          handleReleasesRemove,
-		"releases/list":            handleReleasesList,
-		"contributor-repos/add":    handleContributorReposAdd,
-		"contributor-repos/remove": handleContributorReposRemove,
-		"contributor-repos/list":   handleContributorReposList,
+		"prefix":                    handlePrefix,
+		"verbose":                   handleVerbose,
+		"aliases/add":               handleAliasesAdd,
+		"aliases/remove":            handleAliasesRemove,
+		"aliases/list":              handleAliasesList,
+		"aliases/override":          handleAliasesOverride,
+		"aliases/dedupe":            handleAliasesDedupe,
+		"releases/add":              handleReleasesAdd,
+		"releases/remove":           handleReleasesRemove,
+		"releases/move":             handleReleasesMove,
+		"releases/identity":         handleReleasesIdentity,
+		"releases/list":             handleReleasesList,
+		"releases/import":           handleReleasesImport,
+		"contributor-repos/add":     handleContributorReposAdd,
+		"contributor-repos/add-org": handleContributorReposAddOrg,
+		"contributor-repos/remove":  handleContributorReposRemove,
+		"contributor-repos/list":    handleContributorReposList,
+		"contributor-repos/grant":   handleContributorReposGrant,
+		"contributor-repos/revoke":  handleContributorReposRevoke,
+		"channels/allow":            handleChannelsAllow,
+		"channels/disallow":         handleChannelsDisallow,
+		"channels/list":             handleChannelsList,
+	},
+	AutocompleteHandlers: map[string]butler.AutocompleteHandleFunc{
+		"channels/allow":    handleChannelsCommandAutocomplete,
+		"channels/disallow": handleChannelsCommandAutocomplete,
 	},
 }
 
+func handlePrefix(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.GuildID() == nil {
+		return common.RespondErr(e.Respond, common.NewUserError("this command can only be used in a server"))
+	}
+	if e.Member().Permissions.Missing(discord.PermissionManageServer) {
+		return common.RespondErr(e.Respond, common.NewUserError("you need the Manage Server permission to change the prefix"))
+	}
+
+	prefix := strings.TrimSpace(e.SlashCommandInteractionData().String("prefix"))
+	if err := b.SetGuildPrefix(*e.GuildID(), prefix); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if prefix == "" {
+		return common.Respond(e.Respond, "Text commands are now disabled.")
+	}
+	return common.Respondf(e.Respond, "Text commands now use the prefix `%s`.", prefix)
+}
+
+func handleVerbose(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.Member().Permissions.Missing(discord.PermissionManageServer) {
+		return common.RespondErr(e.Respond, common.NewUserError("you need the Manage Server permission to change the verbose mode"))
+	}
+	enabled := e.SlashCommandInteractionData().Bool("enabled")
+	if err := b.SetVerbose(*e.GuildID(), enabled); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if enabled {
+		return common.Respond(e.Respond, "Responses of /config now include diagnostic details.")
+	}
+	var verboseEverywhere bool
+	b.ReadConfig(func(cfg butler.Config) {
+		verboseEverywhere = cfg.Verbose
+	})
+	if verboseEverywhere {
+		return common.Respond(e.Respond, "Verbose mode disabled for this server, but it is still enabled for all servers in the config.")
+	}
+	return common.Respond(e.Respond, "Responses of /config no longer include diagnostic details.")
+}
+
 func handleAliasesAdd(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
-	module := data.String("module")
 	alias := data.String("alias")
+	module, err := b.NormalizeModule(data.String("module"))
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
 	go func() {
-		_, _ = b.DocClient.Search(context.TODO(), module)
+		_ = b.WarmAlias(alias, module)
 	}()
-	b.Config.Docs.Aliases[alias] = module
-	if err := butler.SaveConfig(b.Config); err != nil {
+	if err = b.SetAlias(alias, module); err != nil {
 		return common.RespondErr(e.Respond, err)
 	}
-	return common.Respondf(e.Respond, "Added alias `%s` for module `%s`.", alias, module)
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), common.Message(common.MessageAliasAdded, alias, module),
+		"Module URL: "+b.PackageURL(module),
+	)
 }
 
 func handleAliasesRemove(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
 	alias := data.String("alias")
 
-	if _, ok := b.Config.Docs.Aliases[alias]; !ok {
+	if _, ok := b.Alias(nil, alias); !ok {
+		return common.RespondErrMessagef(e.Respond, "alias `%s` does not exist", alias)
+	}
+
+	b.DocStatuses.Delete(alias)
+	if err := b.RemoveAliases(alias); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return common.Respond(e.Respond, common.Message(common.MessageAliasRemoved, alias))
+}
+
+func handleAliasesOverride(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.Member().Permissions.Missing(discord.PermissionManageServer) {
+		return common.RespondErr(e.Respond, common.NewUserError("you need the Manage Server permission to override aliases"))
+	}
+	data := e.SlashCommandInteractionData()
+	alias := data.String("alias")
+	module := strings.TrimSpace(data.String("module"))
+
+	globalModule, ok := b.Alias(nil, alias)
+	if !ok {
 		return common.RespondErrMessagef(e.Respond, "alias `%s` does not exist", alias)
 	}
+	if module != "" {
+		var err error
+		if module, err = b.NormalizeModule(module); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+	}
+	if module == "" {
+		if _, ok = b.AliasOverrides(*e.GuildID())[alias]; !ok {
+			return common.RespondErrMessagef(e.Respond, "alias `%s` is not overridden in this server", alias)
+		}
+	} else {
+		go func() {
+			_, _ = b.DocClient.Search(context.TODO(), module)
+		}()
+	}
 
-	delete(b.Config.Docs.Aliases, alias)
-	if err := butler.SaveConfig(b.Config); err != nil {
+	if err := b.SetAliasOverride(*e.GuildID(), alias, module); err != nil {
 		return common.RespondErr(e.Respond, err)
 	}
-	return common.Respondf(e.Respond, "Removed alias `%s`.", alias)
+	if module == "" {
+		return common.Respondf(e.Respond, "Alias `%s` points to `%s` again.", alias, globalModule)
+	}
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), fmt.Sprintf("Alias `%s` now points to `%s` in this server.", alias, module),
+		"Module URL: "+b.PackageURL(module),
+		fmt.Sprintf("Global module: `%s`", globalModule),
+	)
 }
 
 func handleAliasesList(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
-	var message string
-	for alias, module := range b.Config.Docs.Aliases {
-		message += fmt.Sprintf("•`%s` -> `%s`\n", alias, module)
+	overrides := b.AliasOverrides(*e.GuildID())
+	globalAliases := b.Aliases(nil)
+	var entries []string
+	for alias, module := range b.Aliases(e.GuildID()) {
+		entry := fmt.Sprintf("•`%s` -> `%s`", alias, module)
+		if _, ok := overrides[alias]; ok {
+			entry += fmt.Sprintf(" (overrides `%s`)", globalAliases[alias])
+		}
+		entries = append(entries, entry)
+	}
+	return createListPages(b, e, "Aliases", entries)
+}
+
+func handleAliasesDedupe(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	prune := e.SlashCommandInteractionData().Bool("prune")
+
+	modules := map[string][]string{}
+	for alias, module := range b.Aliases(nil) {
+		modules[module] = append(modules[module], alias)
+	}
+	var duplicates []string
+	for module, aliases := range modules {
+		if len(aliases) < 2 {
+			continue
+		}
+		// keep the shortest alias first
+		sort.Slice(aliases, func(i, j int) bool {
+			if len(aliases[i]) != len(aliases[j]) {
+				return len(aliases[i]) < len(aliases[j])
+			}
+			return aliases[i] < aliases[j]
+		})
+		duplicates = append(duplicates, module)
+	}
+	if len(duplicates) == 0 {
+		return common.Respond(e.Respond, "No module has more than one alias.")
+	}
+	sort.Strings(duplicates)
+
+	var (
+		message string
+		removed []string
+	)
+	for _, module := range duplicates {
+		aliases := modules[module]
+		message += fmt.Sprintf("•`%s` <- `%s`\n", module, strings.Join(aliases, "`, `"))
+		if !prune {
+			continue
+		}
+		for _, alias := range aliases[1:] {
+			b.DocStatuses.Delete(alias)
+			removed = append(removed, alias)
+		}
+	}
+	if !prune {
+		return common.Respondf(e.Respond, "Modules with multiple aliases:\n%s", message)
 	}
-	return common.Respondf(e.Respond, "Aliases:\n%s", message)
+	if err := b.RemoveAliases(removed...); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return common.Respondf(e.Respond, "Modules with multiple aliases:\n%s\nRemoved %d aliases, kept the shortest one of each module.", message, len(removed))
 }
 
 func handleReleasesAdd(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
 	name := data.String("name")
 	channelID := data.Snowflake("channel")
-	pingRoleID := data.Snowflake("ping-role")
+	pingRole := data.Role("ping-role")
+
+	if err := common.ValidateGuildChannel(e.Client(), e.GuildID(), channelID, discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if err := common.ValidateAssignableRole(e.GuildID(), pingRole); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	username, avatarURL := data.String("username"), data.String("avatar-url")
+	if err := validateReleaseIdentity(username, avatarURL); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	var threadID snowflake.ID
+	if thread, ok := data.OptChannel("thread"); ok {
+		if err := common.ValidateThread(e.Client(), thread.ID, channelID); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+		threadID = thread.ID
+	}
 
 	webhook, err := b.Client.Rest().CreateWebhook(channelID, discord.WebhookCreate{Name: name})
 	if err != nil {
 		return common.RespondErr(e.Respond, err)
 	}
 
-	if b.Config.GithubReleases == nil {
-		b.Config.GithubReleases = map[string]butler.GithubReleaseConfig{}
+	if err = b.SetReleaseConfig(name, butler.GithubReleaseConfig{
+		WebhookID:        webhook.ID(),
+		WebhookToken:     webhook.Token,
+		PingRole:         pingRole.ID,
+		ThreadID:         threadID,
+		DiscussionThread: data.Bool("discussion-thread"),
+		Digest: butler.ReleaseDigestConfig{
+			IntervalHours: data.Int("digest"),
+		},
+		Username:  username,
+		AvatarURL: avatarURL,
+	}); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	details := []string{
+		fmt.Sprintf("Webhook: `%s` in %s", webhook.ID(), discord.ChannelMention(channelID)),
+		fmt.Sprintf("Ping role: `%s`", pingRole.ID),
+	}
+	if threadID != 0 {
+		details = append(details, fmt.Sprintf("Thread: `%s`", threadID))
 	}
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), common.Message(common.MessageReleaseAdded, name), details...)
+}
+
+func handleReleasesRemove(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	name := data.String("name")
 
-	b.Config.GithubReleases[name] = butler.GithubReleaseConfig{
-		WebhookID:    webhook.ID(),
-		WebhookToken: webhook.Token,
-		PingRole:     pingRoleID,
+	cfg, ok := b.ReleaseConfig(name)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "release `%s` does not exist", name)
 	}
-	if err = butler.SaveConfig(b.Config); err != nil {
+
+	if err := b.RemoveReleaseConfig(name); err != nil {
 		return common.RespondErr(e.Respond, err)
 	}
-	return common.Respondf(e.Respond, "Added release announcement for `%s`.", name)
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), common.Message(common.MessageReleaseRemoved, name),
+		fmt.Sprintf("Webhook `%s` was kept, delete it with /admin webhooks if it is no longer needed.", cfg.WebhookID),
+	)
 }
 
-func handleReleasesRemove(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+func handleReleasesMove(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
 	name := data.String("name")
+	channelID := data.Snowflake("channel")
 
-	if _, ok := b.Config.GithubReleases[name]; !ok {
+	cfg, ok := b.ReleaseConfig(name)
+	if !ok {
 		return common.RespondErrMessagef(e.Respond, "release `%s` does not exist", name)
 	}
+	if err := common.ValidateGuildChannel(e.Client(), e.GuildID(), channelID, discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	permissions, err := b.SelfPermissions(*e.GuildID(), &channelID)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if permissions.Missing(discord.PermissionViewChannel | discord.PermissionManageWebhooks) {
+		return common.RespondErrMessagef(e.Respond, "the bot needs the View Channel and Manage Webhooks permissions in %s", discord.ChannelMention(channelID))
+	}
+
+	webhook, err := b.Client.Rest().CreateWebhook(channelID, discord.WebhookCreate{Name: name})
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
 
-	delete(b.Config.GithubReleases, name)
-	if err := butler.SaveConfig(b.Config); err != nil {
+	oldWebhookID := cfg.WebhookID
+	cfg.WebhookID = webhook.ID()
+	cfg.WebhookToken = webhook.Token
+	// the thread belongs to the old channel
+	cfg.ThreadID = 0
+	// the new webhook replaces the one which was found to be gone
+	broken := cfg.Broken != ""
+	cfg.Broken = ""
+	if err = b.SetReleaseConfig(name, cfg); err != nil {
 		return common.RespondErr(e.Respond, err)
 	}
-	return common.Respondf(e.Respond, "Removed release announcement for `%s`.", name)
+
+	message := fmt.Sprintf("Moved release `%s` to %s.", name, discord.ChannelMention(channelID))
+	if broken {
+		message += "\nThe paused announcements are resumed."
+	}
+	if err = b.Client.Rest().DeleteWebhook(oldWebhookID); err != nil {
+		b.Logger.Errorf("Failed to delete old webhook %s of release %s: %s", oldWebhookID, name, err)
+		message += fmt.Sprintf("\nFailed to delete the old webhook `%s`, please delete it manually.", oldWebhookID)
+	}
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), message,
+		fmt.Sprintf("New webhook: `%s`", webhook.ID()),
+		fmt.Sprintf("Old webhook: `%s`", oldWebhookID),
+	)
+}
+
+func handleReleasesIdentity(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	name := data.String("name")
+	username, avatarURL := data.String("username"), data.String("avatar-url")
+
+	cfg, ok := b.ReleaseConfig(name)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "release `%s` does not exist", name)
+	}
+	if err := validateReleaseIdentity(username, avatarURL); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	cfg.Username = username
+	cfg.AvatarURL = avatarURL
+	if err := b.SetReleaseConfig(name, cfg); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if username == "" && avatarURL == "" {
+		return common.Respondf(e.Respond, "Release `%s` is posted with the name and avatar of its webhook again.", name)
+	}
+	message := fmt.Sprintf("Release `%s` is now posted", name)
+	if username != "" {
+		message += fmt.Sprintf(" as **%s**", username)
+	}
+	if avatarURL != "" {
+		message += " with a custom avatar"
+	}
+	return common.Respond(e.Respond, message+".")
+}
+
+// validateReleaseIdentity checks the name and avatar a release is posted with.
+func validateReleaseIdentity(username string, avatarURL string) error {
+	if err := common.ValidateWebhookUsername(username); err != nil {
+		return err
+	}
+	return common.ValidateAvatarURL(avatarURL)
 }
 
 func handleReleasesList(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	var entries []string
+	for name, cfg := range b.ReleaseConfigs() {
+		entry := fmt.Sprintf("•`%s`", name)
+		if cfg.ThreadID != 0 {
+			entry += " in " + discord.ChannelMention(cfg.ThreadID)
+		}
+		if cfg.Username != "" {
+			entry += fmt.Sprintf(" as **%s**", cfg.Username)
+		}
+		if cfg.Digest.Enabled() {
+			entry += fmt.Sprintf(" (digest every %dh)", cfg.Digest.IntervalHours)
+		}
+		if cfg.Broken != "" {
+			entry += " ⛔ paused, webhook is broken"
+		}
+		entries = append(entries, entry)
+	}
+	return createListPages(b, e, "Releases", entries)
+}
+
+func handleReleasesImport(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	channelID := e.SlashCommandInteractionData().Snowflake("channel")
+	if err := common.ValidateGuildChannel(e.Client(), e.GuildID(), channelID, discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	webhooks, skipped, err := b.ImportableWebhooks(channelID)
+	if err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to get the webhooks of the channel: %s", err)
+	}
+
 	var message string
-	for name := range b.Config.GithubReleases {
-		message += fmt.Sprintf("•`%s`\n", name)
+	for _, wh := range skipped {
+		message += fmt.Sprintf("•Skipped **%s** `%s`: %s\n", wh.Name, wh.ID, wh.Reason)
 	}
-	return common.Respondf(e.Respond, "Releases:\n%s", message)
+	if len(webhooks) == 0 {
+		return common.RespondErrMessagef(e.Respond, "No importable webhooks found in %s.\n%s", discord.ChannelMention(channelID), message)
+	}
+
+	options := make([]discord.SelectMenuOption, 0, len(webhooks))
+	for i, wh := range webhooks {
+		if i == 25 {
+			message += fmt.Sprintf("Only the first 25 of %d webhooks can be selected, import them and run this again for the others.\n", len(webhooks))
+			break
+		}
+		option := discord.NewSelectMenuOption(common.Truncate(wh.Name(), 100), wh.ID().String())
+		if wh.User.ID != 0 {
+			option = option.WithDescription(common.Truncate("Created by "+wh.User.Tag(), 100))
+		}
+		options = append(options, option)
+	}
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContentf("Select a webhook in %s to import as a release announcement.\n%s", discord.ChannelMention(channelID), common.Truncate(message, 1500)).
+		AddActionRow(discord.NewSelectMenu(discord.CustomID("releases_import:"+channelID.String()), "Webhook", options...)).
+		SetEphemeral(true).
+		Build(),
+	)
 }
 
 func handleContributorReposAdd(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
 	name := data.String("name")
-	roleID := data.Snowflake("role")
+	role := data.Role("role")
 
-	if b.Config.ContributorRepos == nil {
-		b.Config.ContributorRepos = map[string]snowflake.ID{}
+	if err := common.ValidateAssignableRole(e.GuildID(), role); err != nil {
+		return common.RespondErr(e.Respond, err)
 	}
 
-	b.Config.ContributorRepos[name] = roleID
-	if err := butler.SaveConfig(b.Config); err != nil {
+	if err := b.SetContributorRepos(role.ID, name); err != nil {
 		return common.RespondErr(e.Respond, err)
 	}
-	return common.Respondf(e.Respond, "Added contributor repository `%s`.", name)
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), common.Message(common.MessageRepoAdded, name),
+		fmt.Sprintf("Contributor role: `%s`", role.ID),
+	)
+}
+
+func handleContributorReposAddOrg(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	org := data.String("org")
+	role := data.Role("role")
+
+	if err := common.ValidateAssignableRole(e.GuildID(), role); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	var patterns []string
+	for _, pattern := range strings.Split(data.String("exclude"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return common.RespondErrMessagef(e.Respond, "`%s` is not a valid pattern", pattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	repos, err := b.OrgRepos(ctx, org)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	var (
+		added                      []string
+		skipped, excluded, existed int
+	)
+	for _, repo := range repos {
+		if repo.GetFork() || repo.GetArchived() {
+			skipped++
+			continue
+		}
+		if matchesAny(patterns, repo.GetName()) {
+			excluded++
+			continue
+		}
+		// keep repositories which are already configured, possibly with another role
+		if _, ok := b.ContributorRepo(repo.GetFullName()); ok {
+			existed++
+			continue
+		}
+		added = append(added, repo.GetFullName())
+	}
+	sort.Strings(added)
+
+	summary := fmt.Sprintf("Excluded by pattern: %d\nSkipped forks and archived: %d\nAlready configured: %d", excluded, skipped, existed)
+	if len(added) == 0 {
+		return common.RespondErrMessagef(e.Respond, "No repositories of `%s` left to add.\n%s", org, summary)
+	}
+	names := common.Truncate("`"+strings.Join(added, "`, `")+"`", 2048)
+
+	confirmID := discord.CustomID("contributor_repo_org:confirm:" + e.ID().String())
+	cancelID := discord.CustomID("contributor_repo_org:cancel:" + e.ID().String())
+	if err = common.RespondComponentsf(e.Respond, "Add %d repositories of `%s` with %s?\n%s\n\n%s",
+		[]discord.ContainerComponent{discord.NewActionRow(discord.NewPrimaryButton("Add", confirmID), discord.NewSecondaryButton("Cancel", cancelID))},
+		len(added), org, discord.RoleMention(role.ID), names, summary,
+	); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		bot.WaitForEvent(e.Client(), ctx, func(ce *events.ComponentInteractionCreate) bool {
+			return ce.User().ID == e.User().ID && (ce.Data.CustomID() == confirmID || ce.Data.CustomID() == cancelID)
+		}, func(ce *events.ComponentInteractionCreate) {
+			if err := ce.DeferUpdateMessage(); err != nil {
+				b.Logger.Error("failed to acknowledge contributor repository import: ", err)
+			}
+			message := "Cancelled."
+			if ce.Data.CustomID() == confirmID {
+				message = addContributorRepos(b, added, role.ID)
+			}
+			if _, err := e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), discord.MessageUpdate{
+				Embeds:     &[]discord.Embed{{Description: message, Color: common.ColorSuccess}},
+				Components: &[]discord.ContainerComponent{},
+			}); err != nil {
+				b.Logger.Error("failed to update contributor repository import: ", err)
+			}
+		}, func() {
+			if _, err := e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), discord.MessageUpdate{
+				Embeds:     &[]discord.Embed{{Description: "Confirmation timed out.", Color: common.ColorError}},
+				Components: &[]discord.ContainerComponent{},
+			}); err != nil {
+				b.Logger.Error("failed to update contributor repository import: ", err)
+			}
+		})
+	}()
+	return nil
+}
+
+// matchesAny reports whether the name matches any of the path.Match patterns.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// addContributorRepos adds the repositories with the role and returns the result message.
+func addContributorRepos(b *butler.Butler, repos []string, roleID snowflake.ID) string {
+	if err := b.SetContributorRepos(roleID, repos...); err != nil {
+		b.Logger.Errorf("Failed to save config: %s", err)
+		return fmt.Sprintf("Failed to add contributor repositories: `%s`", err)
+	}
+	return fmt.Sprintf("Added %d contributor repositories with %s.", len(repos), discord.RoleMention(roleID))
 }
 
 func handleContributorReposRemove(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
 	name := data.String("name")
 
-	if _, ok := b.Config.ContributorRepos[name]; !ok {
+	roleID, ok := b.ContributorRepo(name)
+	if !ok {
 		return common.RespondErrMessagef(e.Respond, "contributor repository `%s` does not exist", name)
 	}
 
-	delete(b.Config.ContributorRepos, name)
-	if err := butler.SaveConfig(b.Config); err != nil {
-		return common.RespondErr(e.Respond, err)
+	if !data.Bool("strip-role") {
+		if err := b.RemoveContributorRepo(name); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+		return common.Respond(e.Respond, common.Message(common.MessageRepoRemoved, name))
+	}
+
+	for repo, repoRoleID := range b.ContributorRepos() {
+		if repo != name && repoRoleID == roleID {
+			return common.RespondErrMessagef(e.Respond, "%s is still used by contributor repository `%s`", discord.RoleMention(roleID), repo)
+		}
+	}
+
+	confirmID := discord.CustomID("contributor_repo_strip:confirm:" + e.ID().String())
+	cancelID := discord.CustomID("contributor_repo_strip:cancel:" + e.ID().String())
+	if err := common.RespondComponentsf(e.Respond, "This removes `%s` and %s from **all** members who have it. Are you sure?",
+		[]discord.ContainerComponent{discord.NewActionRow(discord.NewDangerButton("Remove", confirmID), discord.NewSecondaryButton("Cancel", cancelID))},
+		name, discord.RoleMention(roleID),
+	); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		bot.WaitForEvent(e.Client(), ctx, func(ce *events.ComponentInteractionCreate) bool {
+			return ce.User().ID == e.User().ID && (ce.Data.CustomID() == confirmID || ce.Data.CustomID() == cancelID)
+		}, func(ce *events.ComponentInteractionCreate) {
+			if err := ce.DeferUpdateMessage(); err != nil {
+				b.Logger.Error("failed to acknowledge contributor repository removal: ", err)
+			}
+			message := "Cancelled."
+			if ce.Data.CustomID() == confirmID {
+				message = stripContributorRepo(b, name, roleID)
+			}
+			if _, err := e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), discord.MessageUpdate{
+				Embeds:     &[]discord.Embed{{Description: message, Color: common.ColorSuccess}},
+				Components: &[]discord.ContainerComponent{},
+			}); err != nil {
+				b.Logger.Error("failed to update contributor repository removal: ", err)
+			}
+		}, func() {
+			if _, err := e.Client().Rest().UpdateInteractionResponse(e.ApplicationID(), e.Token(), discord.MessageUpdate{
+				Embeds:     &[]discord.Embed{{Description: "Confirmation timed out.", Color: common.ColorError}},
+				Components: &[]discord.ContainerComponent{},
+			}); err != nil {
+				b.Logger.Error("failed to update contributor repository removal: ", err)
+			}
+		})
+	}()
+	return nil
+}
+
+// stripContributorRepo removes the contributor repository and its role from all members and returns the result message.
+func stripContributorRepo(b *butler.Butler, name string, roleID snowflake.ID) string {
+	if err := b.RemoveContributorRepo(name); err != nil {
+		b.Logger.Errorf("Failed to save config: %s", err)
+		return fmt.Sprintf("Failed to remove contributor repository `%s`: `%s`", name, err)
 	}
-	return common.Respondf(e.Respond, "Removed contributor repository `%s`.", name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	affected, err := b.StripRole(ctx, b.GuildID(), roleID)
+	if err != nil {
+		b.Logger.Errorf("Failed to strip role %s: %s", roleID, err)
+		return fmt.Sprintf("%s\nFailed to remove %s after %d members: `%s`", common.Message(common.MessageRepoRemoved, name), discord.RoleMention(roleID), affected, err)
+	}
+	return fmt.Sprintf("%s\nRemoved %s from %d members.", common.Message(common.MessageRepoRemoved, name), discord.RoleMention(roleID), affected)
 }
 
 func handleContributorReposList(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
-	var message string
-	for name, roleID := range b.Config.ContributorRepos {
-		message += fmt.Sprintf("•`%s` -> %s\n", name, discord.RoleMention(roleID))
+	var entries []string
+	for name, roleID := range b.ContributorRepos() {
+		entries = append(entries, fmt.Sprintf("•`%s` -> %s", name, discord.RoleMention(roleID)))
+	}
+	return createListPages(b, e, "Repositories", entries)
+}
+
+func handleContributorReposGrant(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	user := data.User("user")
+	name := data.String("name")
+
+	roleID, ok := b.ContributorRepo(name)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "contributor repository `%s` does not exist", name)
+	}
+
+	if err := b.DB.AddContributorOverride(user.ID, name, e.User().ID); err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to save contributor override: %s", err)
+	}
+	if err := e.Client().Rest().AddMemberRole(b.GuildID(), user.ID, roleID); err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to add contributor role: %s", err)
+	}
+	b.Events.Publish(eventbus.ContributorRoleAdded{
+		UserID: user.ID,
+		RoleID: roleID,
+	})
+	return common.Respondf(e.Respond, "Granted %s to %s for `%s`.", discord.RoleMention(roleID), discord.UserMention(user.ID), name)
+}
+
+func handleContributorReposRevoke(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	user := data.User("user")
+	name := data.String("name")
+
+	roleID, ok := b.ContributorRepo(name)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "contributor repository `%s` does not exist", name)
+	}
+
+	removed, err := b.DB.RemoveContributorOverride(user.ID, name)
+	if err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to remove contributor override: %s", err)
+	}
+	if !removed {
+		return common.RespondErrMessagef(e.Respond, "%s has no manually granted contributor role for `%s`", discord.UserMention(user.ID), name)
+	}
+	if err = e.Client().Rest().RemoveMemberRole(b.GuildID(), user.ID, roleID); err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to remove contributor role: %s", err)
+	}
+	return common.Respondf(e.Respond, "Revoked %s from %s for `%s`.", discord.RoleMention(roleID), discord.UserMention(user.ID), name)
+}
+
+func handleChannelsAllow(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.Member().Permissions.Missing(discord.PermissionManageServer) {
+		return common.RespondErr(e.Respond, common.NewUserError("you need the Manage Server permission to limit commands to channels"))
+	}
+	data := e.SlashCommandInteractionData()
+	command := strings.TrimPrefix(data.String("command"), "/")
+	channelID := data.Snowflake("channel")
+
+	if err := b.AllowChannel(*e.GuildID(), command, channelID); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return common.RespondVerbose(e.Respond, b.Verbose(e.GuildID()), fmt.Sprintf("`/%s` can now be used in %s.", command, discord.ChannelMention(channelID)),
+		fmt.Sprintf("Channel: `%s`", channelID),
+	)
+}
+
+func handleChannelsDisallow(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	if e.Member().Permissions.Missing(discord.PermissionManageServer) {
+		return common.RespondErr(e.Respond, common.NewUserError("you need the Manage Server permission to limit commands to channels"))
+	}
+	data := e.SlashCommandInteractionData()
+	command := strings.TrimPrefix(data.String("command"), "/")
+	channelID := data.Snowflake("channel")
+
+	removed, err := b.DisallowChannel(*e.GuildID(), command, channelID)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if !removed {
+		return common.RespondErrMessagef(e.Respond, "`/%s` is not allowed in %s", command, discord.ChannelMention(channelID))
+	}
+	allowlists, err := b.GuildChannelAllowlists(*e.GuildID())
+	if err == nil && len(allowlists[command]) == 0 {
+		return common.Respondf(e.Respond, "`/%s` can no longer be used in %s and now works in all channels.", command, discord.ChannelMention(channelID))
+	}
+	return common.Respondf(e.Respond, "`/%s` can no longer be used in %s.", command, discord.ChannelMention(channelID))
+}
+
+func handleChannelsList(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	allowlists, err := b.GuildChannelAllowlists(*e.GuildID())
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if len(allowlists) == 0 {
+		return common.Respond(e.Respond, "All commands can be used in every channel.")
+	}
+
+	var entries []string
+	for command, channelIDs := range allowlists {
+		mentions := make([]string, len(channelIDs))
+		for i, channelID := range channelIDs {
+			mentions[i] = discord.ChannelMention(channelID)
+		}
+		entries = append(entries, fmt.Sprintf("•`/%s` -> %s", command, strings.Join(mentions, ", ")))
+	}
+	return createListPages(b, e, "Allowed Channels", entries)
+}
+
+// createListPages responds with the sorted entries as filterable pages.
+func createListPages(b *butler.Butler, e *events.ApplicationCommandInteractionCreate, title string, entries []string) error {
+	if len(entries) == 0 {
+		return common.Respondf(e.Respond, "%s:\nNone", title)
+	}
+	sort.Strings(entries)
+	return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+		Title:   title,
+		Entries: entries,
+		Creator: e.User().ID,
+	})
+}
+
+func handleChannelsCommandAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	commands := make([]string, 0, len(b.Commands))
+	for name := range b.Commands {
+		if !slices.Contains(butler.ChannelAllowlistExempt, name) {
+			commands = append(commands, name)
+		}
 	}
-	return common.Respondf(e.Respond, "Repositories:\n%s", message)
+	return e.Result(common.AutocompleteChoices(commands, e.Data.String("command")))
 }
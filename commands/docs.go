@@ -2,16 +2,20 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sort"
+	"net"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo-butler/butler"
 	"github.com/disgoorg/disgo-butler/common"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
 	"github.com/hhhapz/doc"
-	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
 var DocsCommand = butler.Command{
@@ -31,25 +35,156 @@ var DocsCommand = butler.Command{
 				Required:     true,
 				Autocomplete: true,
 			},
+			butler.EphemeralOption,
 		},
 	},
 	CommandHandlers: map[string]butler.HandleFunc{
 		"": handleDocs,
 	},
+	OnError: handleDocsError,
 	AutocompleteHandlers: map[string]butler.AutocompleteHandleFunc{
 		"": handleDocsAutocomplete,
 	},
 }
 
+var DocsFindCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName: "docs-find",
+		Description: "Searches all configured modules for a type, function, etc.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				OptionName:  "symbol",
+				Description: "The symbol to search for. Example: MessageCreate",
+				Required:    true,
+			},
+			discord.ApplicationCommandOptionBool{
+				OptionName:  "stdlib",
+				Description: "Whether to search the standard library too.",
+			},
+			butler.EphemeralOption,
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"": handleDocsFind,
+	},
+}
+
+var DocsExamplesCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName: "docs-examples",
+		Description: "Shows the examples of a module or one of its types, functions, etc.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				OptionName:   "module",
+				Description:  "The module to show the examples of. Example: github.com/disgoorg/disgo/discord",
+				Required:     true,
+				Autocomplete: true,
+			},
+			discord.ApplicationCommandOptionString{
+				OptionName:   "symbol",
+				Description:  "The symbol to show the examples of. Defaults to the examples of the module.",
+				Autocomplete: true,
+			},
+			butler.EphemeralOption,
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"": handleDocsExamples,
+	},
+	OnError: handleDocsError,
+	AutocompleteHandlers: map[string]butler.AutocompleteHandleFunc{
+		"": handleDocsExamplesAutocomplete,
+	},
+}
+
+var DocsPrefsCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName: "docs-prefs",
+		Description: "Shows or changes how your /docs results are shown.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				OptionName:  "style",
+				Description: "Whether results are compact, the default or show the whole comment.",
+				Choices: []discord.ApplicationCommandOptionChoiceString{
+					{Name: "compact", Value: butler.DocsStyleCompact},
+					{Name: "default", Value: butler.DocsStyleDefault},
+					{Name: "full", Value: butler.DocsStyleFull},
+				},
+			},
+			discord.ApplicationCommandOptionBool{
+				OptionName:  "source",
+				Description: "Whether to always show the whole declaration.",
+			},
+			discord.ApplicationCommandOptionBool{
+				OptionName:  "examples",
+				Description: "Whether to show examples.",
+			},
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"": handleDocsPrefs,
+	},
+}
+
+// resolveModule returns the module of the alias or the normalized module path the user entered.
+func resolveModule(b *butler.Butler, guildID *snowflake.ID, module string) (string, error) {
+	if aliasModule, ok := b.Alias(guildID, module); ok {
+		return aliasModule, nil
+	}
+	return b.NormalizeModule(module)
+}
+
+func searchDocs(b *butler.Butler, module string) (doc.Package, error) {
+	pkg, err := b.DocClient.Search(context.Background(), module)
+	var statusErr doc.InvalidStatusError
+	if errors.As(err, &statusErr) && statusErr == http.StatusNotFound {
+		return pkg, common.NewUserErrorf("module `%s` not found", module)
+	}
+	return pkg, err
+}
+
+// handleDocsError explains failures of pkg.go.dev instead of showing a generic error.
+func handleDocsError(_ *butler.Butler, e *events.ApplicationCommandInteractionCreate, err error) error {
+	var (
+		statusErr doc.InvalidStatusError
+		netErr    net.Error
+	)
+	if (errors.As(err, &statusErr) && statusErr >= http.StatusInternalServerError) || errors.As(err, &netErr) {
+		return common.RespondErrMessage(e.Respond, "pkg.go.dev seems to be unavailable right now. Please try again later.")
+	}
+	return common.RespondErr(e.Respond, err)
+}
+
 func handleDocs(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
 	data := e.SlashCommandInteractionData()
 
-	pkg, err := b.DocClient.Search(context.Background(), data.String("module"))
+	module, err := resolveModule(b, e.GuildID(), data.String("module"))
 	if err != nil {
-		return common.RespondErr(e.Respond, err)
+		return err
+	}
+	pkg, err := searchDocs(b, module)
+	if err != nil {
+		return err
 	}
 
-	embed, selectMenu := butler.GetDocsEmbed(pkg, data.String("query"), false, false, false, false)
+	if data.String("query") == butler.PkgSymbols {
+		return handleDocsSymbols(b, e, pkg)
+	}
+
+	prefs, err := b.DocsPrefs(e.User().ID)
+	if err != nil && !butler.IsCachedFailure(err) {
+		b.Logger.Errorf("Failed to get docs preferences of %s: %s", e.User().ID, err)
+	}
+	var (
+		embed      discord.Embed
+		selectMenu discord.SelectMenuComponent
+	)
+	if prefs.Style == butler.DocsStyleCompact {
+		embed, selectMenu = b.GetCompactDocsEmbed(pkg, data.String("query"))
+	} else {
+		expandSignature, expandComment, expandExamples := prefs.Expanded()
+		embed, selectMenu = b.GetDocsEmbed(pkg, data.String("query"), expandSignature, expandComment, false, expandExamples)
+	}
 
 	return e.CreateMessage(discord.NewMessageCreateBuilder().
 		SetEmbeds(embed).
@@ -58,50 +193,142 @@ func handleDocs(b *butler.Butler, e *events.ApplicationCommandInteractionCreate)
 	)
 }
 
+func handleDocsPrefs(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	prefs, err := b.DocsPrefs(e.User().ID)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	var changed bool
+	if style, ok := data.OptString("style"); ok {
+		prefs.Style, changed = style, true
+	}
+	if source, ok := data.OptBool("source"); ok {
+		prefs.Source, changed = source, true
+	}
+	if examples, ok := data.OptBool("examples"); ok {
+		prefs.Examples, changed = examples, true
+	}
+	title := "Your docs preferences"
+	if changed {
+		if err = b.SetDocsPrefs(e.User().ID, prefs); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+		title = "Docs preferences saved"
+	}
+
+	style := prefs.Style
+	if style == "" {
+		style = butler.DocsStyleDefault
+	}
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetEmbeds(discord.NewEmbedBuilder().
+			SetTitle(title).
+			SetColor(common.ColorSuccess).
+			AddField("Style", style, true).
+			AddField("Source", strconv.FormatBool(prefs.Source), true).
+			AddField("Examples", strconv.FormatBool(prefs.Examples), true).
+			Build(),
+		).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+func handleDocsSymbols(b *butler.Butler, e *events.ApplicationCommandInteractionCreate, pkg doc.Package) error {
+	pages := b.GetDocsSymbolPages(pkg)
+	if len(pages) == 0 {
+		return common.RespondErrMessagef(e.Respond, "No exported symbols found in `%s`.", pkg.URL)
+	}
+
+	return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+		Title: pkg.URL,
+		URL:   b.PackageURL(pkg.URL),
+		Pages: pages,
+	})
+}
+
+func handleDocsExamples(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+
+	module, err := resolveModule(b, e.GuildID(), data.String("module"))
+	if err != nil {
+		return err
+	}
+	pkg, err := searchDocs(b, module)
+	if err != nil {
+		return err
+	}
+
+	symbol := data.String("symbol")
+	examples, ok := b.FindDocsExamples(pkg, symbol)
+	if !ok {
+		return common.RespondErrMessagef(e.Respond, "`%s` not found in `%s`.", symbol, pkg.URL)
+	}
+	title := pkg.URL
+	if symbol != "" {
+		title += "." + symbol
+	}
+	if len(examples) == 0 {
+		return common.Respondf(e.Respond, "`%s` has no examples.", title)
+	}
+
+	return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+		Title: fmt.Sprintf("Examples of %s", title),
+		URL:   b.PackageURL(pkg.URL),
+		Pages: butler.GetDocsExamplePages(examples),
+	})
+}
+
+func handleDocsExamplesAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	if option, ok := e.Data.Option("symbol"); ok && option.Focused {
+		return handleQueryAutocomplete(b, e, e.Data.String("module"), e.Data.String("symbol"), false)
+	}
+	return handleModuleAutocomplete(b, e, e.Data.String("module"))
+}
+
 func handleDocsAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
 	moduleOption, moduleOptionOk := e.Data.Option("module")
 	if moduleOptionOk && moduleOption.Focused {
 		return handleModuleAutocomplete(b, e, e.Data.String("module"))
 	}
 	if option, ok := e.Data.Option("query"); ok && option.Focused {
-		return handleQueryAutocomplete(b, e, e.Data.String("module"), e.Data.String("query"))
+		return handleQueryAutocomplete(b, e, e.Data.String("module"), e.Data.String("query"), true)
 	}
 	return e.Result(nil)
 }
 
 func handleModuleAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate, module string) error {
-	choices := make([]discord.AutocompleteChoiceString, 0, 25)
-	if module == "" {
-		b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
-			for _, pkg := range cache {
-				if len(choices) > 24 {
-					return
-				}
-				choices = append(choices, discord.AutocompleteChoiceString{Name: pkg.URL, Value: pkg.URL})
-			}
-		})
-	} else {
+	if normalized, err := b.NormalizeModule(module); err == nil {
+		module = normalized
 		_, _ = b.DocClient.Search(context.TODO(), module)
-		b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
-			var packages []string
-			for _, pkg := range cache {
-				packages = append(packages, pkg.URL)
+	}
+	var packages []string
+	b.DocClient.WithCache(func(cache map[string]*doc.CachedPackage) {
+		for _, pkg := range cache {
+			packages = append(packages, pkg.URL)
+			if module != "" {
 				packages = append(packages, pkg.Subpackages...)
 			}
-			ranks := fuzzy.RankFindFold(module, packages)
-			sort.Sort(ranks)
-			for _, rank := range ranks {
-				if len(choices) > 24 {
-					break
-				}
-				choices = append(choices, discord.AutocompleteChoiceString{Name: rank.Target, Value: rank.Target})
-			}
-		})
+		}
+	})
+
+	matches := common.MatchAutocomplete(packages, module)
+	choices := make([]discord.AutocompleteChoiceString, len(matches))
+	for i, match := range matches {
+		choices[i] = discord.AutocompleteChoiceString{Name: match, Value: match}
 	}
-	return e.Result(replaceAliases(b, choices))
+	return e.Result(replaceAliases(b, e.GuildID(), choices))
 }
 
-func handleQueryAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate, module string, query string) error {
+// handleQueryAutocomplete suggests the symbols of the module matching the query. pkgChoices adds the choices for the
+// package info and symbols list of /docs.
+func handleQueryAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate, module string, query string, pkgChoices bool) error {
+	module, err := resolveModule(b, e.GuildID(), module)
+	if err != nil {
+		return e.Result(nil)
+	}
 	pkg, err := b.DocClient.Search(context.Background(), module)
 	if err == doc.InvalidStatusError(404) {
 		return e.Result([]discord.AutocompleteChoice{
@@ -110,9 +337,10 @@ func handleQueryAutocomplete(b *butler.Butler, e *events.AutocompleteInteraction
 	} else if err != nil {
 		return e.Result(nil)
 	}
-	choices := make([]discord.AutocompleteChoiceString, 0, 25)
-	if query == "" {
+	choices := make([]discord.AutocompleteChoiceString, 0, common.MaxAutocompleteChoices)
+	if query == "" && pkgChoices {
 		choices = append(choices, discord.AutocompleteChoiceString{Name: "<Pkg Info>", Value: butler.PkgInfo})
+		choices = append(choices, discord.AutocompleteChoiceString{Name: "<Pkg Symbols>", Value: butler.PkgSymbols})
 	}
 	var symbols []string
 	for _, t := range pkg.Types {
@@ -124,22 +352,20 @@ func handleQueryAutocomplete(b *butler.Butler, e *events.AutocompleteInteraction
 	for _, f := range pkg.Functions {
 		symbols = append(symbols, f.Name)
 	}
-	ranks := fuzzy.RankFindFold(query, symbols)
-	sort.Sort(ranks)
-
-	for _, rank := range ranks {
-		if len(choices) == 25 {
+	for _, match := range common.MatchAutocomplete(symbols, query) {
+		if len(choices) == common.MaxAutocompleteChoices {
 			break
 		}
-		choices = append(choices, discord.AutocompleteChoiceString{Name: rank.Target, Value: rank.Target})
+		choices = append(choices, discord.AutocompleteChoiceString{Name: match, Value: match})
 	}
-	return e.Result(replaceAliases(b, choices))
+	return e.Result(replaceAliases(b, e.GuildID(), choices))
 }
 
-func replaceAliases(b *butler.Butler, choices []discord.AutocompleteChoiceString) []discord.AutocompleteChoice {
+func replaceAliases(b *butler.Butler, guildID *snowflake.ID, choices []discord.AutocompleteChoiceString) []discord.AutocompleteChoice {
+	aliases := b.Aliases(guildID)
 	newChoices := make([]discord.AutocompleteChoice, len(choices))
 	for i, choice := range choices {
-		for alias, module := range b.Config.Docs.Aliases {
+		for alias, module := range aliases {
 			if strings.HasPrefix(choice.Value, module) {
 				choice.Name = strings.Replace(choice.Name, module, alias, 1)
 			}
@@ -148,3 +374,32 @@ func replaceAliases(b *butler.Butler, choices []discord.AutocompleteChoiceString
 	}
 	return newChoices
 }
+
+func handleDocsFind(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	symbol := data.String("symbol")
+
+	// docs-find is never ephemeral by default, so the option decides alone
+	ephemeral := data.Bool(butler.EphemeralOption.OptionName)
+	if err := e.DeferCreateMessage(ephemeral); err != nil {
+		return err
+	}
+	responder := common.DeferredResponder(e.Client(), e, ephemeral)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	results, truncated := b.FindDocs(ctx, symbol, data.Bool("stdlib"))
+	if len(results) == 0 {
+		return common.RespondErrMessagef(responder, "No symbols matching `%s` found.", symbol)
+	}
+
+	pages := b.GetDocsFindPages(results)
+	title := fmt.Sprintf("Symbols matching %s", symbol)
+	if truncated {
+		title += " (truncated)"
+	}
+	return b.CreatePages(responder, e.ID().String(), butler.Pages{
+		Title: title,
+		Pages: pages,
+	})
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var EditMessageCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName:              "edit-message",
+		Description:              "Edits a message previously sent by the bot or one of its webhooks.",
+		DefaultMemberPermissions: discord.PermissionManageMessages,
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionString{
+				OptionName:  "message-link",
+				Description: "The link to the message to edit.",
+				Required:    true,
+			},
+			discord.ApplicationCommandOptionString{
+				OptionName:  "new-content",
+				Description: "The new content of the message.",
+				Required:    true,
+				MaxLength:   json.NewPtr(common.MaxMessageLength),
+			},
+		},
+	},
+	Contexts: butler.CommandContextGuild,
+	CommandHandlers: map[string]butler.HandleFunc{
+		"": handleEditMessage,
+	},
+}
+
+func handleEditMessage(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	content := data.String("new-content")
+
+	guildID, channelID, messageID, err := common.ParseMessageLink(data.String("message-link"))
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if e.GuildID() == nil || *e.GuildID() != guildID {
+		return common.RespondErrMessage(e.Respond, "You can only edit messages of this server.")
+	}
+
+	if err = updateOwnMessage(b, channelID, messageID, &content, nil); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	return common.Respond(e.Respond, "Message edited.")
+}
+
+var errNotOwnMessage = common.NewUserError("This message was not sent by the bot or one of its webhooks.")
+
+// updateOwnMessage edits a message sent by the bot or one of the webhooks it knows. nil fields are left unchanged.
+func updateOwnMessage(b *butler.Butler, channelID snowflake.ID, messageID snowflake.ID, content *string, embeds *[]discord.Embed) error {
+	message, err := b.Client.Rest().GetMessage(channelID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if message.Author.ID == b.Client.ID() {
+		_, err = b.Client.Rest().UpdateMessage(channelID, messageID, discord.MessageUpdate{Content: content, Embeds: embeds})
+		return err
+	}
+
+	if message.WebhookID == nil {
+		return errNotOwnMessage
+	}
+	webhookClient, ok := b.WebhookClient(*message.WebhookID)
+	if !ok {
+		return errNotOwnMessage
+	}
+
+	var threadID snowflake.ID
+	channel, err := b.Client.Rest().GetChannel(channelID)
+	if err != nil {
+		return err
+	}
+	if _, ok = channel.(discord.GuildThread); ok {
+		threadID = channelID
+	}
+	_, err = webhookClient.UpdateMessageInThread(messageID, discord.WebhookMessageUpdate{Content: content, Embeds: embeds}, threadID)
+	return err
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+var GithubCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName: "github",
+		Description: "Used to look up things on GitHub.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "user",
+				Description: "Shows the public profile of a GitHub user.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "login",
+						Description:  "The login of the GitHub user.",
+						Required:     true,
+						Autocomplete: true,
+					},
+					butler.EphemeralOption,
+				},
+			},
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "repo",
+				Description: "Shows information about a GitHub repository.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:   "repo",
+						Description:  "The repository in the form of owner/repo.",
+						Required:     true,
+						Autocomplete: true,
+					},
+					butler.EphemeralOption,
+				},
+			},
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"user": handleGithubUser,
+		"repo": handleGithubRepo,
+	},
+	AutocompleteHandlers: map[string]butler.AutocompleteHandleFunc{
+		"user": handleGithubUserAutocomplete,
+		"repo": handleGithubRepoAutocomplete,
+	},
+}
+
+func handleGithubUser(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	user, err := b.GithubUser(context.Background(), e.SlashCommandInteractionData().String("login"))
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	name := user.GetLogin()
+	if user.GetName() != "" {
+		name = fmt.Sprintf("%s (%s)", user.GetName(), user.GetLogin())
+	}
+	eb := discord.NewEmbedBuilder().
+		SetAuthor(name, user.GetHTMLURL(), user.GetAvatarURL()).
+		SetThumbnail(user.GetAvatarURL()).
+		SetDescription(common.Truncate(user.GetBio(), common.Limits.EmbedDescriptionLength)).
+		SetColor(common.ColorSuccess).
+		AddField("Repositories", fmt.Sprint(user.GetPublicRepos()), true).
+		AddField("Followers", fmt.Sprint(user.GetFollowers()), true).
+		AddField("Following", fmt.Sprint(user.GetFollowing()), true)
+	if user.GetCompany() != "" {
+		eb.AddField("Company", user.GetCompany(), true)
+	}
+	if user.GetLocation() != "" {
+		eb.AddField("Location", user.GetLocation(), true)
+	}
+	if user.GetBlog() != "" {
+		eb.AddField("Website", user.GetBlog(), true)
+	}
+	if user.CreatedAt != nil {
+		eb.AddField("Joined", common.Timestamp(user.GetCreatedAt().Time), false)
+	}
+
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetEmbeds(eb.Build()).
+		Build(),
+	)
+}
+
+func handleGithubUserAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	login := e.Data.String("login")
+	if login == "" {
+		return e.Result(nil)
+	}
+	logins, err := b.SearchGithubUsers(context.Background(), login)
+	if err != nil {
+		b.Logger.Debug("failed to search GitHub users: ", err)
+		return e.Result(nil)
+	}
+	return e.Result(common.AutocompleteChoices(logins, login))
+}
+
+func handleGithubRepo(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	repo, err := b.GithubRepo(context.Background(), e.SlashCommandInteractionData().String("repo"))
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	release, err := b.LatestGithubRelease(context.Background(), repo)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	language := repo.GetLanguage()
+	if language == "" {
+		language = "None"
+	}
+	license := "None"
+	if repo.License != nil {
+		license = repo.GetLicense().GetName()
+	}
+	latestRelease := "None"
+	if release != nil {
+		latestRelease = fmt.Sprintf("[%s](%s) %s", release.GetTagName(), release.GetHTMLURL(), common.Timestamp(release.GetPublishedAt().Time))
+	}
+
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetEmbeds(discord.NewEmbedBuilder().
+			SetAuthor(repo.GetFullName(), repo.GetHTMLURL(), repo.GetOwner().GetAvatarURL()).
+			SetThumbnail(repo.GetOwner().GetAvatarURL()).
+			SetDescription(common.Truncate(repo.GetDescription(), common.Limits.EmbedDescriptionLength)).
+			SetColor(common.ColorSuccess).
+			AddField("Stars", fmt.Sprint(repo.GetStargazersCount()), true).
+			AddField("Forks", fmt.Sprint(repo.GetForksCount()), true).
+			AddField("Open Issues", fmt.Sprint(repo.GetOpenIssuesCount()), true).
+			AddField("Language", language, true).
+			AddField("License", license, true).
+			AddField("Latest Release", latestRelease, false).
+			Build(),
+		).
+		Build(),
+	)
+}
+
+func handleGithubRepoAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	contributorRepos, releaseConfigs := b.ContributorRepos(), b.ReleaseConfigs()
+	repos := make([]string, 0, len(contributorRepos)+len(releaseConfigs))
+	for repo := range contributorRepos {
+		repos = append(repos, repo)
+	}
+	for repo := range releaseConfigs {
+		repos = append(repos, repo)
+	}
+	return e.Result(common.AutocompleteChoices(repos, e.Data.String("repo")))
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
+	"github.com/google/go-github/v44/github"
+)
+
+var IssuesCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName: "issues",
+		Description: "Used to find issues and pull requests in the contributor repositories.",
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionSubCommand{
+				CommandName: "search",
+				Description: "Searches issues and pull requests with the GitHub search syntax.",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						OptionName:  "query",
+						Description: "The search query, e.g. is:open label:bug.",
+						Required:    true,
+						MaxLength:   json.NewPtr(200),
+					},
+					discord.ApplicationCommandOptionString{
+						OptionName:   "repo",
+						Description:  "The repository to search in. Defaults to all contributor repositories.",
+						Autocomplete: true,
+					},
+					butler.EphemeralOption,
+				},
+			},
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"search": handleIssuesSearch,
+	},
+	AutocompleteHandlers: map[string]butler.AutocompleteHandleFunc{
+		"search": handleIssuesSearchAutocomplete,
+	},
+}
+
+func handleIssuesSearch(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	query := data.String("query")
+
+	var repos []string
+	if repo, ok := data.OptString("repo"); ok {
+		if _, ok = b.ContributorRepo(repo); !ok {
+			return common.RespondErrMessagef(e.Respond, "`%s` is not a contributor repository", repo)
+		}
+		repos = []string{repo}
+	} else {
+		for repo := range b.ContributorRepos() {
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		return common.RespondErrMessage(e.Respond, "No contributor repositories are configured.")
+	}
+
+	issues, total, err := b.SearchGithubIssues(context.Background(), query, repos)
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if len(issues) == 0 {
+		return common.Respondf(e.Respond, "No issues or pull requests match `%s`.", query)
+	}
+
+	entries := make([]string, len(issues))
+	for i, issue := range issues {
+		entries[i] = formatIssue(issue)
+	}
+	title := fmt.Sprintf("%d results for %s", total, query)
+	if total > len(issues) {
+		title = fmt.Sprintf("%d of %d results for %s", len(issues), total, query)
+	}
+	return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+		Title:   common.Truncate(title, 256),
+		Entries: entries,
+		Creator: e.User().ID,
+	})
+}
+
+// formatIssue returns a list entry of the issue or pull request with its state and labels.
+func formatIssue(issue *github.Issue) string {
+	kind := "Issue"
+	if issue.IsPullRequest() {
+		kind = "PR"
+	}
+	state := "🟢"
+	if issue.GetState() == "closed" {
+		state = "🔴"
+	}
+	repo := issue.GetRepositoryURL()
+	if i := strings.LastIndex(repo, "/repos/"); i >= 0 {
+		repo = repo[i+len("/repos/"):]
+	}
+	entry := fmt.Sprintf("%s %s [%s#%d](%s) %s", state, kind, repo, issue.GetNumber(), issue.GetHTMLURL(), common.Truncate(issue.GetTitle(), 100))
+	for _, label := range issue.Labels {
+		entry += " `" + label.GetName() + "`"
+	}
+	return entry
+}
+
+func handleIssuesSearchAutocomplete(b *butler.Butler, e *events.AutocompleteInteractionCreate) error {
+	contributorRepos := b.ContributorRepos()
+	repos := make([]string, 0, len(contributorRepos))
+	for repo := range contributorRepos {
+		repos = append(repos, repo)
+	}
+	return e.Result(common.AutocompleteChoices(repos, e.Data.String("repo")))
+}
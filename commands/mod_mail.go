@@ -0,0 +1,443 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/mod_mail"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
+)
+
+var ModMailCommand = func(m *mod_mail.ModMail) butler.Command {
+	return butler.Command{
+		Create: discord.SlashCommandCreate{
+			CommandName: "modmail",
+			Description: "Used to manage mod mail tickets.",
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "move",
+					Description: "Moves the current ticket to another channel.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionChannel{
+							OptionName:   "channel",
+							Description:  "The channel to move the ticket to.",
+							Required:     true,
+							ChannelTypes: []discord.ChannelType{discord.ChannelTypeGuildText},
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "note",
+					Description: "Adds an internal note to the current ticket which is not sent to the user.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							OptionName:  "note",
+							Description: "The note to add.",
+							Required:    true,
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "reply",
+					Description: "Replies to the user of the current ticket, optionally without showing who you are.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							OptionName:  "message",
+							Description: "The reply to send.",
+							Required:    true,
+							MaxLength:   json.NewPtr(common.Limits.EmbedDescriptionLength),
+						},
+						discord.ApplicationCommandOptionBool{
+							OptionName:  "anonymous",
+							Description: "Whether to send the reply as Staff. Defaults to the setting of this server.",
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "claim",
+					Description: "Claims the current ticket to show you are handling it.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionBool{
+							OptionName:  "exclusive",
+							Description: "Whether only your replies should be sent to the user.",
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "unclaim",
+					Description: "Removes the claim of the current ticket.",
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "close",
+					Description: "Closes the current ticket and locks its thread.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							OptionName:  "reason",
+							Description: "Why the ticket is closed, this is sent to the user.",
+							MaxLength:   json.NewPtr(common.MaxEmbedFieldLength),
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "list",
+					Description: "Lists all open tickets.",
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "stats",
+					Description: "Shows statistics about the tickets of this server.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionInt{
+							OptionName:  "days",
+							Description: "The amount of days to show. Defaults to 7 days.",
+							MinValue:    json.NewPtr(1),
+							MaxValue:    json.NewPtr(90),
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "reveal",
+					Description: "Shows you who is behind the current anonymized ticket.",
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "test",
+					Description: "Tests the mod mail setup of this server with a test ticket which is deleted afterwards.",
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					CommandName: "unblock",
+					Description: "Lets a blocked user open tickets again.",
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionUser{
+							OptionName:  "user",
+							Description: "The user to unblock.",
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+		Contexts: butler.CommandContextGuild,
+		CommandHandlers: map[string]butler.HandleFunc{
+			"move":    handleModMailMove(m),
+			"note":    handleModMailNote(m),
+			"reply":   handleModMailReply(m),
+			"claim":   handleModMailClaim(m),
+			"unclaim": handleModMailUnclaim(m),
+			"close":   handleModMailClose(m),
+			"list":    handleModMailList(m),
+			"stats":   handleModMailStats(m),
+			"reveal":  handleModMailReveal(m),
+			"unblock": handleModMailUnblock(m),
+			"test":    ownerOnly(handleModMailTest(m)),
+		},
+	}
+}
+
+func handleModMailMove(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		channelID := e.SlashCommandInteractionData().Snowflake("channel")
+		if err := common.ValidateGuildChannel(e.Client(), e.GuildID(), channelID, discord.ChannelTypeGuildText); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+
+		threadID, err := m.MoveThread(e.Client(), e.ChannelID(), channelID)
+		if common.IsUserError(err) {
+			return common.RespondErr(e.Respond, err)
+		} else if err != nil {
+			return common.RespondMessageErr(e.Respond, "Failed to move ticket: %s", err)
+		}
+
+		if err = common.Respondf(e.Respond, "Ticket moved to %s.", discord.ChannelMention(threadID)); err != nil {
+			return err
+		}
+		_, err = e.Client().Rest().UpdateChannel(e.ChannelID(), discord.GuildThreadUpdate{
+			Archived: json.NewPtr(true),
+		})
+		return err
+	}
+}
+
+func handleModMailNote(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		if !m.IsTicket(e.ChannelID()) {
+			return common.RespondErr(e.Respond, mod_mail.ErrNoTicket)
+		}
+
+		// interaction responses are webhook messages, which are never forwarded to the user
+		return e.CreateMessage(discord.NewMessageCreateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetAuthor("Internal note by "+e.User().Tag(), "", e.User().EffectiveAvatarURL()).
+				SetDescription(e.SlashCommandInteractionData().String("note")).
+				SetColor(0xFEE75C).
+				SetFooter("This note is not sent to the user", "").
+				Build(),
+			).
+			Build(),
+		)
+	}
+}
+
+func handleModMailReply(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		data := e.SlashCommandInteractionData()
+		if err := m.CheckReply(e.ChannelID(), e.User().ID); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+		anonymous, ok := data.OptBool("anonymous")
+		if !ok {
+			anonymous = m.AnonymousReplies(e.ChannelID())
+		}
+
+		// the copy in the thread shows who sent the reply, interaction responses are never forwarded themselves
+		footer := "Sent to the user"
+		if anonymous {
+			footer = "Sent to the user anonymously"
+		}
+		content := data.String("message")
+		if err := e.CreateMessage(discord.NewMessageCreateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetAuthor(e.User().Tag(), "", e.User().EffectiveAvatarURL()).
+				SetDescription(content).
+				SetFooter(footer, "").
+				Build(),
+			).
+			Build(),
+		); err != nil {
+			return err
+		}
+		message, err := e.Client().Rest().GetInteractionResponse(e.ApplicationID(), e.Token())
+		if err != nil {
+			return err
+		}
+		return m.Reply(e.Client(), e.ChannelID(), message.ID, e.User(), content, anonymous)
+	}
+}
+
+func handleModMailClaim(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		exclusive := e.SlashCommandInteractionData().Bool("exclusive")
+		previous, err := m.ClaimThread(e.Client(), e.ChannelID(), e.User(), exclusive)
+		if common.IsUserError(err) {
+			return common.RespondErr(e.Respond, err)
+		} else if err != nil {
+			b.Logger.Error("failed to rename claimed thread: ", err)
+		}
+
+		message := fmt.Sprintf("%s claimed this ticket.", e.User().Mention())
+		if previous != nil && previous.UserID != e.User().ID {
+			message = fmt.Sprintf("%s took over this ticket from %s.", e.User().Mention(), discord.UserMention(previous.UserID))
+		}
+		if exclusive {
+			message += " Only their replies are sent to the user."
+		}
+		return common.Respond(e.Respond, message)
+	}
+}
+
+func handleModMailUnclaim(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		previous, err := m.UnclaimThread(e.Client(), e.ChannelID())
+		if common.IsUserError(err) {
+			return common.RespondErr(e.Respond, err)
+		} else if err != nil {
+			b.Logger.Error("failed to rename unclaimed thread: ", err)
+		}
+		return common.Respondf(e.Respond, "%s removed the claim of %s.", e.User().Mention(), discord.UserMention(previous.UserID))
+	}
+}
+
+func handleModMailClose(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		reason := e.SlashCommandInteractionData().String("reason")
+		if err := m.CloseThread(e.Client(), e.ChannelID(), e.User(), reason); err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+
+		message := fmt.Sprintf("Ticket closed by %s.", e.User().Mention())
+		if reason != "" {
+			message += "\n**Reason:** " + reason
+		}
+		if err := common.Respond(e.Respond, message); err != nil {
+			b.Logger.Error("failed to respond to close ticket in channel: ", err)
+		}
+		_, err := e.Client().Rest().UpdateChannel(e.ChannelID(), discord.GuildThreadUpdate{
+			Archived: json.NewPtr(true),
+			Locked:   json.NewPtr(true),
+		})
+		return err
+	}
+}
+
+func handleModMailList(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		var tickets []mod_mail.Ticket
+		for _, ticket := range m.Tickets() {
+			if e.GuildID() != nil && ticket.GuildID == *e.GuildID() {
+				tickets = append(tickets, ticket)
+			}
+		}
+		if len(tickets) == 0 {
+			return common.Respond(e.Respond, "No open tickets.")
+		}
+		sort.Slice(tickets, func(i, j int) bool {
+			return tickets[i].ThreadID < tickets[j].ThreadID
+		})
+
+		entries := make([]string, len(tickets))
+		for i, ticket := range tickets {
+			entry := "• " + discord.ChannelMention(ticket.ThreadID)
+			if ticket.Claim == nil {
+				entries[i] = entry + " unclaimed"
+				continue
+			}
+			entry += " claimed by " + discord.UserMention(ticket.Claim.UserID)
+			if ticket.Claim.Exclusive {
+				entry += " (exclusive)"
+			}
+			entries[i] = entry
+		}
+		return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+			Title:   fmt.Sprintf("%d Open Tickets", len(tickets)),
+			Entries: entries,
+			Creator: e.User().ID,
+		})
+	}
+}
+
+func handleModMailReveal(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		if !m.IsTicket(e.ChannelID()) {
+			return common.RespondErr(e.Respond, mod_mail.ErrNoTicket)
+		}
+		pseudonym, ok := m.Pseudonym(e.ChannelID())
+		if !ok {
+			return common.RespondErr(e.Respond, mod_mail.ErrNotAnonymous)
+		}
+
+		// the reveal is only shown to the staff member asking, but noted in the thread
+		if _, err := e.Client().Rest().CreateMessage(e.ChannelID(), discord.MessageCreate{
+			Content:         fmt.Sprintf("%s revealed the identity of %s.", e.User().Mention(), pseudonym.Name),
+			AllowedMentions: &discord.AllowedMentions{},
+		}); err != nil {
+			return common.RespondMessageErr(e.Respond, "Failed to note the reveal in the ticket: %s", err)
+		}
+		return e.CreateMessage(discord.NewMessageCreateBuilder().
+			SetContentf("%s is %s(`%s`).", pseudonym.Name, discord.UserMention(pseudonym.UserID), pseudonym.UserID).
+			SetAllowedMentions(&discord.AllowedMentions{}).
+			SetEphemeral(true).
+			Build(),
+		)
+	}
+}
+
+func handleModMailUnblock(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		user := e.SlashCommandInteractionData().User("user")
+		if !m.UnblockUser(user.ID) {
+			return common.RespondErrMessagef(e.Respond, "%s is not blocked", user.Mention())
+		}
+		return common.Respondf(e.Respond, "%s can open tickets again.", user.Mention())
+	}
+}
+
+func handleModMailTest(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		steps, err := m.Test(e.Client(), *e.GuildID(), e.User())
+		if err != nil {
+			return common.RespondErr(e.Respond, err)
+		}
+
+		var message string
+		for _, step := range steps {
+			switch {
+			case step.Err == nil:
+				message += fmt.Sprintf("✅ **%s**\n", step.Name)
+			case step.Skipped():
+				message += fmt.Sprintf("⏭️ **%s**: skipped\n", step.Name)
+			default:
+				message += fmt.Sprintf("❌ **%s**: `%s`\n", step.Name, step.Err)
+			}
+		}
+		return common.Respond(e.Respond, message)
+	}
+}
+
+func handleModMailStats(m *mod_mail.ModMail) butler.HandleFunc {
+	return func(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+		days, ok := e.SlashCommandInteractionData().OptInt("days")
+		if !ok {
+			days = 7
+		}
+		stats := m.ActivityStats(*e.GuildID(), time.Now().AddDate(0, 0, -days))
+		if stats.Opened == 0 {
+			return common.Respondf(e.Respond, "No tickets were opened in the last %d days.", days)
+		}
+
+		averageResponse := "No replies yet"
+		if stats.Responded > 0 {
+			averageResponse = stats.AverageResponse.Round(time.Minute).String()
+		}
+
+		hours := make([]int, 24)
+		for hour := range hours {
+			hours[hour] = hour
+		}
+		sort.SliceStable(hours, func(i, j int) bool {
+			return stats.OpenedPerHour[hours[i]] > stats.OpenedPerHour[hours[j]]
+		})
+		var busiestHours string
+		for _, hour := range hours[:3] {
+			if stats.OpenedPerHour[hour] == 0 {
+				break
+			}
+			busiestHours += fmt.Sprintf("%02d:00 - %02d:00: %d\n", hour, (hour+1)%24, stats.OpenedPerHour[hour])
+		}
+
+		topRepliers := "No replies yet"
+		if len(stats.TopRepliers) > 0 {
+			topRepliers = ""
+			for i, replier := range stats.TopRepliers {
+				if i == 5 {
+					break
+				}
+				topRepliers += fmt.Sprintf("%s: %d\n", discord.UserMention(replier.UserID), replier.Replies)
+			}
+		}
+
+		// summarize longer ranges by week to stay within the field limit
+		var perDay string
+		if len(stats.OpenedPerDay) <= 14 {
+			for _, day := range stats.OpenedPerDay {
+				perDay += fmt.Sprintf("%s: %d\n", day.Day.Format("Mon 02 Jan"), day.Count)
+			}
+		} else {
+			for i := 0; i < len(stats.OpenedPerDay); i += 7 {
+				var count int
+				for j := i; j < i+7 && j < len(stats.OpenedPerDay); j++ {
+					count += stats.OpenedPerDay[j].Count
+				}
+				perDay += fmt.Sprintf("Week of %s: %d\n", stats.OpenedPerDay[i].Day.Format("02 Jan"), count)
+			}
+		}
+
+		return e.CreateMessage(discord.NewMessageCreateBuilder().
+			SetEmbeds(discord.NewEmbedBuilder().
+				SetTitle("Mod Mail Statistics").
+				SetDescriptionf("Tickets opened in the last %d days", days).
+				AddField("Tickets", fmt.Sprintf("**Opened:** %d\n**Closed:** %d\n**Replied:** %d", stats.Opened, stats.Closed, stats.Responded), true).
+				AddField("Average First Reply", averageResponse, true).
+				AddField("Busiest Hours", busiestHours, true).
+				AddField("Top Repliers", topRepliers, true).
+				AddField("Opened", perDay, false).
+				SetFooterText("Times are in " + common.Location().String()).
+				SetColor(common.ColorSuccess).
+				Build(),
+			).
+			Build(),
+		)
+	}
+}
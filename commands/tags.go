@@ -11,8 +11,6 @@ import (
 	"github.com/disgoorg/disgo-butler/db"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
-	"github.com/disgoorg/utils/paginator"
-	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
 var TagsCommand = butler.Command{
@@ -179,7 +177,7 @@ func infoTagHandler(b *butler.Butler, e *events.ApplicationCommandInteractionCre
 					},
 					{
 						Name:  "Created at",
-						Value: fmt.Sprintf("%s (%s)", discord.NewTimestamp(discord.TimestampStyleNone, tag.CreatedAt), discord.NewTimestamp(discord.TimestampStyleRelative, tag.CreatedAt)),
+						Value: common.Timestamp(tag.CreatedAt),
 					},
 				},
 			},
@@ -196,27 +194,13 @@ func listTagHandler(b *butler.Butler, e *events.ApplicationCommandInteractionCre
 		return common.Respond(e.Respond, "No tags found.")
 	}
 
-	var pages []string
-	curPage := ""
-	for _, tag := range tags {
-		newPage := fmt.Sprintf("**%s** - %s\n", tag.Name, discord.UserMention(tag.OwnerID))
-		if len(curPage)+len(newPage) > 2000 {
-			pages = append(pages, curPage)
-			curPage = ""
-		}
-		curPage += newPage
-	}
-	if len(curPage) > 0 {
-		pages = append(pages, curPage)
+	entries := make([]string, len(tags))
+	for i, tag := range tags {
+		entries[i] = fmt.Sprintf("**%s** - %s", tag.Name, discord.UserMention(tag.OwnerID))
 	}
 
-	return b.Paginator.Create(e.Respond, &paginator.Paginator{
-		PageFunc: func(page int, embed *discord.EmbedBuilder) {
-			embed.SetDescription(pages[page])
-		},
-		MaxPages:        len(pages),
-		ExpiryLastUsage: true,
-		ID:              e.ID().String(),
+	return b.CreatePages(e.Respond, e.ID().String(), butler.Pages{
+		Entries: entries,
 	})
 }
 
@@ -237,23 +221,11 @@ func autoCompleteListTagHandler(filterTags bool) func(b *butler.Butler, e *event
 		if err != nil {
 			return e.Result(nil)
 		}
-		var response []discord.AutocompleteChoice
-
 		options := make([]string, len(tags))
 		for i := range tags {
 			options[i] = tags[i].Name
 		}
-		options = fuzzy.FindFold(name, options)
-		for _, option := range options {
-			if len(response) >= 25 {
-				break
-			}
-			response = append(response, discord.AutocompleteChoiceString{
-				Name:  option,
-				Value: option,
-			})
-		}
-		return e.Result(response)
+		return e.Result(common.AutocompleteChoices(options, name))
 	}
 }
 
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+// TextCommands are the message based shortcuts for guilds which configured a prefix.
+var TextCommands = []butler.TextCommand{
+	{Name: "docs", Handler: handleTextDocs},
+}
+
+// handleTextDocs looks up docs like /docs with the arguments `<module> [query]`.
+func handleTextDocs(b *butler.Butler, e *events.GuildMessageCreate, args string) error {
+	module, query, _ := strings.Cut(args, " ")
+	if module == "" {
+		return replyErr(b, e, common.NewUserError("usage: `docs <module> [query]`"))
+	}
+	if aliasModule, ok := b.Alias(&e.GuildID, module); ok {
+		module = aliasModule
+	}
+
+	pkg, err := searchDocs(b, module)
+	if err != nil {
+		return replyErr(b, e, err)
+	}
+	// the expand select menu is only available for interactions
+	embed, _ := b.GetDocsEmbed(pkg, strings.TrimSpace(query), false, false, false, false)
+	_, err = e.Client().Rest().CreateMessage(e.ChannelID, discord.NewMessageCreateBuilder().
+		SetEmbeds(embed).
+		SetMessageReferenceByID(e.MessageID).
+		Build(),
+	)
+	return err
+}
+
+// replyErr replies to the message like common.RespondErr responds to interactions.
+func replyErr(b *butler.Butler, e *events.GuildMessageCreate, err error) error {
+	message := "Something went wrong while executing this. Please try again later."
+	if common.IsUserError(err) {
+		message = err.Error()
+	} else {
+		b.Logger.Error("error while executing text command: ", err)
+	}
+	_, err = e.Client().Rest().CreateMessage(e.ChannelID, discord.NewMessageCreateBuilder().
+		SetEmbeds(discord.NewEmbedBuilder().
+			SetDescription(message).
+			SetColor(common.ColorError).
+			Build(),
+		).
+		SetMessageReferenceByID(e.MessageID).
+		Build(),
+	)
+	return err
+}
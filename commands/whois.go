@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+var WhoisCommand = butler.Command{
+	Create: discord.SlashCommandCreate{
+		CommandName:              "whois",
+		Description:              "Shows information about a member and their linked GitHub account.",
+		DefaultMemberPermissions: discord.PermissionManageMessages,
+		Options: []discord.ApplicationCommandOption{
+			discord.ApplicationCommandOptionUser{
+				OptionName:  "user",
+				Description: "The user to look up.",
+				Required:    true,
+			},
+		},
+	},
+	CommandHandlers: map[string]butler.HandleFunc{
+		"": handleWhois,
+	},
+}
+
+func handleWhois(b *butler.Butler, e *events.ApplicationCommandInteractionCreate) error {
+	data := e.SlashCommandInteractionData()
+	user := data.User("user")
+
+	eb := discord.NewEmbedBuilder().
+		SetAuthor(user.Tag(), "", user.EffectiveAvatarURL()).
+		SetThumbnail(user.EffectiveAvatarURL()).
+		SetColor(common.ColorSuccess).
+		AddField("User", fmt.Sprintf("%s (`%s`)", discord.UserMention(user.ID), user.ID), false).
+		AddField("Created at", common.Timestamp(user.ID.Time()), false)
+
+	if member, ok := data.OptMember("user"); ok {
+		eb.AddField("Joined at", common.Timestamp(member.JoinedAt), false)
+		roles := "None"
+		if len(member.RoleIDs) > 0 {
+			mentions := make([]string, len(member.RoleIDs))
+			for i, roleID := range member.RoleIDs {
+				mentions[i] = discord.RoleMention(roleID)
+			}
+			roles = strings.Join(mentions, " ")
+		}
+		eb.AddField("Roles", roles, false)
+	} else {
+		eb.AddField("Member", "Not a member of this server", false)
+	}
+
+	account, err := b.DB.GetGithubAccount(user.ID)
+	if err == sql.ErrNoRows {
+		eb.AddField("GitHub", "No linked account", false)
+	} else if err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to get linked GitHub account: %s", err)
+	} else {
+		eb.AddField("GitHub", fmt.Sprintf("[%s](https://github.com/%s) (linked %s)", account.Login, account.Login, common.Timestamp(account.UpdatedAt)), false)
+		repos := "None"
+		if len(account.Repos) > 0 {
+			repos = "•`" + strings.Join(account.Repos, "`\n•`") + "`"
+		}
+		eb.AddField("Contributor Repositories", repos, false)
+	}
+
+	return e.CreateMessage(discord.NewMessageCreateBuilder().
+		SetEmbeds(eb.Build()).
+		SetEphemeral(true).
+		Build(),
+	)
+}
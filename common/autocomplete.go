@@ -0,0 +1,80 @@
+package common
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+const (
+	MaxAutocompleteChoices      = 25
+	MaxAutocompleteChoiceLength = 100
+)
+
+// MatchAutocomplete returns at most MaxAutocompleteChoices candidates matching the query case-insensitively.
+// Exact matches come first, followed by prefix and substring matches. Ties are ordered by length and then alphabetically.
+// Candidates which are too long to be used as a choice value are skipped.
+func MatchAutocomplete(candidates []string, query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	type match struct {
+		value string
+		rank  int
+	}
+	seen := make(map[string]struct{}, len(candidates))
+	matches := make([]match, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate == "" || utf8.RuneCountInString(candidate) > MaxAutocompleteChoiceLength {
+			continue
+		}
+		if _, ok := seen[candidate]; ok {
+			continue
+		}
+		seen[candidate] = struct{}{}
+
+		lower := strings.ToLower(candidate)
+		var rank int
+		switch {
+		case lower == query:
+			rank = 0
+		case strings.HasPrefix(lower, query):
+			rank = 1
+		case strings.Contains(lower, query):
+			rank = 2
+		default:
+			continue
+		}
+		matches = append(matches, match{value: candidate, rank: rank})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		if len(matches[i].value) != len(matches[j].value) {
+			return len(matches[i].value) < len(matches[j].value)
+		}
+		return matches[i].value < matches[j].value
+	})
+	if len(matches) > MaxAutocompleteChoices {
+		matches = matches[:MaxAutocompleteChoices]
+	}
+
+	values := make([]string, len(matches))
+	for i, m := range matches {
+		values[i] = m.value
+	}
+	return values
+}
+
+// AutocompleteChoices returns the choices for all candidates matching the query. See MatchAutocomplete.
+func AutocompleteChoices(candidates []string, query string) []discord.AutocompleteChoice {
+	matches := MatchAutocomplete(candidates, query)
+	choices := make([]discord.AutocompleteChoice, len(matches))
+	for i, value := range matches {
+		choices[i] = discord.AutocompleteChoiceString{Name: value, Value: value}
+	}
+	return choices
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchAutocomplete(t *testing.T) {
+	many := make([]string, 40)
+	for i := range many {
+		many[i] = fmt.Sprintf("module%02d", i)
+	}
+
+	tests := []struct {
+		name       string
+		candidates []string
+		query      string
+		want       []string
+	}{
+		{
+			name:       "exact then prefix then substring",
+			candidates: []string{"my-disgo", "disgo-butler", "disgo", "Disgo-Utils"},
+			query:      "disgo",
+			want:       []string{"disgo", "Disgo-Utils", "disgo-butler", "my-disgo"},
+		},
+		{
+			name:       "case insensitive and trimmed",
+			candidates: []string{"Snowflake", "log"},
+			query:      "  SNOW ",
+			want:       []string{"Snowflake"},
+		},
+		{
+			name:       "ties by length then alphabetically",
+			candidates: []string{"bcc", "abc", "ab", "abcd", "aab"},
+			query:      "b",
+			want:       []string{"bcc", "ab", "aab", "abc", "abcd"},
+		},
+		{
+			name:       "empty query matches everything",
+			candidates: []string{"b", "a"},
+			query:      "",
+			want:       []string{"a", "b"},
+		},
+		{
+			name:       "no match",
+			candidates: []string{"disgo"},
+			query:      "log",
+			want:       []string{},
+		},
+		{
+			name:       "duplicates, empty and too long candidates are skipped",
+			candidates: []string{"disgo", "", "disgo", "disgo" + strings.Repeat("o", MaxAutocompleteChoiceLength)},
+			query:      "dis",
+			want:       []string{"disgo"},
+		},
+		{
+			name:       "capped at the most relevant choices",
+			candidates: append([]string{"module"}, many...),
+			query:      "module",
+			want:       append([]string{"module"}, many[:MaxAutocompleteChoices-1]...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchAutocomplete(tt.candidates, tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchAutocomplete() = %q, want %q", got, tt.want)
+			}
+			if len(got) > MaxAutocompleteChoices {
+				t.Errorf("MatchAutocomplete() returned %d choices, want at most %d", len(got), MaxAutocompleteChoices)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+// Backoff calculates exponentially growing delays with jitter between Min and Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+
+	attempts int
+}
+
+// Next returns the delay to wait before the next attempt.
+func (b *Backoff) Next() time.Duration {
+	delay := b.Min << b.attempts
+	if delay <= 0 || delay > b.Max {
+		delay = b.Max
+	} else {
+		b.attempts++
+	}
+	if jitter := int64(delay / 2); jitter > 0 {
+		delay += time.Duration(rand.Int63n(jitter))
+	}
+	return delay
+}
+
+func (b *Backoff) Reset() {
+	b.attempts = 0
+}
+
+// PermanentError stops Retry from retrying the wrapped error even if it is a server error.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Retry calls fn until it succeeds, returns an error which is not a server error or attempts are exhausted. A
+// PermanentError is returned unwrapped right away.
+func Retry(ctx context.Context, backoff *Backoff, attempts int, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		var permanent *PermanentError
+		if err = fn(); errors.As(err, &permanent) {
+			return permanent.Err
+		}
+		if err == nil || !IsServerError(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff.Next()):
+		}
+	}
+	return err
+}
+
+// IsServerError reports whether the error was caused by Discord being unavailable rather than by the request itself.
+func IsServerError(err error) bool {
+	var restErr *rest.Error
+	if errors.As(err, &restErr) {
+		return restErr.Response != nil && restErr.Response.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsTimeout reports whether the request timed out. The request may have reached Discord anyway, so requests which
+// aren't idempotent must not be retried.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/rest"
+)
+
+func TestRetry(t *testing.T) {
+	serverErr := rest.NewError(nil, nil, &http.Response{StatusCode: http.StatusBadGateway}, nil)
+	timeoutErr := &url.Error{Op: "Post", URL: "https://discord.com", Err: context.DeadlineExceeded}
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+		wantCalls int
+	}{
+		{name: "success", wantCalls: 1},
+		{name: "client error", err: rest.NewError(nil, nil, &http.Response{StatusCode: http.StatusBadRequest}, nil), wantCalls: 1},
+		{name: "server error", err: serverErr, wantCalls: 3},
+		{name: "timeout", err: timeoutErr, wantCalls: 3},
+		{name: "permanent timeout", err: timeoutErr, permanent: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			err := Retry(context.Background(), &Backoff{Min: time.Millisecond, Max: time.Millisecond}, 3, func() error {
+				calls++
+				if tt.permanent {
+					return &PermanentError{Err: tt.err}
+				}
+				return tt.err
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if err != tt.err {
+				t.Errorf("Retry() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	if !IsTimeout(&url.Error{Op: "Post", URL: "https://discord.com", Err: context.DeadlineExceeded}) {
+		t.Error("deadline exceeded is not a timeout")
+	}
+	if IsTimeout(&url.Error{Op: "Post", URL: "https://discord.com", Err: errors.New("connection refused")}) {
+		t.Error("connection refused is a timeout")
+	}
+}
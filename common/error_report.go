@@ -0,0 +1,81 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+// maxErrorReports is how many of the latest internal errors can still be reported.
+const maxErrorReports = 100
+
+// ErrorReport is an internal error shown to a user. Its ID is logged with the error and shown to the user, who can
+// report it to the staff with the button below the error.
+type ErrorReport struct {
+	ID  string
+	Err string
+	At  time.Time
+}
+
+var (
+	errorReportsMu      sync.Mutex
+	errorReportsEnabled bool
+	errorReports        = map[string]ErrorReport{}
+	// errorReportIDs are the IDs of errorReports from the oldest to the newest
+	errorReportIDs []string
+)
+
+// SetErrorReports enables the report button below internal errors.
+func SetErrorReports(enabled bool) {
+	errorReportsMu.Lock()
+	defer errorReportsMu.Unlock()
+	errorReportsEnabled = enabled
+}
+
+// GetErrorReport returns the internal error with the ID if it is recent enough to be reported.
+func GetErrorReport(id string) (ErrorReport, bool) {
+	errorReportsMu.Lock()
+	defer errorReportsMu.Unlock()
+	report, ok := errorReports[id]
+	return report, ok
+}
+
+// newErrorReport remembers the error under a random ID and returns whether it can be reported.
+func newErrorReport(err error) (ErrorReport, bool) {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	report := ErrorReport{
+		ID:  hex.EncodeToString(b),
+		Err: err.Error(),
+		At:  time.Now(),
+	}
+
+	errorReportsMu.Lock()
+	defer errorReportsMu.Unlock()
+	errorReports[report.ID] = report
+	errorReportIDs = append(errorReportIDs, report.ID)
+	if len(errorReportIDs) > maxErrorReports {
+		delete(errorReports, errorReportIDs[0])
+		errorReportIDs = errorReportIDs[1:]
+	}
+	return report, errorReportsEnabled
+}
+
+// respondInternalErr logs the error with the ID of its report and shows the generic error with the ID and, if enabled,
+// a button to report it.
+func respondInternalErr(respondFunc events.InteractionResponderFunc, err error) error {
+	report, reportable := newErrorReport(err)
+	logger.Errorf("error %s while executing interaction: %s", report.ID, err)
+
+	builder := discord.NewMessageCreateBuilder().
+		SetEmbeds(messageEmbeds(Message(MessageGenericError)+"\nError ID: `"+report.ID+"`", ColorError)...).
+		SetEphemeral(true)
+	if reportable {
+		builder.AddActionRow(discord.NewDangerButton("Report", discord.CustomID("error_report:"+report.ID)))
+	}
+	return respondFunc(discord.InteractionResponseTypeCreateMessage, builder.Build())
+}
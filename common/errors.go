@@ -0,0 +1,38 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/log"
+)
+
+var logger log.Logger = log.Default()
+
+// SetLogger sets the logger used to log system errors passed to RespondErr.
+func SetLogger(l log.Logger) {
+	logger = l
+}
+
+// UserError is an expected error caused by the user. Its message is shown to the user as is.
+type UserError struct {
+	Message string
+}
+
+func (e UserError) Error() string {
+	return e.Message
+}
+
+func NewUserError(message string) error {
+	return UserError{Message: message}
+}
+
+func NewUserErrorf(message string, a ...any) error {
+	return UserError{Message: fmt.Sprintf(message, a...)}
+}
+
+// IsUserError reports whether any error in err's chain is a UserError.
+func IsUserError(err error) bool {
+	var userErr UserError
+	return errors.As(err, &userErr)
+}
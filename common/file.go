@@ -0,0 +1,49 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+// MaxFileSize is the maximum size of a file the bot can upload.
+const MaxFileSize = 8 * 1024 * 1024
+
+// ErrFileTooLarge is returned when a file exceeds MaxFileSize. The interaction is not responded to in this case.
+var ErrFileTooLarge = errors.New("file too large")
+
+func RespondFile(respondFunc events.InteractionResponderFunc, name string, data []byte) error {
+	return respondFile(respondFunc, name, data, false)
+}
+
+func RespondFileEphemeral(respondFunc events.InteractionResponderFunc, name string, data []byte) error {
+	return respondFile(respondFunc, name, data, true)
+}
+
+func respondFile(respondFunc events.InteractionResponderFunc, name string, data []byte, ephemeral bool) error {
+	if len(data) > MaxFileSize {
+		return fmt.Errorf("%w: %s is %s but the upload limit is %s", ErrFileTooLarge, name, FormatBytes(len(data)), FormatBytes(MaxFileSize))
+	}
+	return respondFunc(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreateBuilder().
+		AddFile(name, "", bytes.NewReader(data)).
+		SetEphemeral(ephemeral).
+		Build(),
+	)
+}
+
+// FormatBytes renders the size in bytes with a binary unit, e.g. "1.5 MiB".
+func FormatBytes(size int) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := unit, 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,22 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Maps by ID in the config are keyed by the ID as string, TOML can't encode maps with other keys.
+
+// ParseIDKeys returns a copy of the map keyed by the parsed IDs. It fails on the first key which is no valid ID.
+func ParseIDKeys[V any](m map[string]V) (map[snowflake.ID]V, error) {
+	parsed := make(map[snowflake.ID]V, len(m))
+	for key, value := range m {
+		id, err := snowflake.Parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID %q: %w", key, err)
+		}
+		parsed[id] = value
+	}
+	return parsed, nil
+}
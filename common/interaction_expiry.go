@@ -0,0 +1,55 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// InteractionTokenTTL is how long Discord accepts the token of an interaction to edit or follow up its response.
+const InteractionTokenTTL = 15 * time.Minute
+
+// interactionExpired reports whether the token of the interaction is too old to be used.
+func interactionExpired(interaction discord.BaseInteraction) bool {
+	return time.Since(interaction.ID().Time()) >= InteractionTokenTTL
+}
+
+// isInvalidToken reports whether Discord rejected the interaction token, which happens once it expired.
+func isInvalidToken(err error) bool {
+	var restErr *rest.Error
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusUnauthorized
+}
+
+// respondExpired delivers the response of an interaction whose token expired as a regular message. Public responses are
+// posted in the channel of the interaction with a mention of the user. Ephemeral responses, and responses which can't
+// be posted in the channel, are sent to the DMs of the user instead.
+func respondExpired(client bot.Client, interaction discord.BaseInteraction, messageCreate discord.MessageCreate, ephemeral bool) error {
+	user := interaction.User()
+	messageCreate.Flags = messageCreate.Flags.Remove(discord.MessageFlagEphemeral)
+	if !ephemeral {
+		channelMessage := messageCreate
+		channelMessage.Content = Truncate(user.Mention()+" "+messageCreate.Content, Limits.MessageLength)
+		channelMessage.AllowedMentions = &discord.AllowedMentions{Users: []snowflake.ID{user.ID}}
+		_, err := client.Rest().CreateMessage(interaction.ChannelID(), channelMessage)
+		if err == nil {
+			logger.Warnf("interaction %s expired, sent its response in channel %s", interaction.ID(), interaction.ChannelID())
+			return nil
+		}
+		logger.Warnf("interaction %s expired and its response could not be sent in channel %s: %s", interaction.ID(), interaction.ChannelID(), err)
+	}
+
+	dmChannel, err := client.Rest().CreateDMChannel(user.ID)
+	if err != nil {
+		return err
+	}
+	if _, err = client.Rest().CreateMessage(dmChannel.ID(), messageCreate); err != nil {
+		return err
+	}
+	logger.Warnf("interaction %s expired, sent its response to %s in DMs", interaction.ID(), user.Tag())
+	return nil
+}
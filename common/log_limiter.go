@@ -0,0 +1,90 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/log"
+)
+
+const DefaultLogSuppressionWindow = 5 * time.Minute
+
+// LogLimiter collapses identical errors into a single line per window, so persistent failures like a deleted channel
+// don't flood the logs on every event. The first occurrence is logged right away, repeats within the window are counted
+// and summarized with the next occurrence after it. The zero value suppresses for DefaultLogSuppressionWindow.
+type LogLimiter struct {
+	// Window is how long identical errors are suppressed after being logged. A negative Window disables suppression.
+	Window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*limitedLog
+}
+
+type limitedLog struct {
+	logger     log.Logger
+	loggedAt   time.Time
+	suppressed int
+}
+
+// Error logs the message like log.Logger.Error unless it was already logged within the window.
+func (l *LogLimiter) Error(logger log.Logger, v ...any) {
+	if message, ok := l.allow(logger, fmt.Sprint(v...)); ok {
+		logger.Error(message)
+	}
+}
+
+// Errorf logs the message like log.Logger.Errorf unless it was already logged within the window.
+func (l *LogLimiter) Errorf(logger log.Logger, format string, v ...any) {
+	if message, ok := l.allow(logger, fmt.Sprintf(format, v...)); ok {
+		logger.Error(message)
+	}
+}
+
+// allow returns the message to log, with a summary of the suppressed repeats if there were any, or false if it is
+// suppressed.
+func (l *LogLimiter) allow(logger log.Logger, message string) (string, bool) {
+	window := l.Window
+	if window == 0 {
+		window = DefaultLogSuppressionWindow
+	}
+	if window < 0 {
+		return message, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if entry, ok := l.entries[message]; ok && now.Sub(entry.loggedAt) < window {
+		entry.suppressed++
+		return "", false
+	}
+
+	var suppressed int
+	if entry, ok := l.entries[message]; ok {
+		suppressed = entry.suppressed
+		delete(l.entries, message)
+	}
+	l.prune(now, window)
+	if l.entries == nil {
+		l.entries = map[string]*limitedLog{}
+	}
+	l.entries[message] = &limitedLog{logger: logger, loggedAt: now}
+	if suppressed > 0 {
+		message += fmt.Sprintf(" (repeated %d times in the last %s)", suppressed, window)
+	}
+	return message, true
+}
+
+// prune drops the entries older than the window and logs the summary of the ones which were repeated. mu must be held.
+func (l *LogLimiter) prune(now time.Time, window time.Duration) {
+	for message, entry := range l.entries {
+		if now.Sub(entry.loggedAt) < window {
+			continue
+		}
+		if entry.suppressed > 0 {
+			entry.logger.Errorf("%s (repeated %d times in the last %s)", message, entry.suppressed, window)
+		}
+		delete(l.entries, message)
+	}
+}
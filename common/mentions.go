@@ -0,0 +1,64 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Features which can override the global AllowedMentionsConfig.
+const (
+	MentionsReleases = "releases"
+	MentionsModMail  = "mod_mail"
+)
+
+// AllowedMentionsConfig is the policy of which mentions in messages of the bot ping. The zero value only pings the
+// roles a feature explicitly mentions, like the ping role of a release announcement.
+type AllowedMentionsConfig struct {
+	Everyone bool `json:"everyone" yaml:"everyone" toml:"everyone"`
+	Users    bool `json:"users" yaml:"users" toml:"users"`
+	// RoleIDs may be pinged by any message in addition to the roles a feature mentions itself.
+	RoleIDs []snowflake.ID `json:"role_ids,omitempty" yaml:"role_ids,omitempty" toml:"role_ids,omitempty"`
+}
+
+var (
+	allowedMentionsMu      sync.RWMutex
+	allowedMentions        AllowedMentionsConfig
+	featureAllowedMentions map[string]AllowedMentionsConfig
+)
+
+// SetAllowedMentions sets the global policy and the per feature overrides.
+func SetAllowedMentions(global AllowedMentionsConfig, features map[string]AllowedMentionsConfig) {
+	allowedMentionsMu.Lock()
+	defer allowedMentionsMu.Unlock()
+	allowedMentions = global
+	featureAllowedMentions = features
+}
+
+// AllowedMentions returns the allowed mentions of a message sent by the given feature. roleIDs are the configured roles
+// the feature intends to ping.
+func AllowedMentions(feature string, roleIDs ...snowflake.ID) *discord.AllowedMentions {
+	allowedMentionsMu.RLock()
+	cfg, ok := featureAllowedMentions[feature]
+	if !ok {
+		cfg = allowedMentions
+	}
+	allowedMentionsMu.RUnlock()
+
+	mentions := &discord.AllowedMentions{}
+	if cfg.Everyone {
+		mentions.Parse = append(mentions.Parse, discord.AllowedMentionTypeEveryone)
+	}
+	if cfg.Users {
+		mentions.Parse = append(mentions.Parse, discord.AllowedMentionTypeUsers)
+	}
+	for _, ids := range [][]snowflake.ID{cfg.RoleIDs, roleIDs} {
+		for _, roleID := range ids {
+			if roleID != 0 {
+				mentions.Roles = append(mentions.Roles, roleID)
+			}
+		}
+	}
+	return mentions
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"regexp"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var messageLinkRegex = regexp.MustCompile(`^https?://(?:(?:ptb|canary)\.)?discord(?:app)?\.com/channels/(\d+)/(\d+)/(\d+)$`)
+
+// ParseMessageLink returns the guild, channel and message ID of a message link.
+func ParseMessageLink(link string) (guildID snowflake.ID, channelID snowflake.ID, messageID snowflake.ID, err error) {
+	matches := messageLinkRegex.FindStringSubmatch(link)
+	if matches == nil {
+		err = NewUserError("invalid message link")
+		return
+	}
+	if guildID, err = snowflake.Parse(matches[1]); err != nil {
+		return
+	}
+	if channelID, err = snowflake.Parse(matches[2]); err != nil {
+		return
+	}
+	messageID, err = snowflake.Parse(matches[3])
+	return
+}
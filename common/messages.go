@@ -0,0 +1,71 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Keys of the messages which can be customized in the config.
+const (
+	MessageGenericError        = "generic_error"
+	MessageOwnerOnly           = "owner_only"
+	MessageAliasAdded          = "alias_added"
+	MessageAliasRemoved        = "alias_removed"
+	MessageReleaseAdded        = "release_added"
+	MessageReleaseRemoved      = "release_removed"
+	MessageRepoAdded           = "contributor_repo_added"
+	MessageRepoRemoved         = "contributor_repo_removed"
+	MessageModMailOptIn        = "mod_mail_opt_in"
+	MessageModMailCreated      = "mod_mail_created"
+	MessageModMailDeclined     = "mod_mail_declined"
+	MessageModMailOptInTimeout = "mod_mail_opt_in_timeout"
+	MessageModMailClosed       = "mod_mail_closed"
+	MessageChannelNotAllowed   = "channel_not_allowed"
+)
+
+// DefaultMessages are the built-in message templates. Templates use fmt verbs for their arguments.
+var DefaultMessages = map[string]string{
+	MessageGenericError:        "Something went wrong while executing this. Please try again later.",
+	MessageOwnerOnly:           "This command is only available to the bot owners.",
+	MessageAliasAdded:          "Added alias `%s` for module `%s`.",
+	MessageAliasRemoved:        "Removed alias `%s`.",
+	MessageReleaseAdded:        "Added release announcement for `%s`.",
+	MessageReleaseRemoved:      "Removed release announcement for `%s`.",
+	MessageRepoAdded:           "Added contributor repository `%s`.",
+	MessageRepoRemoved:         "Removed contributor repository `%s`.",
+	MessageModMailOptIn:        "Are you sure you want to open a ticket?",
+	MessageModMailCreated:      "New Ticket created.",
+	MessageModMailDeclined:     "No Ticket created.",
+	MessageModMailOptInTimeout: "Ticket creation timed out.",
+	MessageModMailClosed:       "Ticket closed.",
+	MessageChannelNotAllowed:   "This command can only be used in %s.",
+}
+
+var (
+	messagesMu sync.RWMutex
+	messages   map[string]string
+)
+
+// SetMessages overrides the DefaultMessages with the given templates.
+func SetMessages(templates map[string]string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	messages = templates
+}
+
+// Message formats the template of the given key with the arguments. Templates from the config take precedence over the
+// DefaultMessages. Unknown keys are returned as is.
+func Message(key string, a ...any) string {
+	messagesMu.RLock()
+	template, ok := messages[key]
+	messagesMu.RUnlock()
+	if !ok {
+		if template, ok = DefaultMessages[key]; !ok {
+			return key
+		}
+	}
+	if len(a) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, a...)
+}
@@ -1,10 +1,15 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
 )
 
 const (
@@ -12,17 +17,53 @@ const (
 	ColorSuccess = 0x5c5fea
 )
 
+// RespondErr shows the message of user errors directly. Other errors are logged and a generic message with the ID of
+// the error is shown instead, see ErrorReport.
 func RespondErr(respondFunc events.InteractionResponderFunc, err error) error {
-	return RespondErrMessage(respondFunc, fmt.Sprintf("Error while executing: %s", err))
+	var userErr UserError
+	if errors.As(err, &userErr) {
+		return RespondErrMessage(respondFunc, userErr.Message)
+	}
+	return respondInternalErr(respondFunc, err)
+}
+
+// messageEmbeds splits the message at line boundaries into as many embeds as fit in a single message. Whatever does
+// not fit is cut off with TruncatedMarker.
+func messageEmbeds(message string, color int) []discord.Embed {
+	if utf8.RuneCountInString(message) <= Limits.EmbedDescriptionLength {
+		return []discord.Embed{{Description: message, Color: color}}
+	}
+
+	chunks := SplitLines(message, Limits.EmbedDescriptionLength)
+	embeds := make([]discord.Embed, 0, len(chunks))
+	var length int
+	for i, chunk := range chunks {
+		chunkLength := utf8.RuneCountInString(chunk)
+		if len(embeds) == MaxEmbeds || length+chunkLength > MaxEmbedsLength {
+			// append the marker to the previous embed, or start a truncated one if there is room left
+			remaining := MaxEmbedsLength - length
+			if len(embeds) < MaxEmbeds && remaining > len(TruncatedMarker)*2 {
+				embeds = append(embeds, discord.Embed{Description: Truncate(strings.Join(chunks[i:], ""), remaining), Color: color})
+			} else {
+				// the last chunk may be short, so let it grow into the space left
+				last := &embeds[len(embeds)-1]
+				maxLength := utf8.RuneCountInString(last.Description) + remaining
+				if maxLength > Limits.EmbedDescriptionLength {
+					maxLength = Limits.EmbedDescriptionLength
+				}
+				last.Description = Truncate(last.Description+strings.Join(chunks[i:], ""), maxLength)
+			}
+			break
+		}
+		length += chunkLength
+		embeds = append(embeds, discord.Embed{Description: chunk, Color: color})
+	}
+	return embeds
 }
 
 func RespondErrMessage(respondFunc events.InteractionResponderFunc, message string) error {
 	return respondFunc(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreateBuilder().
-		SetEmbeds(discord.NewEmbedBuilder().
-			SetDescription(message).
-			SetColor(ColorError).
-			Build(),
-		).
+		SetEmbeds(messageEmbeds(message, ColorError)...).
 		SetEphemeral(true).
 		Build(),
 	)
@@ -34,23 +75,16 @@ func RespondErrMessagef(respondFunc events.InteractionResponderFunc, message str
 
 func RespondMessageErr(respondFunc events.InteractionResponderFunc, message string, err error) error {
 	return respondFunc(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreateBuilder().
-		SetEmbeds(discord.NewEmbedBuilder().
-			SetDescriptionf(message, err).
-			SetColor(ColorError).
-			Build(),
-		).
+		SetEmbeds(messageEmbeds(fmt.Sprintf(message, err), ColorError)...).
 		SetEphemeral(true).
 		Build(),
 	)
 }
 
+// Respond sends the message as embed. Messages longer than an embed description are split into multiple embeds.
 func Respond(respondFunc events.InteractionResponderFunc, message string) error {
 	return respondFunc(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreateBuilder().
-		SetEmbeds(discord.NewEmbedBuilder().
-			SetDescription(message).
-			SetColor(ColorSuccess).
-			Build(),
-		).
+		SetEmbeds(messageEmbeds(message, ColorSuccess)...).
 		Build(),
 	)
 }
@@ -58,3 +92,90 @@ func Respond(respondFunc events.InteractionResponderFunc, message string) error
 func Respondf(respondFunc events.InteractionResponderFunc, message string, a ...any) error {
 	return Respond(respondFunc, fmt.Sprintf(message, a...))
 }
+
+// RespondVerbose sends the message like Respond. In verbose mode the details are appended, they are diagnostic
+// information like the IDs of created webhooks which help while setting the bot up.
+func RespondVerbose(respondFunc events.InteractionResponderFunc, verbose bool, message string, details ...string) error {
+	if verbose && len(details) > 0 {
+		message += "\n\n__**Details**__\n" + strings.Join(details, "\n")
+	}
+	return Respond(respondFunc, message)
+}
+
+func RespondComponents(respondFunc events.InteractionResponderFunc, message string, components ...discord.ContainerComponent) error {
+	return respondFunc(discord.InteractionResponseTypeCreateMessage, discord.NewMessageCreateBuilder().
+		SetEmbeds(messageEmbeds(message, ColorSuccess)...).
+		AddContainerComponents(components...).
+		Build(),
+	)
+}
+
+func RespondComponentsf(respondFunc events.InteractionResponderFunc, message string, components []discord.ContainerComponent, a ...any) error {
+	return RespondComponents(respondFunc, fmt.Sprintf(message, a...), components...)
+}
+
+// EphemeralResponder returns a responder which shows all messages it creates or defers only to the user.
+func EphemeralResponder(respondFunc events.InteractionResponderFunc) events.InteractionResponderFunc {
+	return func(responseType discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+		if responseType == discord.InteractionResponseTypeCreateMessage || responseType == discord.InteractionResponseTypeDeferredCreateMessage {
+			// defers have no data unless they are ephemeral already
+			if messageCreate, ok := data.(discord.MessageCreate); ok || data == nil {
+				messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+				data = messageCreate
+			}
+		}
+		return respondFunc(responseType, data, opts...)
+	}
+}
+
+// DeferredResponder returns a responder which fills in the deferred response of an interaction instead of creating a new one.
+// This allows using helpers which expect a responder like the paginator after a slow operation. Once the interaction
+// expired, the response is sent as a regular message instead, see respondExpired. ephemeral is whether the interaction
+// was deferred ephemerally.
+func DeferredResponder(client bot.Client, interaction discord.BaseInteraction, ephemeral bool) events.InteractionResponderFunc {
+	return func(_ discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+		messageCreate, ok := data.(discord.MessageCreate)
+		if !ok {
+			return fmt.Errorf("unsupported deferred response data: %T", data)
+		}
+		if interactionExpired(interaction) {
+			return respondExpired(client, interaction, messageCreate, ephemeral)
+		}
+		_, err := client.Rest().UpdateInteractionResponse(interaction.ApplicationID(), interaction.Token(), discord.MessageUpdate{
+			Content:         &messageCreate.Content,
+			Embeds:          &messageCreate.Embeds,
+			Components:      &messageCreate.Components,
+			Files:           messageCreate.Files,
+			AllowedMentions: messageCreate.AllowedMentions,
+		}, opts...)
+		if isInvalidToken(err) {
+			return respondExpired(client, interaction, messageCreate, ephemeral)
+		}
+		return err
+	}
+}
+
+// FollowupResponder returns a responder which sends each response as a new follow-up message of the interaction.
+// Every call creates another message, so handlers can report progress during long operations.
+// The interaction must be acknowledged first with a response or a defer, follow-ups before that fail. Follow-ups are
+// shown in the order they were sent, so don't send them concurrently if their order matters. Like with the
+// DeferredResponder, follow-ups of expired interactions are sent as regular messages.
+func FollowupResponder(client bot.Client, interaction discord.BaseInteraction, ephemeral bool) events.InteractionResponderFunc {
+	return func(_ discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+		messageCreate, ok := data.(discord.MessageCreate)
+		if !ok {
+			return fmt.Errorf("unsupported follow-up data: %T", data)
+		}
+		if interactionExpired(interaction) {
+			return respondExpired(client, interaction, messageCreate, ephemeral)
+		}
+		if ephemeral {
+			messageCreate.Flags = messageCreate.Flags.Add(discord.MessageFlagEphemeral)
+		}
+		_, err := client.Rest().CreateFollowupMessage(interaction.ApplicationID(), interaction.Token(), messageCreate, opts...)
+		if isInvalidToken(err) {
+			return respondExpired(client, interaction, messageCreate, ephemeral)
+		}
+		return err
+	}
+}
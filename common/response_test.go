@@ -0,0 +1,189 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/disgo/rest/route"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func TestMessageEmbeds(t *testing.T) {
+	lines := func(count int, length int) string {
+		return strings.Repeat(strings.Repeat("a", length-1)+"\n", count)
+	}
+
+	tests := []struct {
+		name                   string
+		message                string
+		embedDescriptionLength int
+		wantEmbeds             int
+		wantTruncated          bool
+	}{
+		{name: "short", message: "pong", wantEmbeds: 1},
+		{name: "exactly one embed", message: strings.Repeat("a", MaxEmbedDescriptionLength), wantEmbeds: 1},
+		{name: "split into embeds", message: lines(2, 2500), wantEmbeds: 2},
+		{name: "cut at the combined embed length", message: lines(3, 3000), wantEmbeds: 2, wantTruncated: true},
+		{name: "cut at the embed count", message: lines(20, 100), embedDescriptionLength: 100, wantEmbeds: MaxEmbeds, wantTruncated: true},
+		{name: "configured description length", message: lines(3, 100), embedDescriptionLength: 150, wantEmbeds: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.embedDescriptionLength > 0 {
+				limits := Limits
+				Limits.EmbedDescriptionLength = tt.embedDescriptionLength
+				t.Cleanup(func() {
+					Limits = limits
+				})
+			}
+
+			embeds := messageEmbeds(tt.message, ColorError)
+			if len(embeds) != tt.wantEmbeds {
+				t.Fatalf("messageEmbeds() returned %d embeds, want %d", len(embeds), tt.wantEmbeds)
+			}
+			var (
+				length      int
+				description strings.Builder
+			)
+			for _, embed := range embeds {
+				if embed.Color != ColorError {
+					t.Errorf("embed color = %d, want %d", embed.Color, ColorError)
+				}
+				if embedLength := utf8.RuneCountInString(embed.Description); embedLength > Limits.EmbedDescriptionLength {
+					t.Errorf("embed description has %d characters, want at most %d", embedLength, Limits.EmbedDescriptionLength)
+				}
+				length += EmbedLength(embed)
+				description.WriteString(embed.Description)
+			}
+			if length > MaxEmbedsLength {
+				t.Errorf("embeds have %d characters, want at most %d", length, MaxEmbedsLength)
+			}
+			if truncated := strings.HasSuffix(description.String(), TruncatedMarker); truncated != tt.wantTruncated {
+				t.Errorf("truncated = %t, want %t", truncated, tt.wantTruncated)
+			}
+			if !tt.wantTruncated && description.String() != tt.message {
+				t.Errorf("embeds lost content of the message")
+			}
+		})
+	}
+}
+
+// testInteraction is an interaction which was just created, only its ID, application and token are used.
+type testInteraction struct {
+	discord.BaseInteraction
+	id snowflake.ID
+}
+
+func (i testInteraction) ID() snowflake.ID            { return i.id }
+func (i testInteraction) ApplicationID() snowflake.ID { return testApplicationID }
+func (i testInteraction) Token() string               { return "interaction-token" }
+
+const testApplicationID snowflake.ID = 817327181659111454
+
+type recordedRequest struct {
+	Method  string
+	Path    string
+	Content string
+	Flags   discord.MessageFlags
+}
+
+// requestRecorder answers all requests of the rest client with an empty message and records them.
+type requestRecorder struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func (r *requestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := recordedRequest{Method: req.Method, Path: strings.TrimPrefix(req.URL.Path, "/api/v"+route.APIVersion)}
+	if req.Body != nil {
+		var body struct {
+			Content string               `json:"content"`
+			Flags   discord.MessageFlags `json:"flags"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		recorded.Content, recorded.Flags = body.Content, body.Flags
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, recorded)
+	r.mu.Unlock()
+
+	if req.Method == http.MethodDelete {
+		return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"1","channel_id":"1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func newTestClient(t *testing.T) (bot.Client, *requestRecorder) {
+	t.Helper()
+	recorder := &requestRecorder{}
+	token := base64.StdEncoding.EncodeToString([]byte(testApplicationID.String())) + ".token.test"
+	client, err := disgo.New(token, bot.WithRestClientConfigOpts(rest.WithHTTPClient(&http.Client{Transport: recorder})))
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	return client, recorder
+}
+
+func TestDeferredFollowups(t *testing.T) {
+	webhookPath := "/webhooks/" + testApplicationID.String() + "/interaction-token"
+	tests := []struct {
+		name      string
+		ephemeral bool
+		want      []recordedRequest
+	}{
+		{
+			name: "public",
+			want: []recordedRequest{
+				{Method: http.MethodPatch, Path: webhookPath + "/messages/@original", Content: "Working on it…"},
+				{Method: http.MethodPost, Path: webhookPath, Content: "1/2 done"},
+				{Method: http.MethodPost, Path: webhookPath, Content: "2/2 done"},
+			},
+		},
+		{
+			name:      "ephemeral",
+			ephemeral: true,
+			want: []recordedRequest{
+				{Method: http.MethodPatch, Path: webhookPath + "/messages/@original", Content: "Working on it…"},
+				{Method: http.MethodPost, Path: webhookPath, Content: "1/2 done", Flags: discord.MessageFlagEphemeral},
+				{Method: http.MethodPost, Path: webhookPath, Content: "2/2 done", Flags: discord.MessageFlagEphemeral},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, recorder := newTestClient(t)
+			interaction := testInteraction{id: snowflake.New(time.Now())}
+			deferred := DeferredResponder(client, interaction, tt.ephemeral)
+			followup := FollowupResponder(client, interaction, tt.ephemeral)
+
+			if err := deferred(discord.InteractionResponseTypeCreateMessage, discord.MessageCreate{Content: "Working on it…"}); err != nil {
+				t.Fatalf("failed to fill in deferred response: %s", err)
+			}
+			for _, content := range []string{"1/2 done", "2/2 done"} {
+				if err := followup(discord.InteractionResponseTypeCreateMessage, discord.MessageCreate{Content: content}); err != nil {
+					t.Fatalf("failed to send follow-up: %s", err)
+				}
+			}
+			if !reflect.DeepEqual(recorder.requests, tt.want) {
+				t.Errorf("requests = %+v, want %+v", recorder.requests, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+const timeFormat = "2006-01-02 15:04 MST"
+
+var location = time.UTC
+
+// SetTimezone sets the timezone used by FormatTime. An empty name keeps UTC.
+func SetTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	location = loc
+	return nil
+}
+
+// Timestamp renders t as Discord timestamp markup so every viewer sees it in their local time.
+func Timestamp(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", discord.TimestampStyleShortDateTime.FormatTime(t), discord.TimestampStyleRelative.FormatTime(t))
+}
+
+// FormatTime renders t as a static string in the configured timezone for places where Discord markup is not rendered.
+func FormatTime(t time.Time) string {
+	return t.In(location).Format(timeFormat)
+}
+
+// Location returns the configured timezone.
+func Location() *time.Location {
+	return location
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	at := time.Date(2022, 7, 1, 22, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		timezone string
+		want     string
+	}{
+		{name: "default", want: "2022-07-01 22:30 UTC"},
+		{name: "configured timezone", timezone: "Europe/Berlin", want: "2022-07-02 00:30 CEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				location = time.UTC
+			})
+			if err := SetTimezone(tt.timezone); err != nil {
+				t.Skipf("timezone data is not available: %s", err)
+			}
+			if got := FormatTime(at); got != tt.want {
+				t.Errorf("FormatTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
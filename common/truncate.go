@@ -0,0 +1,153 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+const (
+	MaxMessageLength          = 2000
+	MaxEmbedDescriptionLength = 4096
+	MaxEmbedFieldLength       = 1024
+	// MaxTextInputLength is the maximum length of a text input in a modal.
+	MaxTextInputLength = 4000
+	// MaxEmbedsLength is the maximum combined length of all embeds in a message.
+	MaxEmbedsLength = 6000
+	MaxEmbeds       = 10
+
+	// MinLimitLength is the smallest configurable limit, shorter ones leave no room for the truncation marker.
+	MinLimitLength = 64
+
+	TruncatedMarker = "… (truncated)"
+)
+
+type LimitsConfig struct {
+	MessageLength          int `json:"message_length" yaml:"message_length" toml:"message_length"`
+	EmbedDescriptionLength int `json:"embed_description_length" yaml:"embed_description_length" toml:"embed_description_length"`
+	EmbedFieldLength       int `json:"embed_field_length" yaml:"embed_field_length" toml:"embed_field_length"`
+}
+
+// Limits are the maximum lengths used when rendering messages. They never exceed Discord's limits.
+var Limits = LimitsConfig{
+	MessageLength:          MaxMessageLength,
+	EmbedDescriptionLength: MaxEmbedDescriptionLength,
+	EmbedFieldLength:       MaxEmbedFieldLength,
+}
+
+// SetLimits overrides the default Limits with all non-zero values of the config. Values below MinLimitLength are
+// raised to it.
+func SetLimits(config LimitsConfig) {
+	Limits.MessageLength = limit(config.MessageLength, MaxMessageLength)
+	Limits.EmbedDescriptionLength = limit(config.EmbedDescriptionLength, MaxEmbedDescriptionLength)
+	Limits.EmbedFieldLength = limit(config.EmbedFieldLength, MaxEmbedFieldLength)
+}
+
+func limit(value int, max int) int {
+	if value <= 0 || value > max {
+		return max
+	}
+	if value < MinLimitLength {
+		return MinLimitLength
+	}
+	return value
+}
+
+// EmbedLength returns the length of the embed counted towards MaxEmbedsLength.
+func EmbedLength(embed discord.Embed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return length
+}
+
+// Truncate cuts the text to at most maxLength characters. See TruncateLink.
+func Truncate(text string, maxLength int) string {
+	return TruncateLink(text, maxLength, "")
+}
+
+// TruncateLink cuts the text to at most maxLength characters at the last line or word boundary and appends
+// TruncatedMarker with an optional link to the full content. Open code blocks are closed.
+func TruncateLink(text string, maxLength int, url string) string {
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+
+	marker := TruncatedMarker
+	if url != "" {
+		marker += fmt.Sprintf(" [see more](%s)", url)
+	}
+	// reserve space for the marker and closing a code block
+	cut := maxLength - utf8.RuneCountInString(marker) - len("\n```\n")
+	if cut <= 0 {
+		return string([]rune(marker)[:maxLength])
+	}
+
+	truncated := string([]rune(text)[:cut])
+	if i := strings.LastIndex(truncated, "\n"); i > len(truncated)/2 {
+		truncated = truncated[:i]
+	} else if i = strings.LastIndexAny(truncated, " \t"); i > len(truncated)/2 {
+		truncated = truncated[:i]
+	}
+	if strings.Count(truncated, "```")%2 == 1 {
+		truncated += "\n```\n"
+	}
+	return truncated + marker
+}
+
+// SplitLines splits the text into chunks of at most maxLength characters at line boundaries. Lines longer than
+// maxLength are split at word boundaries or hard if there are none. Code blocks cut by a split are closed and reopened.
+func SplitLines(text string, maxLength int) []string {
+	var (
+		chunks []string
+		chunk  string
+	)
+	flush := func() {
+		if chunk == "" {
+			return
+		}
+		if strings.Count(chunk, "```")%2 == 1 {
+			chunks = append(chunks, chunk+"\n```")
+			chunk = "```\n"
+			return
+		}
+		chunks = append(chunks, chunk)
+		chunk = ""
+	}
+	// reserve space for closing a code block
+	maxLength -= len("\n```")
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for line != "" && utf8.RuneCountInString(chunk)+utf8.RuneCountInString(line) > maxLength {
+			if chunk != "" && chunk != "```\n" {
+				flush()
+				continue
+			}
+			// the line does not fit in an empty chunk
+			cut := maxLength - utf8.RuneCountInString(chunk)
+			if cut <= 0 {
+				// no room left for the code block, always take at least one character
+				cut = 1
+			}
+			runes := []rune(line)
+			part := string(runes[:cut])
+			if i := strings.LastIndexAny(part, " \t"); i > len(part)/2 {
+				part = part[:i+1]
+			}
+			chunk += part
+			line = line[len(part):]
+			flush()
+		}
+		chunk += line
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		maxLength int
+		want      []string
+	}{
+		{name: "empty", text: "", maxLength: 14, want: nil},
+		{name: "fits", text: "a\nb", maxLength: 14, want: []string{"a\nb"}},
+		{name: "line boundaries", text: "aaaa\nbbbb\ncccc", maxLength: 14, want: []string{"aaaa\nbbbb\n", "cccc"}},
+		{name: "long line at word boundary", text: "abcdef ghijklmn", maxLength: 14, want: []string{"abcdef ", "ghijklmn"}},
+		{name: "long line without spaces", text: strings.Repeat("a", 25), maxLength: 14, want: []string{strings.Repeat("a", 10), strings.Repeat("a", 10), strings.Repeat("a", 5)}},
+		{name: "code block is closed and reopened", text: "```\nab\ncd\nef\n```", maxLength: 14, want: []string{"```\nab\ncd\n\n```", "```\nef\n```"}},
+		{name: "characters instead of bytes", text: "äöüäöü\näöü", maxLength: 11, want: []string{"äöüäöü\n", "äöü"}},
+		{name: "no room for a code block", text: "ab\n", maxLength: 4, want: []string{"a", "b", "\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitLines(tt.text, tt.maxLength)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitLines() = %q, want %q", got, tt.want)
+			}
+			for _, chunk := range got {
+				if length := utf8.RuneCountInString(chunk); length > tt.maxLength {
+					t.Errorf("chunk %q has %d characters, want at most %d", chunk, length, tt.maxLength)
+				}
+			}
+		})
+	}
+}
+
+func TestSetLimits(t *testing.T) {
+	defaults := Limits
+	t.Cleanup(func() {
+		Limits = defaults
+	})
+
+	SetLimits(LimitsConfig{MessageLength: 4, EmbedDescriptionLength: 5000, EmbedFieldLength: 500})
+	want := LimitsConfig{MessageLength: MinLimitLength, EmbedDescriptionLength: MaxEmbedDescriptionLength, EmbedFieldLength: 500}
+	if Limits != want {
+		t.Errorf("Limits = %+v, want %+v", Limits, want)
+	}
+}
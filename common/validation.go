@@ -0,0 +1,94 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"golang.org/x/exp/slices"
+)
+
+// ValidateGuildChannel checks that the channel belongs to the given guild and is of one of the given types.
+// Problems caused by the user are returned as UserError.
+func ValidateGuildChannel(client bot.Client, guildID *snowflake.ID, channelID snowflake.ID, channelTypes ...discord.ChannelType) error {
+	if guildID == nil {
+		return NewUserError("this command can only be used in a server")
+	}
+	channel, err := client.Rest().GetChannel(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to get channel %s: %w", discord.ChannelMention(channelID), err)
+	}
+	guildChannel, ok := channel.(discord.GuildChannel)
+	if !ok || guildChannel.GuildID() != *guildID {
+		return NewUserErrorf("channel %s does not belong to this server", discord.ChannelMention(channelID))
+	}
+	if len(channelTypes) > 0 && !slices.Contains(channelTypes, channel.Type()) {
+		return NewUserErrorf("channel %s can't be used for this", discord.ChannelMention(channelID))
+	}
+	return nil
+}
+
+// ValidateAssignableRole checks that the role can be mentioned and assigned to members of the given guild.
+// Problems caused by the user are returned as UserError.
+func ValidateAssignableRole(guildID *snowflake.ID, role discord.Role) error {
+	if guildID == nil {
+		return NewUserError("this command can only be used in a server")
+	}
+	if role.ID == *guildID {
+		return NewUserError("the @everyone role can't be used for this")
+	}
+	if role.Managed {
+		return NewUserErrorf("role %s is managed by an integration and can't be assigned", discord.RoleMention(role.ID))
+	}
+	return nil
+}
+
+// ValidateThread checks that the thread belongs to the given parent channel.
+// Problems caused by the user are returned as UserError.
+func ValidateThread(client bot.Client, threadID snowflake.ID, parentID snowflake.ID) error {
+	channel, err := client.Rest().GetChannel(threadID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread %s: %w", discord.ChannelMention(threadID), err)
+	}
+	thread, ok := channel.(discord.GuildThread)
+	if !ok {
+		return NewUserErrorf("channel %s is not a thread", discord.ChannelMention(threadID))
+	}
+	if thread.ParentID() == nil || *thread.ParentID() != parentID {
+		return NewUserErrorf("thread %s does not belong to %s", discord.ChannelMention(threadID), discord.ChannelMention(parentID))
+	}
+	return nil
+}
+
+// ValidateWebhookUsername checks that the name can be used as username of webhook messages. An empty name is valid and
+// keeps the name of the webhook.
+func ValidateWebhookUsername(name string) error {
+	if name == "" {
+		return nil
+	}
+	if length := utf8.RuneCountInString(name); length > 80 {
+		return NewUserErrorf("the username `%s` is longer than 80 characters", name)
+	}
+	lower := strings.ToLower(name)
+	if strings.Contains(lower, "discord") || strings.Contains(lower, "clyde") {
+		return NewUserErrorf("the username `%s` must not contain \"discord\" or \"clyde\"", name)
+	}
+	return nil
+}
+
+// ValidateAvatarURL checks that the URL is an absolute http(s) URL Discord can fetch an avatar from. An empty URL is
+// valid and keeps the avatar of the webhook.
+func ValidateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(avatarURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return NewUserErrorf("`%s` is not a valid avatar URL, it must start with https://", avatarURL)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package components
+
+import (
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+var AdminRestartComponent = butler.Component{
+	Action:  "admin_restart",
+	Handler: handleAdminRestart,
+}
+
+func handleAdminRestart(b *butler.Butler, data []string, e *events.ComponentInteractionCreate) error {
+	if !b.IsOwner(e.User().ID) {
+		return common.RespondErrMessage(e.Respond, common.Message(common.MessageOwnerOnly))
+	}
+	content := "Restart cancelled."
+	if data[0] == "confirm" {
+		content = "Restarting..."
+	}
+	if err := e.UpdateMessage(discord.MessageUpdate{
+		Content:    &content,
+		Components: &[]discord.ContainerComponent{},
+	}); err != nil || data[0] != "confirm" {
+		return err
+	}
+	b.Logger.Infof("Restart requested by %s(%s)", e.User().Tag(), e.User().ID)
+	b.Restart(e.ApplicationID(), e.Token())
+	return nil
+}
@@ -30,32 +30,34 @@ func handleDocsAction(b *butler.Butler, _ []string, e *events.ComponentInteracti
 		return common.RespondErrMessagef(e.Respond, "Error while fetching package: %s", err)
 	}
 
-	var (
-		expandSignature bool
-		expandComment   bool
-		expandMethods   bool
-		expandExamples  bool
-	)
-	if strings.HasPrefix(action, "expand:") {
-		switch strings.TrimPrefix(action, "expand:") {
-		case "signature":
-			expandSignature = true
-		case "methods":
-			expandMethods = true
-		case "comment":
-			expandComment = true
-		case "examples":
-			expandExamples = true
-		}
-	} else if !strings.HasPrefix(action, "collapse:") {
+	// the result is rendered with the preferences of the user who looked it up
+	prefs, err := b.DocsPrefs(e.Message.Interaction.User.ID)
+	if err != nil && !butler.IsCachedFailure(err) {
+		b.Logger.Errorf("Failed to get docs preferences of %s: %s", e.Message.Interaction.User.ID, err)
+	}
+	var expandMethods bool
+	expandSignature, expandComment, expandExamples := prefs.Expanded()
+	kind, part, _ := strings.Cut(action, ":")
+	if kind != "expand" && kind != "collapse" {
 		return common.RespondErrMessagef(e.Respond, "Unknown action: %s", action)
 	}
+	expand := kind == "expand"
+	switch part {
+	case "signature":
+		expandSignature = expand
+	case "methods":
+		expandMethods = expand
+	case "comment":
+		expandComment = expand
+	case "examples":
+		expandExamples = expand
+	}
 
 	var query string
 	if len(values) > 1 {
 		query = values[1]
 	}
-	embed, selectMenu := butler.GetDocsEmbed(pkg, query, expandSignature, expandComment, expandMethods, expandExamples)
+	embed, selectMenu := b.GetDocsEmbed(pkg, query, expandSignature, expandComment, expandMethods, expandExamples)
 	if e.Message.Interaction.User.ID != e.User().ID && e.Member().Permissions.Missing(discord.PermissionManageMessages) {
 		return e.CreateMessage(discord.MessageCreate{Embeds: []discord.Embed{embed}, Flags: discord.MessageFlagEphemeral})
 	}
@@ -0,0 +1,40 @@
+package components
+
+import (
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+var ErrorReportComponent = butler.Component{
+	Action:  "error_report",
+	Handler: handleErrorReport,
+}
+
+func handleErrorReport(b *butler.Butler, data []string, e *events.ComponentInteractionCreate) error {
+	report, ok := common.GetErrorReport(data[0])
+	if !ok {
+		return common.RespondErrMessage(e.Respond, "This error is too old to be reported.")
+	}
+	ctx := butler.ErrorReportContext{
+		User:      e.User(),
+		GuildID:   e.GuildID(),
+		ChannelID: e.ChannelID(),
+	}
+	if e.Message.Interaction != nil {
+		ctx.Command = e.Message.Interaction.Name
+	}
+	if err := b.ReportError(report, ctx); err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	b.Logger.Infof("Error %s reported by %s(%s)", report.ID, e.User().Tag(), e.User().ID)
+
+	return e.UpdateMessage(discord.MessageUpdate{
+		Embeds: &[]discord.Embed{{
+			Description: common.Message(common.MessageGenericError) + "\nError ID: `" + report.ID + "`\nThe error was reported, thank you!",
+			Color:       common.ColorError,
+		}},
+		Components: &[]discord.ContainerComponent{},
+	})
+}
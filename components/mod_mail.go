@@ -0,0 +1,98 @@
+package components
+
+import (
+	"errors"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/mod_mail"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var ModMailComponent = butler.Component{
+	Action:  "mod_mail",
+	Handler: handleModMail,
+}
+
+func handleModMail(b *butler.Butler, data []string, e *events.ComponentInteractionCreate) error {
+	threadID := e.ChannelID()
+	switch data[0] {
+	case "claim":
+		previous, err := b.ModMail.ClaimThread(e.Client(), threadID, e.User(), false)
+		if common.IsUserError(err) {
+			return common.RespondErr(e.Respond, err)
+		} else if err != nil {
+			b.Logger.Error("failed to rename claimed thread: ", err)
+		}
+		if err = e.UpdateMessage(discord.MessageUpdate{Components: json.NewPtr(disableButtons(e.Message, e.Data.CustomID()))}); err != nil {
+			return err
+		}
+		message := e.User().Mention() + " claimed this ticket."
+		if previous != nil {
+			message = e.User().Mention() + " took over this ticket from " + discord.UserMention(previous.UserID) + "."
+		}
+		_, err = e.Client().Rest().CreateMessage(threadID, discord.MessageCreate{
+			Content:         message,
+			AllowedMentions: &discord.AllowedMentions{},
+		})
+		return err
+
+	case "close", "block":
+		var blocked snowflake.ID
+		// the pseudonym is gone once the ticket is closed
+		pseudonym, anonymous := b.ModMail.Pseudonym(threadID)
+		if data[0] == "block" && len(data) > 1 {
+			userID, err := snowflake.Parse(data[1])
+			if err != nil {
+				return common.RespondErr(e.Respond, err)
+			}
+			b.ModMail.BlockUser(userID)
+			blocked = userID
+		}
+		if err := b.ModMail.CloseThread(e.Client(), threadID, e.User(), ""); err != nil && (blocked == 0 || !errors.Is(err, mod_mail.ErrNoTicket)) {
+			return common.RespondErr(e.Respond, err)
+		}
+		if err := e.UpdateMessage(discord.MessageUpdate{Components: json.NewPtr(disableButtons(e.Message, ""))}); err != nil {
+			return err
+		}
+		message := "Ticket closed by " + e.User().Mention() + "."
+		if blocked != 0 {
+			blockedName := discord.UserMention(blocked)
+			if anonymous {
+				blockedName = pseudonym.Name
+			}
+			message = blockedName + " was blocked and the ticket closed by " + e.User().Mention() + "."
+		}
+		if _, err := e.Client().Rest().CreateMessage(threadID, discord.MessageCreate{
+			Content:         message,
+			AllowedMentions: &discord.AllowedMentions{},
+		}); err != nil {
+			b.Logger.Error("failed to send ticket closed message: ", err)
+		}
+		_, err := e.Client().Rest().UpdateChannel(threadID, discord.GuildThreadUpdate{
+			Archived: json.NewPtr(true),
+		})
+		return err
+	}
+	return nil
+}
+
+// disableButtons returns the action rows of the message with the button with the given custom ID disabled, or all
+// buttons if customID is empty.
+func disableButtons(message discord.Message, customID discord.CustomID) []discord.ContainerComponent {
+	var rows []discord.ContainerComponent
+	for _, row := range message.ActionRows() {
+		components := make([]discord.InteractiveComponent, 0, len(row.Components()))
+		for _, component := range row.Components() {
+			if button, ok := component.(discord.ButtonComponent); ok && (customID == "" || button.CustomID == customID) {
+				component = button.AsDisabled()
+			}
+			components = append(components, component)
+		}
+		rows = append(rows, discord.NewActionRow(components...))
+	}
+	return rows
+}
@@ -0,0 +1,117 @@
+package components
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/json"
+)
+
+var PagesComponent = butler.Component{
+	Action:  "pages",
+	Handler: handlePages,
+}
+
+// handlePages navigates and filters butler.Pages. The custom ID is "pages:<id>:<action>:<current page>".
+func handlePages(b *butler.Butler, data []string, e *events.ComponentInteractionCreate) error {
+	if len(data) < 3 {
+		return nil
+	}
+	id, action := data[0], data[1]
+	page, err := strconv.Atoi(data[2])
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	var pages butler.Pages
+	ok, err := b.ComponentStates.Get(butler.PagesStatePrefix(id), &pages)
+	if err != nil {
+		b.Logger.Errorf("Failed to decode pages %s: %s", id, err)
+	}
+	if !ok || err != nil || len(pages.AllPages()) == 0 {
+		// the pages expired, remove the buttons like the paginator does
+		return e.UpdateMessage(discord.MessageUpdate{Components: &[]discord.ContainerComponent{}})
+	}
+	if pages.Creator != 0 && pages.Creator != e.User().ID {
+		return common.RespondErrMessage(e.Respond, "You can't interact with these pages because they are not yours.")
+	}
+
+	switch action {
+	case "first":
+		page = 0
+	case "back":
+		page--
+	case "next":
+		page++
+	case "last":
+		page = len(pages.AllPages()) - 1
+	case "stop":
+		b.ComponentStates.Delete(butler.PagesStatePrefix(id))
+		return e.UpdateMessage(discord.MessageUpdate{Components: &[]discord.ContainerComponent{}})
+	case "filter":
+		return pagesFilterModal(b, e, id, pages)
+	case "clear":
+		pages.Filter = ""
+		page = 0
+	}
+
+	// refresh the expiry on every use
+	if err = b.ComponentStates.Set(butler.PagesStatePrefix(id), pages, butler.PagesTTL); err != nil {
+		b.Logger.Errorf("Failed to store pages %s: %s", id, err)
+	}
+	embed, components := pages.Render(id, page)
+	return e.UpdateMessage(discord.MessageUpdate{
+		Embeds:     json.NewPtr([]discord.Embed{embed}),
+		Components: &components,
+	})
+}
+
+// pagesFilterModal asks for the filter of the pages with a modal and shows the first page of the filtered entries.
+func pagesFilterModal(b *butler.Butler, e *events.ComponentInteractionCreate, id string, pages butler.Pages) error {
+	customID := discord.CustomID("pages_filter:" + e.ID().String())
+	if err := e.CreateModal(discord.NewModalCreateBuilder().
+		SetCustomID(customID).
+		SetTitle("Filter").
+		AddActionRow(discord.NewShortTextInput("filter", "Only show entries containing").
+			WithRequired(false).
+			WithMaxLength(100).
+			WithValue(pages.Filter),
+		).
+		Build(),
+	); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		bot.WaitForEvent(e.Client(), ctx, func(me *events.ModalSubmitInteractionCreate) bool {
+			return me.Data.CustomID == customID
+		}, func(me *events.ModalSubmitInteractionCreate) {
+			// the pages could have expired while the modal was open
+			if ok, err := b.ComponentStates.Get(butler.PagesStatePrefix(id), &pages); !ok || err != nil {
+				_ = common.RespondErrMessage(me.Respond, "These pages expired.")
+				return
+			}
+			pages.Filter = strings.TrimSpace(me.Data.Text("filter"))
+			if err := b.ComponentStates.Set(butler.PagesStatePrefix(id), pages, butler.PagesTTL); err != nil {
+				b.Logger.Errorf("Failed to store pages %s: %s", id, err)
+			}
+			embed, components := pages.Render(id, 0)
+			if err := me.UpdateMessage(discord.MessageUpdate{
+				Embeds:     json.NewPtr([]discord.Embed{embed}),
+				Components: &components,
+			}); err != nil {
+				b.Logger.Error("failed to update filtered pages: ", err)
+			}
+		}, func() {})
+	}()
+	return nil
+}
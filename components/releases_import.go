@@ -0,0 +1,134 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var ReleasesImportComponent = butler.Component{
+	Action:  "releases_import",
+	Handler: handleReleasesImport,
+}
+
+// handleReleasesImport asks for the repository and ping role of the selected webhook and adds it as release
+// announcement. The custom ID is "releases_import:<channel id>".
+func handleReleasesImport(b *butler.Butler, data []string, e *events.ComponentInteractionCreate) error {
+	selectData, ok := e.Data.(discord.SelectMenuInteractionData)
+	if !ok || len(selectData.Values) == 0 || e.GuildID() == nil {
+		return nil
+	}
+	channelID, err := snowflake.Parse(data[0])
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	webhookID, err := snowflake.Parse(selectData.Values[0])
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+
+	// fetch the webhook again, it could have been imported or deleted in the meantime
+	webhooks, _, err := b.ImportableWebhooks(channelID)
+	if err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to get the webhooks of the channel: %s", err)
+	}
+	var webhook *discord.IncomingWebhook
+	for i := range webhooks {
+		if webhooks[i].ID() == webhookID {
+			webhook = &webhooks[i]
+			break
+		}
+	}
+	if webhook == nil {
+		return common.RespondErrMessage(e.Respond, "This webhook can't be imported anymore.")
+	}
+
+	customID := discord.CustomID("releases_import_modal:" + e.ID().String())
+	repoInput := discord.NewShortTextInput("repo", "Repository").
+		WithRequired(true).
+		WithMaxLength(100).
+		WithPlaceholder("disgoorg/disgo")
+	if strings.Count(webhook.Name(), "/") == 1 {
+		repoInput = repoInput.WithValue(webhook.Name())
+	}
+	if err = e.CreateModal(discord.NewModalCreateBuilder().
+		SetCustomID(customID).
+		SetTitle("Import " + common.Truncate(webhook.Name(), 38)).
+		AddActionRow(repoInput).
+		AddActionRow(discord.NewShortTextInput("ping-role", "Ping role").
+			WithRequired(true).
+			WithMaxLength(100).
+			WithPlaceholder("Role name, mention or ID"),
+		).
+		Build(),
+	); err != nil {
+		return err
+	}
+
+	guildID := *e.GuildID()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		bot.WaitForEvent(e.Client(), ctx, func(me *events.ModalSubmitInteractionCreate) bool {
+			return me.Data.CustomID == customID
+		}, func(me *events.ModalSubmitInteractionCreate) {
+			repo := strings.TrimSpace(me.Data.Text("repo"))
+			if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+				_ = common.RespondErrMessagef(me.Respond, "`%s` is not a repository in the owner/name format", repo)
+				return
+			}
+			if _, ok := b.ReleaseConfig(repo); ok {
+				_ = common.RespondErrMessagef(me.Respond, "release `%s` already exists", repo)
+				return
+			}
+			role, err := findRole(b, guildID, me.Data.Text("ping-role"))
+			if err == nil {
+				err = common.ValidateAssignableRole(&guildID, role)
+			}
+			if err != nil {
+				_ = common.RespondErr(me.Respond, err)
+				return
+			}
+
+			if err = b.SetReleaseConfig(repo, butler.GithubReleaseConfig{
+				WebhookID:    webhook.ID(),
+				WebhookToken: webhook.Token,
+				PingRole:     role.ID,
+			}); err != nil {
+				_ = common.RespondErr(me.Respond, err)
+				return
+			}
+			if err = common.RespondVerbose(me.Respond, b.Verbose(me.GuildID()), common.Message(common.MessageReleaseAdded, repo),
+				fmt.Sprintf("Webhook: `%s` in %s", webhook.ID(), discord.ChannelMention(webhook.ChannelID)),
+				fmt.Sprintf("Ping role: `%s`", role.ID),
+			); err != nil {
+				b.Logger.Error("failed to respond to release import: ", err)
+			}
+		}, func() {})
+	}()
+	return nil
+}
+
+// findRole returns the role of the guild matching the ID, mention or name, ignoring the case of the name.
+func findRole(b *butler.Butler, guildID snowflake.ID, input string) (discord.Role, error) {
+	input = strings.TrimSpace(input)
+	if roleID, err := snowflake.Parse(strings.TrimSuffix(strings.TrimPrefix(input, "<@&"), ">")); err == nil {
+		if role, ok := b.Client.Caches().Roles().Get(guildID, roleID); ok {
+			return role, nil
+		}
+	}
+	for _, role := range b.Client.Caches().Roles().GroupAll(guildID) {
+		if strings.EqualFold(role.Name, strings.TrimPrefix(input, "@")) {
+			return role, nil
+		}
+	}
+	return discord.Role{}, common.NewUserErrorf("role `%s` not found", input)
+}
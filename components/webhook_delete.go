@@ -0,0 +1,45 @@
+package components
+
+import (
+	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var WebhookDeleteComponent = butler.Component{
+	Action:  "webhook_delete",
+	Handler: handleWebhookDelete,
+}
+
+func handleWebhookDelete(b *butler.Butler, data []string, e *events.ComponentInteractionCreate) error {
+	if !b.IsOwner(e.User().ID) {
+		return common.RespondErrMessage(e.Respond, "This action is only available to the bot owners.")
+	}
+	webhookID, err := snowflake.Parse(data[0])
+	if err != nil {
+		return common.RespondErr(e.Respond, err)
+	}
+	if err = e.Client().Rest().DeleteWebhook(webhookID); err != nil {
+		return common.RespondMessageErr(e.Respond, "Failed to delete webhook: ", err)
+	}
+
+	var rows []discord.ContainerComponent
+	for _, row := range e.Message.ActionRows() {
+		var remaining []discord.InteractiveComponent
+		for _, component := range row.Components() {
+			if component.ID() != e.Data.CustomID() {
+				remaining = append(remaining, component)
+			}
+		}
+		if len(remaining) > 0 {
+			rows = append(rows, discord.NewActionRow(remaining...))
+		}
+	}
+	content := e.Message.Content
+	if len(rows) == 0 {
+		content = "All orphaned webhooks deleted."
+	}
+	return e.UpdateMessage(discord.MessageUpdate{Content: &content, Components: &rows})
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type ChannelAllowlistsDB interface {
+	GetChannelAllowlists(ctx context.Context, guildID snowflake.ID) ([]ChannelAllowlist, error)
+	AddChannelAllowlist(guildID snowflake.ID, feature string, channelID snowflake.ID) error
+	RemoveChannelAllowlist(guildID snowflake.ID, feature string, channelID snowflake.ID) (bool, error)
+}
+
+// ChannelAllowlist allows a feature of the bot in a channel. Features without any allowed channels can be used anywhere.
+type ChannelAllowlist struct {
+	GuildID   snowflake.ID `bun:"guild_id,pk"`
+	Feature   string       `bun:"feature,pk"`
+	ChannelID snowflake.ID `bun:"channel_id,pk"`
+	CreatedAt time.Time    `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+func (s *sqlDB) GetChannelAllowlists(ctx context.Context, guildID snowflake.ID) (allowlists []ChannelAllowlist, err error) {
+	err = s.db.NewSelect().
+		Model(&allowlists).
+		Where("guild_id = ?", guildID).
+		Scan(ctx)
+	return
+}
+
+func (s *sqlDB) AddChannelAllowlist(guildID snowflake.ID, feature string, channelID snowflake.ID) (err error) {
+	_, err = s.db.NewInsert().Model(&ChannelAllowlist{
+		GuildID:   guildID,
+		Feature:   feature,
+		ChannelID: channelID,
+	}).
+		On("CONFLICT (guild_id, feature, channel_id) DO NOTHING").
+		Exec(context.TODO())
+	return
+}
+
+func (s *sqlDB) RemoveChannelAllowlist(guildID snowflake.ID, feature string, channelID snowflake.ID) (bool, error) {
+	rs, err := s.db.NewDelete().Model((*ChannelAllowlist)(nil)).Where("guild_id = ? AND feature = ? AND channel_id = ?", guildID, feature, channelID).Exec(context.TODO())
+	if err != nil {
+		return false, err
+	}
+	rows, err := rs.RowsAffected()
+	return rows > 0, err
+}
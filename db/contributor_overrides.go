@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type ContributorOverridesDB interface {
+	GetContributorOverrides(userID snowflake.ID) ([]ContributorOverride, error)
+	GetAllContributorOverrides() ([]ContributorOverride, error)
+	AddContributorOverride(userID snowflake.ID, repo string, grantedBy snowflake.ID) error
+	RemoveContributorOverride(userID snowflake.ID, repo string) (bool, error)
+}
+
+type ContributorOverride struct {
+	UserID    snowflake.ID `bun:"user_id,pk"`
+	Repo      string       `bun:"repo,pk"`
+	GrantedBy snowflake.ID `bun:"granted_by,notnull"`
+	CreatedAt time.Time    `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+func (s *sqlDB) GetContributorOverrides(userID snowflake.ID) (overrides []ContributorOverride, err error) {
+	err = s.db.NewSelect().
+		Model(&overrides).
+		Where("user_id = ?", userID).
+		Scan(context.TODO())
+	return
+}
+
+func (s *sqlDB) GetAllContributorOverrides() (overrides []ContributorOverride, err error) {
+	err = s.db.NewSelect().
+		Model(&overrides).
+		Scan(context.TODO())
+	return
+}
+
+func (s *sqlDB) AddContributorOverride(userID snowflake.ID, repo string, grantedBy snowflake.ID) (err error) {
+	_, err = s.db.NewInsert().Model(&ContributorOverride{
+		UserID:    userID,
+		Repo:      repo,
+		GrantedBy: grantedBy,
+	}).
+		On("CONFLICT (user_id, repo) DO NOTHING").
+		Exec(context.TODO())
+	return
+}
+
+func (s *sqlDB) RemoveContributorOverride(userID snowflake.ID, repo string) (bool, error) {
+	rs, err := s.db.NewDelete().Model((*ContributorOverride)(nil)).Where("user_id = ? AND repo = ?", userID, repo).Exec(context.TODO())
+	if err != nil {
+		return false, err
+	}
+	rows, err := rs.RowsAffected()
+	return rows > 0, err
+}
@@ -11,12 +11,12 @@ import (
 )
 
 type Config struct {
-	Address  string `json:"address"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Database string `json:"database"`
-	Insecure bool   `json:"insecure"`
-	Verbose  bool   `json:"verbose"`
+	Address  string `json:"address" yaml:"address" toml:"address"`
+	User     string `json:"user" yaml:"user" toml:"user"`
+	Password string `json:"password" yaml:"password" toml:"password"`
+	Database string `json:"database" yaml:"database" toml:"database"`
+	Insecure bool   `json:"insecure" yaml:"insecure" toml:"insecure"`
+	Verbose  bool   `json:"verbose" yaml:"verbose" toml:"verbose"`
 }
 
 func SetupDatabase(shouldSyncDBTables bool, config Config) (DB, error) {
@@ -34,6 +34,24 @@ func SetupDatabase(shouldSyncDBTables bool, config Config) (DB, error) {
 		if _, err := db.NewCreateTable().Model((*Tag)(nil)).Exec(context.TODO()); err != nil {
 			return nil, err
 		}
+		if _, err := db.NewCreateTable().Model((*GithubAccount)(nil)).Exec(context.TODO()); err != nil {
+			return nil, err
+		}
+		if _, err := db.NewCreateTable().Model((*ContributorOverride)(nil)).Exec(context.TODO()); err != nil {
+			return nil, err
+		}
+		if _, err := db.NewCreateTable().Model((*GuildSettings)(nil)).Exec(context.TODO()); err != nil {
+			return nil, err
+		}
+		if _, err := db.NewCreateTable().Model((*PendingRelease)(nil)).Exec(context.TODO()); err != nil {
+			return nil, err
+		}
+		if _, err := db.NewCreateTable().Model((*ChannelAllowlist)(nil)).Exec(context.TODO()); err != nil {
+			return nil, err
+		}
+		if _, err := db.NewCreateTable().Model((*DocsPrefs)(nil)).Exec(context.TODO()); err != nil {
+			return nil, err
+		}
 	}
 
 	return &sqlDB{db: db}, nil
@@ -41,6 +59,14 @@ func SetupDatabase(shouldSyncDBTables bool, config Config) (DB, error) {
 
 type DB interface {
 	TagsDB
+	GithubAccountsDB
+	ContributorOverridesDB
+	GuildSettingsDB
+	PendingReleasesDB
+	ChannelAllowlistsDB
+	DocsPrefsDB
+	Ping(ctx context.Context) error
+	Stats() sql.DBStats
 	Close()
 }
 
@@ -48,6 +74,14 @@ type sqlDB struct {
 	db *bun.DB
 }
 
+func (s *sqlDB) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *sqlDB) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
 func (s *sqlDB) Close() {
 	s.db.Close()
 }
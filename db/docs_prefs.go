@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type DocsPrefsDB interface {
+	GetDocsPrefs(ctx context.Context, userID snowflake.ID) (DocsPrefs, error)
+	SetDocsPrefs(prefs DocsPrefs) error
+}
+
+// DocsPrefs is how a user wants /docs results to be shown.
+type DocsPrefs struct {
+	UserID    snowflake.ID `bun:"user_id,pk"`
+	Style     string       `bun:"style,notnull"`
+	Source    bool         `bun:"source,notnull"`
+	Examples  bool         `bun:"examples,notnull"`
+	UpdatedAt time.Time    `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (s *sqlDB) GetDocsPrefs(ctx context.Context, userID snowflake.ID) (prefs DocsPrefs, err error) {
+	err = s.db.NewSelect().
+		Model(&prefs).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	return
+}
+
+func (s *sqlDB) SetDocsPrefs(prefs DocsPrefs) (err error) {
+	prefs.UpdatedAt = time.Now()
+	_, err = s.db.NewInsert().Model(&prefs).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("style = EXCLUDED.style").
+		Set("source = EXCLUDED.source").
+		Set("examples = EXCLUDED.examples").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(context.TODO())
+	return
+}
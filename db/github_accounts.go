@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type GithubAccountsDB interface {
+	GetGithubAccount(userID snowflake.ID) (GithubAccount, error)
+	GetAllGithubAccounts() ([]GithubAccount, error)
+	SetGithubAccount(userID snowflake.ID, login string, repos []string) error
+}
+
+type GithubAccount struct {
+	UserID    snowflake.ID `bun:"user_id,pk"`
+	Login     string       `bun:"login,notnull"`
+	Repos     []string     `bun:"repos,array"`
+	UpdatedAt time.Time    `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (s *sqlDB) GetGithubAccount(userID snowflake.ID) (account GithubAccount, err error) {
+	err = s.db.NewSelect().
+		Model(&account).
+		Where("user_id = ?", userID).
+		Scan(context.TODO())
+	return
+}
+
+func (s *sqlDB) GetAllGithubAccounts() (accounts []GithubAccount, err error) {
+	err = s.db.NewSelect().
+		Model(&accounts).
+		Scan(context.TODO())
+	return
+}
+
+func (s *sqlDB) SetGithubAccount(userID snowflake.ID, login string, repos []string) (err error) {
+	_, err = s.db.NewInsert().Model(&GithubAccount{
+		UserID:    userID,
+		Login:     login,
+		Repos:     repos,
+		UpdatedAt: time.Now(),
+	}).
+		On("CONFLICT (user_id) DO UPDATE").
+		Set("login = EXCLUDED.login").
+		Set("repos = EXCLUDED.repos").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(context.TODO())
+	return
+}
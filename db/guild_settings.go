@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type GuildSettingsDB interface {
+	GetGuildSettings(ctx context.Context, guildID snowflake.ID) (GuildSettings, error)
+	SetGuildPrefix(guildID snowflake.ID, prefix string) error
+}
+
+type GuildSettings struct {
+	GuildID   snowflake.ID `bun:"guild_id,pk"`
+	Prefix    string       `bun:"prefix,notnull"`
+	UpdatedAt time.Time    `bun:"updated_at,notnull,default:current_timestamp"`
+}
+
+func (s *sqlDB) GetGuildSettings(ctx context.Context, guildID snowflake.ID) (settings GuildSettings, err error) {
+	err = s.db.NewSelect().
+		Model(&settings).
+		Where("guild_id = ?", guildID).
+		Scan(ctx)
+	return
+}
+
+func (s *sqlDB) SetGuildPrefix(guildID snowflake.ID, prefix string) (err error) {
+	_, err = s.db.NewInsert().Model(&GuildSettings{
+		GuildID:   guildID,
+		Prefix:    prefix,
+		UpdatedAt: time.Now(),
+	}).
+		On("CONFLICT (guild_id) DO UPDATE").
+		Set("prefix = EXCLUDED.prefix").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(context.TODO())
+	return
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type PendingReleasesDB interface {
+	AddPendingRelease(release PendingRelease) error
+	GetPendingReleases(repo string) ([]PendingRelease, error)
+	DeletePendingReleases(ids []int64, post func() error) error
+}
+
+// PendingRelease is a release held back until the next digest of its repository is posted.
+type PendingRelease struct {
+	ID          int64     `bun:"id,pk,autoincrement"`
+	Repo        string    `bun:"repo,notnull"`
+	TagName     string    `bun:"tag_name,notnull"`
+	URL         string    `bun:"url,notnull"`
+	Author      string    `bun:"author,notnull"`
+	PublishedAt time.Time `bun:"published_at,notnull"`
+}
+
+func (s *sqlDB) AddPendingRelease(release PendingRelease) (err error) {
+	_, err = s.db.NewInsert().Model(&release).Exec(context.TODO())
+	return
+}
+
+func (s *sqlDB) GetPendingReleases(repo string) (releases []PendingRelease, err error) {
+	err = s.db.NewSelect().
+		Model(&releases).
+		Where("repo = ?", repo).
+		Order("published_at ASC").
+		Scan(context.TODO())
+	return
+}
+
+// DeletePendingReleases deletes the pending releases and calls post in the same transaction. The releases are kept if
+// post fails and post isn't called if they can't be deleted, so a failed delete doesn't post them again.
+func (s *sqlDB) DeletePendingReleases(ids []int64, post func() error) error {
+	if len(ids) == 0 {
+		return post()
+	}
+	return s.db.RunInTx(context.TODO(), nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().
+			Model((*PendingRelease)(nil)).
+			Where("id IN (?)", bun.In(ids)).
+			Exec(ctx); err != nil {
+			return err
+		}
+		return post()
+	})
+}
@@ -0,0 +1,82 @@
+package eventbus
+
+import (
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/disgoorg/log"
+)
+
+func New(logger log.Logger) *Bus {
+	return &Bus{
+		logger:      logger,
+		subscribers: map[reflect.Type][]*subscriber{},
+	}
+}
+
+// Bus lets external code subscribe to typed events published by the butler subsystems.
+type Bus struct {
+	logger log.Logger
+
+	mu          sync.RWMutex
+	subscribers map[reflect.Type][]*subscriber
+}
+
+type subscriber struct {
+	handler func(event any)
+	async   bool
+}
+
+// Subscribe registers a handler for events of type E and returns a function to unsubscribe it.
+// Async handlers are called in their own goroutine, others are called in order by Publish.
+// Panics in handlers are recovered and logged.
+func Subscribe[E any](bus *Bus, handler func(event E), async bool) func() {
+	eventType := reflect.TypeOf((*E)(nil)).Elem()
+	sub := &subscriber{
+		handler: func(event any) {
+			handler(event.(E))
+		},
+		async: async,
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.subscribers[eventType] = append(bus.subscribers[eventType], sub)
+
+	return func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		subs := bus.subscribers[eventType]
+		for i := range subs {
+			if subs[i] == sub {
+				bus.subscribers[eventType] = append(subs[:i:i], subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish sends the event to all subscribers of its type.
+func (b *Bus) Publish(event any) {
+	b.mu.RLock()
+	subs := b.subscribers[reflect.TypeOf(event)]
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.async {
+			go b.call(sub, event)
+			continue
+		}
+		b.call(sub, event)
+	}
+}
+
+func (b *Bus) call(sub *subscriber, event any) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Errorf("recovered from panic in %T subscriber: %v\n%s", event, r, debug.Stack())
+		}
+	}()
+	sub.handler(event)
+}
@@ -0,0 +1,27 @@
+package eventbus
+
+import (
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ReleaseAnnounced is published after a GitHub release has been announced.
+type ReleaseAnnounced struct {
+	Repo      string
+	TagName   string
+	URL       string
+	ChannelID snowflake.ID
+	MessageID snowflake.ID
+}
+
+// ModMailThreadOpened is published after a new mod mail thread has been created for a user.
+type ModMailThreadOpened struct {
+	UserID      snowflake.ID
+	DMChannelID snowflake.ID
+	ThreadID    snowflake.ID
+}
+
+// ContributorRoleAdded is published after a contributor role has been added to a member.
+type ContributorRoleAdded struct {
+	UserID snowflake.ID
+	RoleID snowflake.ID
+}
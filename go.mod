@@ -3,6 +3,7 @@ module github.com/disgoorg/disgo-butler
 go 1.18
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/disgoorg/disgo v0.13.5
 	github.com/disgoorg/log v1.2.0
 	github.com/disgoorg/snowflake/v2 v2.0.0
@@ -10,12 +11,12 @@ require (
 	github.com/go-chi/chi/v5 v5.0.7
 	github.com/google/go-github/v44 v44.1.0
 	github.com/hhhapz/doc v0.5.1
-	github.com/lithammer/fuzzysearch v1.1.3
 	github.com/uptrace/bun v1.0.22
 	github.com/uptrace/bun/dialect/pgdialect v1.0.22
 	github.com/uptrace/bun/driver/pgdriver v1.0.22
 	github.com/uptrace/bun/extra/bundebug v1.0.22
 	golang.org/x/exp v0.0.0-20220325121720-054d8573a5d8
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -34,6 +35,5 @@ require (
 	golang.org/x/crypto v0.0.0-20220126234351-aa10faf2a1f8 // indirect
 	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2 // indirect
 	golang.org/x/sys v0.0.0-20220114195835-da31bd327af9 // indirect
-	golang.org/x/text v0.3.7 // indirect
 	mellium.im/sasl v0.2.1 // indirect
 )
@@ -0,0 +1,190 @@
+package mod_mail
+
+import (
+	"sort"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const defaultHistoryRetentionDays = 90
+
+// TicketRecord is the activity of a single ticket kept for the mod mail statistics.
+type TicketRecord struct {
+	DMChannelID  snowflake.ID `json:"dm_channel_id" yaml:"dm_channel_id" toml:"dm_channel_id"`
+	GuildID      snowflake.ID `json:"guild_id" yaml:"guild_id" toml:"guild_id"`
+	OpenedAt     time.Time    `json:"opened_at" yaml:"opened_at" toml:"opened_at"`
+	FirstReplyAt *time.Time   `json:"first_reply_at,omitempty" yaml:"first_reply_at,omitempty" toml:"first_reply_at,omitempty"`
+	ClosedAt     *time.Time   `json:"closed_at,omitempty" yaml:"closed_at,omitempty" toml:"closed_at,omitempty"`
+	// Replies are the amount of messages sent to the user by staff member ID.
+	Replies map[string]int `json:"replies,omitempty" yaml:"replies,omitempty" toml:"replies,omitempty"`
+}
+
+// ActivityStats summarizes the tickets opened in a time range.
+type ActivityStats struct {
+	Opened    int
+	Closed    int
+	Responded int
+	// AverageResponse is the average time until the first staff reply of the responded tickets.
+	AverageResponse time.Duration
+	// OpenedPerDay are the opened tickets by day in the configured timezone, oldest first. Days without tickets are included.
+	OpenedPerDay []DayCount
+	// OpenedPerHour are the opened tickets by the hour of the day in the configured timezone.
+	OpenedPerHour [24]int
+	// TopRepliers are the staff members ordered by their amount of replies, most first.
+	TopRepliers []ReplierCount
+}
+
+type DayCount struct {
+	Day   time.Time
+	Count int
+}
+
+type ReplierCount struct {
+	UserID  snowflake.ID
+	Replies int
+}
+
+// loadHistory splits the persisted records into the open and closed tickets. Open records whose ticket is gone are
+// kept as closed. DMThreads must be loaded.
+func (m *ModMail) loadHistory(records []TicketRecord) {
+	for i := range records {
+		record := records[i]
+		if _, ok := m.DMThreads[record.DMChannelID]; ok && record.ClosedAt == nil {
+			m.openRecords[record.DMChannelID] = &record
+			continue
+		}
+		m.closedRecords = append(m.closedRecords, record)
+	}
+}
+
+// history returns all records within the retention to persist them. Mu must be held.
+func (m *ModMail) history() []TicketRecord {
+	retentionDays := m.config.HistoryRetentionDays
+	if retentionDays <= 0 {
+		retentionDays = defaultHistoryRetentionDays
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	records := make([]TicketRecord, 0, len(m.closedRecords)+len(m.openRecords))
+	for _, record := range m.closedRecords {
+		if record.OpenedAt.After(cutoff) {
+			records = append(records, record)
+		}
+	}
+	for _, record := range m.openRecords {
+		records = append(records, *record)
+	}
+	return records
+}
+
+// recordOpened starts the record of a new ticket. Mu must be held.
+func (m *ModMail) recordOpened(dmChannelID snowflake.ID, guildID snowflake.ID) {
+	m.openRecords[dmChannelID] = &TicketRecord{
+		DMChannelID: dmChannelID,
+		GuildID:     guildID,
+		OpenedAt:    time.Now(),
+	}
+}
+
+// recordReply counts a staff reply sent to the user. Mu must be held.
+func (m *ModMail) recordReply(dmChannelID snowflake.ID, userID snowflake.ID) {
+	record, ok := m.openRecords[dmChannelID]
+	if !ok {
+		return
+	}
+	if record.FirstReplyAt == nil {
+		now := time.Now()
+		record.FirstReplyAt = &now
+	}
+	if record.Replies == nil {
+		record.Replies = map[string]int{}
+	}
+	record.Replies[userID.String()]++
+}
+
+// recordClosed finishes the record of the ticket. Mu must be held.
+func (m *ModMail) recordClosed(dmChannelID snowflake.ID) {
+	record, ok := m.openRecords[dmChannelID]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	record.ClosedAt = &now
+	m.closedRecords = append(m.closedRecords, *record)
+	delete(m.openRecords, dmChannelID)
+}
+
+// ActivityStats returns the statistics of the tickets in the guild opened since the given time.
+func (m *ModMail) ActivityStats(guildID snowflake.ID, since time.Time) ActivityStats {
+	m.Mu.Lock()
+	records := make([]TicketRecord, 0, len(m.closedRecords)+len(m.openRecords))
+	records = append(records, m.closedRecords...)
+	for _, record := range m.openRecords {
+		records = append(records, *record)
+	}
+	m.Mu.Unlock()
+
+	location := common.Location()
+	firstDay := startOfDay(since.In(location))
+	today := startOfDay(time.Now().In(location))
+	var stats ActivityStats
+	for day := firstDay; !day.After(today); day = day.AddDate(0, 0, 1) {
+		stats.OpenedPerDay = append(stats.OpenedPerDay, DayCount{Day: day})
+	}
+
+	var (
+		responseTotal time.Duration
+		replies       = map[snowflake.ID]int{}
+	)
+	for _, record := range records {
+		if record.GuildID != guildID || record.OpenedAt.Before(since) {
+			continue
+		}
+		stats.Opened++
+		if record.ClosedAt != nil {
+			stats.Closed++
+		}
+		if record.FirstReplyAt != nil {
+			stats.Responded++
+			responseTotal += record.FirstReplyAt.Sub(record.OpenedAt)
+		}
+		for key, count := range record.Replies {
+			userID, err := snowflake.Parse(key)
+			if err != nil {
+				continue
+			}
+			replies[userID] += count
+		}
+
+		openedAt := record.OpenedAt.In(location)
+		stats.OpenedPerHour[openedAt.Hour()]++
+		// days are not always 24 hours long, so count them instead of dividing
+		for i := range stats.OpenedPerDay {
+			if stats.OpenedPerDay[i].Day.Equal(startOfDay(openedAt)) {
+				stats.OpenedPerDay[i].Count++
+				break
+			}
+		}
+	}
+	if stats.Responded > 0 {
+		stats.AverageResponse = responseTotal / time.Duration(stats.Responded)
+	}
+
+	for userID, count := range replies {
+		stats.TopRepliers = append(stats.TopRepliers, ReplierCount{UserID: userID, Replies: count})
+	}
+	sort.Slice(stats.TopRepliers, func(i, j int) bool {
+		if stats.TopRepliers[i].Replies != stats.TopRepliers[j].Replies {
+			return stats.TopRepliers[i].Replies > stats.TopRepliers[j].Replies
+		}
+		return stats.TopRepliers[i].UserID < stats.TopRepliers[j].UserID
+	})
+	return stats
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,37 @@
+package mod_mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var ErrNotAnonymous = common.NewUserError("the user of this ticket is not anonymized")
+
+// Pseudonym is the name a user is shown as in the ticket thread when their guild anonymizes tickets.
+type Pseudonym struct {
+	Name   string       `json:"name" yaml:"name" toml:"name"`
+	UserID snowflake.ID `json:"user_id" yaml:"user_id" toml:"user_id"`
+}
+
+// newPseudonym returns a random pseudonym for the user like "Anonymous 3FA2C1".
+func newPseudonym(userID snowflake.ID) Pseudonym {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return Pseudonym{
+		Name:   "Anonymous " + strings.ToUpper(hex.EncodeToString(b)),
+		UserID: userID,
+	}
+}
+
+// Pseudonym returns the pseudonym of the user of the given thread if their identity is hidden from the staff.
+// The pseudonym includes the user ID, so staff can reveal who is behind a ticket when needed.
+func (m *ModMail) Pseudonym(threadID snowflake.ID) (Pseudonym, bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	pseudonym, ok := m.pseudonyms[threadID]
+	return pseudonym, ok
+}
@@ -0,0 +1,80 @@
+package mod_mail
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"golang.org/x/exp/slices"
+)
+
+// AttachmentFilterConfig controls which attachments of users are forwarded into ticket threads.
+// Extensions are matched without the leading dot, content types without parameters. Empty lists disable the filter.
+type AttachmentFilterConfig struct {
+	// AllowedExtensions and AllowedContentTypes block every attachment not matching one of them if set.
+	AllowedExtensions   []string `json:"allowed_extensions" yaml:"allowed_extensions" toml:"allowed_extensions"`
+	AllowedContentTypes []string `json:"allowed_content_types" yaml:"allowed_content_types" toml:"allowed_content_types"`
+	// BlockedExtensions and BlockedContentTypes block every attachment matching one of them.
+	BlockedExtensions   []string `json:"blocked_extensions" yaml:"blocked_extensions" toml:"blocked_extensions"`
+	BlockedContentTypes []string `json:"blocked_content_types" yaml:"blocked_content_types" toml:"blocked_content_types"`
+}
+
+// filterAttachments splits the attachments into the allowed ones and notes about the blocked ones.
+func (c AttachmentFilterConfig) filterAttachments(attachments []discord.Attachment) ([]discord.Attachment, []string) {
+	var (
+		allowed []discord.Attachment
+		notes   []string
+	)
+	for _, attachment := range attachments {
+		extension := strings.ToLower(strings.TrimPrefix(filepath.Ext(attachment.Filename), "."))
+		var contentType string
+		if attachment.ContentType != nil {
+			contentType, _, _ = mime.ParseMediaType(*attachment.ContentType)
+		}
+		if c.blocked(extension, contentType) {
+			kind := extension
+			if kind == "" {
+				kind = contentType
+			}
+			if kind == "" {
+				kind = "unknown"
+			}
+			notes = append(notes, fmt.Sprintf("⚠️ blocked attachment `%s` (%s)", attachment.Filename, kind))
+			continue
+		}
+		allowed = append(allowed, attachment)
+	}
+	return allowed, notes
+}
+
+func (c AttachmentFilterConfig) blocked(extension string, contentType string) bool {
+	if containsFold(c.BlockedExtensions, extension) || containsFold(c.BlockedContentTypes, contentType) {
+		return true
+	}
+	if len(c.AllowedExtensions) == 0 && len(c.AllowedContentTypes) == 0 {
+		return false
+	}
+	return !containsFold(c.AllowedExtensions, extension) && !containsFold(c.AllowedContentTypes, contentType)
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	return slices.IndexFunc(values, func(v string) bool {
+		return strings.EqualFold(strings.TrimPrefix(v, "."), value)
+	}) != -1
+}
+
+// withAttachmentNotes appends the notes about blocked attachments to the content.
+func withAttachmentNotes(content string, notes []string) string {
+	if len(notes) == 0 {
+		return content
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + strings.Join(notes, "\n")
+}
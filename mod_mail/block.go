@@ -0,0 +1,43 @@
+package mod_mail
+
+import (
+	"sort"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// BlockUser ignores all further DMs of the user. It does not close their open ticket.
+func (m *ModMail) BlockUser(userID snowflake.ID) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.blocked[userID] = struct{}{}
+}
+
+// UnblockUser lets the user open tickets again and reports whether they were blocked.
+func (m *ModMail) UnblockUser(userID snowflake.ID) bool {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	_, ok := m.blocked[userID]
+	delete(m.blocked, userID)
+	return ok
+}
+
+// IsBlocked reports whether DMs of the user are ignored.
+func (m *ModMail) IsBlocked(userID snowflake.ID) bool {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	_, ok := m.blocked[userID]
+	return ok
+}
+
+// blockedUserIDs returns the blocked users in a stable order to persist them. Mu must be held.
+func (m *ModMail) blockedUserIDs() []snowflake.ID {
+	userIDs := make([]snowflake.ID, 0, len(m.blocked))
+	for userID := range m.blocked {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		return userIDs[i] < userIDs[j]
+	})
+	return userIDs
+}
@@ -0,0 +1,128 @@
+package mod_mail
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/json"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const claimedSuffix = " (claimed by "
+
+var ErrNotClaimed = common.NewUserError("this ticket is not claimed")
+
+// Claim is a staff member handling a ticket.
+type Claim struct {
+	UserID snowflake.ID `json:"user_id" yaml:"user_id" toml:"user_id"`
+	// Exclusive only forwards replies of the claiming staff member to the user.
+	Exclusive bool `json:"exclusive,omitempty" yaml:"exclusive,omitempty" toml:"exclusive,omitempty"`
+}
+
+// Ticket is an open ticket with its claim if any.
+type Ticket struct {
+	ThreadID    snowflake.ID
+	DMChannelID snowflake.ID
+	GuildID     snowflake.ID
+	Claim       *Claim
+}
+
+// Tickets returns all open tickets.
+func (m *ModMail) Tickets() []Ticket {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	tickets := make([]Ticket, 0, len(m.DMThreads))
+	for dmID, threadID := range m.DMThreads {
+		ticket := Ticket{
+			ThreadID:    threadID,
+			DMChannelID: dmID,
+			GuildID:     m.threadGuilds[threadID],
+		}
+		if claim, ok := m.claims[threadID]; ok {
+			ticket.Claim = &claim
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets
+}
+
+// ClaimThread records the given staff member as the one handling the ticket and shows the claim in the thread name.
+// Claiming an already claimed ticket hands it over and returns the previous claim.
+func (m *ModMail) ClaimThread(client bot.Client, threadID snowflake.ID, user discord.User, exclusive bool) (*Claim, error) {
+	m.Mu.Lock()
+	if _, ok := m.ThreadDMs[threadID]; !ok {
+		m.Mu.Unlock()
+		return nil, ErrNoTicket
+	}
+	var previous *Claim
+	if claim, ok := m.claims[threadID]; ok {
+		previous = &claim
+	}
+	m.claims[threadID] = Claim{
+		UserID:    user.ID,
+		Exclusive: exclusive,
+	}
+	m.Mu.Unlock()
+
+	return previous, renameClaimedThread(client, threadID, user.Username)
+}
+
+// UnclaimThread removes the claim of the ticket and returns it.
+func (m *ModMail) UnclaimThread(client bot.Client, threadID snowflake.ID) (*Claim, error) {
+	m.Mu.Lock()
+	if _, ok := m.ThreadDMs[threadID]; !ok {
+		m.Mu.Unlock()
+		return nil, ErrNoTicket
+	}
+	claim, ok := m.claims[threadID]
+	if !ok {
+		m.Mu.Unlock()
+		return nil, ErrNotClaimed
+	}
+	delete(m.claims, threadID)
+	m.Mu.Unlock()
+
+	return &claim, renameClaimedThread(client, threadID, "")
+}
+
+// blockedByClaim reports whether the message of the given staff member must not be forwarded because another staff member
+// claimed the ticket exclusively.
+func (m *ModMail) blockedByClaim(threadID snowflake.ID, userID snowflake.ID) (Claim, bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	claim, ok := m.claims[threadID]
+	return claim, ok && claim.Exclusive && claim.UserID != userID
+}
+
+// renameClaimedThread replaces the claim in the thread name with the given claimer or removes it if claimer is empty.
+func renameClaimedThread(client bot.Client, threadID snowflake.ID, claimer string) error {
+	thread, err := client.Rest().GetChannel(threadID)
+	if err != nil {
+		return err
+	}
+	name, _, _ := strings.Cut(thread.Name(), claimedSuffix)
+	if claimer != "" {
+		name += claimedSuffix + claimer + ")"
+	}
+	if name == thread.Name() {
+		return nil
+	}
+	_, err = client.Rest().UpdateChannel(threadID, discord.GuildThreadUpdate{
+		Name: json.NewPtr(common.Truncate(name, 100)),
+	})
+	return err
+}
+
+// claimedNotice is posted when a staff member replies to a ticket exclusively claimed by someone else.
+func (m *ModMail) claimedNotice(client bot.Client, message discord.Message, claim Claim) {
+	if _, err := client.Rest().CreateMessage(message.ChannelID, discord.MessageCreate{
+		Content:          fmt.Sprintf("This ticket is claimed by %s, your message was not sent to the user.", discord.UserMention(claim.UserID)),
+		MessageReference: &discord.MessageReference{MessageID: &message.ID},
+		AllowedMentions:  &discord.AllowedMentions{},
+	}); err != nil {
+		m.logs.Error(client.Logger(), "failed to send claimed notice: ", err)
+	}
+}
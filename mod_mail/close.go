@@ -0,0 +1,79 @@
+package mod_mail
+
+import (
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// CloseThread closes the ticket of the given thread and tells the user who closed it and why, if a reason is given. In
+// guilds with anonymous replies the closer is shown as anonymousStaffName instead. The forwarded messages of the ticket
+// are forgotten, so later edits are no longer bridged. The thread itself is left to the caller to archive, so it can
+// still respond in it.
+func (m *ModMail) CloseThread(client bot.Client, threadID snowflake.ID, closer discord.User, reason string) error {
+	m.Mu.Lock()
+	dmID, ok := m.ThreadDMs[threadID]
+	if !ok {
+		m.Mu.Unlock()
+		return ErrNoTicket
+	}
+	// the guild of the thread is forgotten below
+	anonymous := m.guilds[m.threadGuilds[threadID]].AnonymousReplies
+	linkedThreadIDs := map[snowflake.ID]struct{}{}
+	for linkedThreadID, threadDMID := range m.ThreadDMs {
+		if threadDMID == dmID {
+			linkedThreadIDs[linkedThreadID] = struct{}{}
+			delete(m.ThreadDMs, linkedThreadID)
+			delete(m.threadParents, linkedThreadID)
+			delete(m.threadGuilds, linkedThreadID)
+			delete(m.claims, linkedThreadID)
+			delete(m.pseudonyms, linkedThreadID)
+		}
+	}
+	for messageID, message := range m.dmMessageIDs {
+		if _, ok = linkedThreadIDs[message.ThreadID]; ok {
+			delete(m.dmMessageIDs, messageID)
+		}
+	}
+	for messageID, message := range m.threadMessageIDs {
+		if _, ok = linkedThreadIDs[message.ThreadID]; ok {
+			delete(m.threadMessageIDs, messageID)
+		}
+	}
+	delete(m.DMThreads, dmID)
+	m.recordClosed(dmID)
+	m.Mu.Unlock()
+
+	author := &discord.EmbedAuthor{
+		Name:    closer.Tag(),
+		IconURL: closer.EffectiveAvatarURL(),
+	}
+	if anonymous {
+		author = &discord.EmbedAuthor{Name: anonymousStaffName}
+	}
+	embed := discord.Embed{
+		Author:      author,
+		Description: common.Message(common.MessageModMailClosed),
+		Color:       0xFF0000,
+	}
+	if reason != "" {
+		embed.Fields = []discord.EmbedField{{Name: "Reason", Value: common.Truncate(reason, common.Limits.EmbedFieldLength)}}
+	}
+	if _, err := client.Rest().CreateMessage(dmID, discord.MessageCreate{
+		Embeds: []discord.Embed{embed},
+	}); err != nil {
+		m.logs.Error(client.Logger(), "failed to close ticket in dm: ", err)
+	}
+	return nil
+}
+
+// triageButtons are added to the first message of a ticket to claim, close or block it with one click.
+// They are handled by the mod_mail component.
+func triageButtons(userID snowflake.ID) discord.ContainerComponent {
+	return discord.NewActionRow(
+		discord.NewPrimaryButton("Claim", "mod_mail:claim"),
+		discord.NewSecondaryButton("Close", "mod_mail:close"),
+		discord.NewDangerButton("Block", discord.CustomID("mod_mail:block:"+userID.String())),
+	)
+}
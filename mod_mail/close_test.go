@@ -0,0 +1,77 @@
+package mod_mail
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/disgo"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// messageRecorder answers all requests of the rest client with an empty message and keeps the created messages.
+type messageRecorder struct {
+	messages []discord.MessageCreate
+}
+
+func (r *messageRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var messageCreate discord.MessageCreate
+	if err := json.NewDecoder(req.Body).Decode(&messageCreate); err != nil {
+		return nil, err
+	}
+	r.messages = append(r.messages, messageCreate)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"1","channel_id":"1"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestCloseThreadAuthor(t *testing.T) {
+	const (
+		guildID  snowflake.ID = 817327181659111454
+		threadID snowflake.ID = 817327181659111459
+		dmID     snowflake.ID = 817327181659111460
+	)
+	closer := discord.User{ID: 170939974227591168, Username: "topi", Discriminator: "0001"}
+
+	tests := []struct {
+		name      string
+		anonymous bool
+		want      discord.EmbedAuthor
+	}{
+		{name: "closer", want: discord.EmbedAuthor{Name: closer.Tag(), IconURL: closer.EffectiveAvatarURL()}},
+		{name: "anonymous replies", anonymous: true, want: discord.EmbedAuthor{Name: anonymousStaffName}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &messageRecorder{}
+			token := base64.StdEncoding.EncodeToString([]byte(guildID.String())) + ".token.test"
+			client, err := disgo.New(token, bot.WithRestClientConfigOpts(rest.WithHTTPClient(&http.Client{Transport: recorder})))
+			if err != nil {
+				t.Fatalf("failed to create client: %s", err)
+			}
+
+			m := New(Config{
+				Guilds:  map[string]GuildConfig{guildID.String(): {ChannelID: 1, AnonymousReplies: tt.anonymous}},
+				Threads: []Thread{{ThreadID: threadID, ChannelID: dmID, GuildID: guildID}},
+			}, nil, nil)
+			if err = m.CloseThread(client, threadID, closer, ""); err != nil {
+				t.Fatalf("CloseThread() error = %v", err)
+			}
+			if len(recorder.messages) != 1 || len(recorder.messages[0].Embeds) != 1 {
+				t.Fatalf("sent %+v, want a single embed", recorder.messages)
+			}
+			if author := recorder.messages[0].Embeds[0].Author; author == nil || *author != tt.want {
+				t.Errorf("author = %+v, want %+v", author, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package mod_mail
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	deliveryQueueSize = 20
+	deliveryAttempts  = 3
+)
+
+type delivery struct {
+	deliver func() error
+	failed  func(err error)
+}
+
+// deliveryQueue delivers messages per conversation in order and retries them on transient failures.
+type deliveryQueue struct {
+	mu      sync.Mutex
+	pending map[snowflake.ID][]delivery
+	running map[snowflake.ID]bool
+}
+
+func newDeliveryQueue() *deliveryQueue {
+	return &deliveryQueue{
+		pending: map[snowflake.ID][]delivery{},
+		running: map[snowflake.ID]bool{},
+	}
+}
+
+// enqueue adds the delivery to the queue of the conversation. If the queue is full the delivery fails immediately.
+func (q *deliveryQueue) enqueue(conversationID snowflake.ID, d delivery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending[conversationID]) >= deliveryQueueSize {
+		go d.failed(fmt.Errorf("delivery queue is full"))
+		return
+	}
+	q.pending[conversationID] = append(q.pending[conversationID], d)
+	if !q.running[conversationID] {
+		q.running[conversationID] = true
+		go q.run(conversationID)
+	}
+}
+
+func (q *deliveryQueue) run(conversationID snowflake.ID) {
+	for {
+		q.mu.Lock()
+		deliveries := q.pending[conversationID]
+		if len(deliveries) == 0 {
+			delete(q.pending, conversationID)
+			delete(q.running, conversationID)
+			q.mu.Unlock()
+			return
+		}
+		d := deliveries[0]
+		q.pending[conversationID] = deliveries[1:]
+		q.mu.Unlock()
+
+		if err := d.deliverSafe(); err != nil {
+			d.failed(err)
+		}
+	}
+}
+
+// deliverSafe delivers with retries and turns panics into errors, so a single delivery can't stop the queue of the
+// conversation.
+func (d delivery) deliverSafe() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	backoff := &common.Backoff{Min: time.Second, Max: 10 * time.Second}
+	return common.Retry(context.Background(), backoff, deliveryAttempts, d.deliver)
+}
+
+// deliveryFailed posts a notice about a message which could not be delivered in the thread.
+func (m *ModMail) deliveryFailed(client bot.Client, threadID snowflake.ID, notice string, err error) {
+	if _, err = client.Rest().CreateMessage(threadID, discord.MessageCreate{
+		Embeds: []discord.Embed{
+			{
+				Description: fmt.Sprintf("%s: `%s`", notice, err),
+				Color:       0xFF0000,
+			},
+		},
+	}); err != nil {
+		m.logs.Error(client.Logger(), "failed to send delivery failure notice: ", err)
+	}
+}
@@ -0,0 +1,56 @@
+package mod_mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeliveryQueuePanic(t *testing.T) {
+	q := newDeliveryQueue()
+	failed := make(chan error, 1)
+	delivered := make(chan struct{})
+
+	q.enqueue(1, delivery{
+		deliver: func() error {
+			var m map[string]int
+			m["panic"]++
+			return nil
+		},
+		failed: func(err error) {
+			failed <- err
+		},
+	})
+	q.enqueue(1, delivery{
+		deliver: func() error {
+			close(delivered)
+			return nil
+		},
+		failed: func(err error) {
+			t.Errorf("second delivery failed: %s", err)
+		},
+	})
+
+	select {
+	case err := <-failed:
+		if err == nil || !strings.HasPrefix(err.Error(), "panic: ") {
+			t.Errorf("failed with %v, want the panic as error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("panicking delivery did not fail")
+	}
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("delivery after the panic was not delivered")
+	}
+}
+
+func TestThreadWebhookMissing(t *testing.T) {
+	m := New(Config{}, nil, nil)
+	m.threadGuilds[2] = 3
+	if _, err := m.threadWebhook(2); !errors.Is(err, errNoThreadWebhook) {
+		t.Errorf("threadWebhook() error = %v, want %v", err, errNoThreadWebhook)
+	}
+}
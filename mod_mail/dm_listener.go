@@ -3,11 +3,15 @@ package mod_mail
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/eventbus"
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 func (m *ModMail) dmMessageCreateListener(event *events.DMMessageCreate) {
@@ -16,151 +20,391 @@ func (m *ModMail) dmMessageCreateListener(event *events.DMMessageCreate) {
 	}
 
 	go func() {
-		accepted := true
 		m.Mu.Lock()
 		threadID, ok := m.DMThreads[event.ChannelID]
-		m.Mu.Unlock()
 		if !ok {
-			newTicketMessage, err := event.Client().Rest().CreateMessage(event.ChannelID, discord.NewMessageCreateBuilder().
-				SetEmbeds(discord.NewEmbedBuilder().
-					SetDescription("Are you sure you want to open a ticket?").
-					Build(),
-				).
-				AddActionRow(discord.NewSuccessButton("Yes", "yes"), discord.NewDangerButton("No", "no")).
-				Build(),
-			)
-			if err != nil {
-				event.Client().Logger().Error("failed to send new ticket message: ", err)
+			if _, blocked := m.blocked[event.Message.Author.ID]; blocked {
+				m.Mu.Unlock()
+				return
+			}
+			_, pending := m.pendingMessages[event.Message.Author.ID]
+			// hold the message back until the ticket is opened
+			m.pendingMessages[event.Message.Author.ID] = append(m.pendingMessages[event.Message.Author.ID], event.Message)
+			m.Mu.Unlock()
+			if pending {
 				return
 			}
+			m.openTicket(event.Client(), event.ChannelID, event.Message.Author)
+			return
+		}
+		m.Mu.Unlock()
+		m.forwardToThread(event.Client(), event.ChannelID, threadID, event.Message)
+	}()
+}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-			defer cancel()
-			bot.WaitForEvent(event.Client(), ctx, func(e *events.ComponentInteractionCreate) bool {
-				return e.ChannelID() == event.ChannelID && e.Data.Type() == discord.ComponentTypeButton
-			}, func(e *events.ComponentInteractionCreate) {
-				if e.Data.CustomID() == "no" {
-					accepted = false
-					if err := e.UpdateMessage(discord.MessageUpdate{
-						Embeds: &[]discord.Embed{
-							{
-								Description: "No Ticket created.",
-								Color:       0xFF0000,
-							},
-						},
-						Components: &[]discord.ContainerComponent{},
-					}); err != nil {
-						event.Client().Logger().Error("failed to update new ticket message: ", err)
-					}
-					return
-				}
+// releasePending forwards the held back messages of the user to the thread or drops them if no thread was opened.
+func (m *ModMail) releasePending(client bot.Client, dmChannelID snowflake.ID, userID snowflake.ID, threadID snowflake.ID) {
+	m.Mu.Lock()
+	messages := m.pendingMessages[userID]
+	delete(m.pendingMessages, userID)
+	m.Mu.Unlock()
+	if threadID == 0 {
+		return
+	}
+	for _, message := range messages {
+		m.forwardToThread(client, dmChannelID, threadID, message)
+	}
+}
 
-				thread, err := event.Client().Rest().CreateThread(m.channelID, discord.GuildPublicThreadCreate{
-					Name:                event.Message.Author.Tag(),
-					AutoArchiveDuration: discord.AutoArchiveDuration1h,
-				})
-				if err != nil {
-					event.Client().Logger().Error("failed to create new thread: ", err)
-					return
-				}
+// openTicket opens a ticket in the guild the user shares with the bot. If the user shares multiple guilds with
+// mod mail they are asked in which one to open it.
+func (m *ModMail) openTicket(client bot.Client, dmChannelID snowflake.ID, author discord.User) {
+	guildIDs, err := m.memberGuilds(client, author.ID)
+	if err != nil {
+		m.logs.Error(client.Logger(), "failed to get mod mail guilds of user: ", err)
+		m.releasePending(client, dmChannelID, author.ID, 0)
+		return
+	}
+	if len(guildIDs) == 0 {
+		m.releasePending(client, dmChannelID, author.ID, 0)
+		if _, err = client.Rest().CreateMessage(dmChannelID, discord.MessageCreate{
+			Embeds: []discord.Embed{
+				{
+					Description: "You are not a member of any server using this mod mail.",
+					Color:       0xFF0000,
+				},
+			},
+		}); err != nil {
+			m.logs.Error(client.Logger(), "failed to send no guild message: ", err)
+		}
+		return
+	}
+	if m.config.InstantThreads && len(guildIDs) == 1 {
+		m.openInstantly(client, dmChannelID, author, guildIDs[0])
+		return
+	}
+	m.optIn(client, dmChannelID, author, guildIDs)
+}
 
-				threadID = thread.ID()
+// openInstantly opens a ticket without asking the user for confirmation.
+func (m *ModMail) openInstantly(client bot.Client, dmChannelID snowflake.ID, author discord.User, guildID snowflake.ID) {
+	var threadID snowflake.ID
+	defer func() {
+		m.releasePending(client, dmChannelID, author.ID, threadID)
+	}()
 
-				if _, err := m.webhookClient.CreateMessageInThread(discord.WebhookMessageCreate{
-					Content:         fmt.Sprintf("%s\nNew ticket opened by %s(`%s`)", discord.RoleMention(m.roleID), event.Message.Author.Tag(), event.Message.Author.ID),
-					AllowedMentions: &discord.DefaultAllowedMentions,
-				}, threadID); err != nil {
-					event.Client().Logger().Error("failed to create new thread message: ", err)
-				}
+	if description, throttled := m.throttled(client, author); throttled {
+		if _, err := client.Rest().CreateMessage(dmChannelID, discord.MessageCreate{
+			Embeds: []discord.Embed{
+				{
+					Description: description,
+					Color:       0xFF0000,
+				},
+			},
+		}); err != nil {
+			m.logs.Error(client.Logger(), "failed to send throttled message: ", err)
+		}
+		return
+	}
+	var err error
+	if threadID, err = m.openThread(client, dmChannelID, author, guildID); err != nil {
+		m.logs.Error(client.Logger(), "failed to create new thread: ", err)
+	}
+}
 
-				m.Mu.Lock()
-				defer m.Mu.Unlock()
-				m.DMThreads[event.ChannelID] = threadID
-				m.ThreadDMs[threadID] = event.ChannelID
-				if err := e.UpdateMessage(discord.MessageUpdate{
-					Embeds: &[]discord.Embed{
-						{
-							Description: "New Ticket created.",
-							Color:       0x00FF00,
-						},
-					},
-					Components: &[]discord.ContainerComponent{},
-				}); err != nil {
-					event.Client().Logger().Error("failed to update new ticket message: ", err)
-				}
-			}, func() {
-				accepted = false
-				if _, err := event.Client().Rest().UpdateMessage(event.ChannelID, newTicketMessage.ID, discord.MessageUpdate{
-					Embeds: &[]discord.Embed{
-						{
-							Description: "Ticket creation timed out.",
-							Color:       0xFF0000,
-						},
+// optIn asks the user to confirm they want to open a ticket before opening it. With multiple guilds the user picks
+// the guild to open the ticket in instead.
+func (m *ModMail) optIn(client bot.Client, dmChannelID snowflake.ID, author discord.User, guildIDs []snowflake.ID) {
+	var threadID snowflake.ID
+	defer func() {
+		m.releasePending(client, dmChannelID, author.ID, threadID)
+	}()
+
+	messageCreate := discord.NewMessageCreateBuilder().
+		SetEmbeds(discord.NewEmbedBuilder().
+			SetDescription(common.Message(common.MessageModMailOptIn)).
+			Build(),
+		)
+	if len(guildIDs) == 1 {
+		messageCreate.AddActionRow(discord.NewSuccessButton("Yes", "yes"), discord.NewDangerButton("No", "no"))
+	} else {
+		options := make([]discord.SelectMenuOption, 0, len(guildIDs))
+		for _, guildID := range guildIDs {
+			if len(options) == 25 {
+				break
+			}
+			options = append(options, discord.NewSelectMenuOption(guildName(client, guildID), guildID.String()))
+		}
+		messageCreate.
+			AddActionRow(discord.NewSelectMenu("guild", "Select a server", options...)).
+			AddActionRow(discord.NewDangerButton("No", "no"))
+	}
+	newTicketMessage, err := client.Rest().CreateMessage(dmChannelID, messageCreate.Build())
+	if err != nil {
+		m.logs.Error(client.Logger(), "failed to send new ticket message: ", err)
+		return
+	}
+
+	timeout := 20 * time.Second
+	if m.config.OptInTimeoutSeconds > 0 {
+		timeout = time.Duration(m.config.OptInTimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	bot.WaitForEvent(client, ctx, func(e *events.ComponentInteractionCreate) bool {
+		return e.ChannelID() == dmChannelID && (e.Data.Type() == discord.ComponentTypeButton || e.Data.Type() == discord.ComponentTypeSelectMenu)
+	}, func(e *events.ComponentInteractionCreate) {
+		updateNewTicketMessage := func(description string, color int) {
+			if err := e.UpdateMessage(discord.MessageUpdate{
+				Embeds: &[]discord.Embed{
+					{
+						Description: description,
+						Color:       color,
 					},
-					Components: &[]discord.ContainerComponent{},
-				}); err != nil {
-					event.Client().Logger().Error("failed to update new ticket message: ", err)
-				}
-			})
-			if !accepted {
-				return
+				},
+				Components: &[]discord.ContainerComponent{},
+			}); err != nil {
+				m.logs.Error(client.Logger(), "failed to update new ticket message: ", err)
 			}
 		}
-		webhookMessageCreate := discord.WebhookMessageCreate{
-			Content:   event.Message.Content,
-			Username:  event.Message.Author.Username,
-			AvatarURL: event.Message.Author.EffectiveAvatarURL(),
-			Embeds:    event.Message.Embeds,
-			Files:     filesFromAttachments(event.Client(), event.Message.Attachments),
+
+		if e.Data.CustomID() == "no" {
+			updateNewTicketMessage(common.Message(common.MessageModMailDeclined), 0xFF0000)
+			return
 		}
 
-		message, err := m.webhookClient.CreateMessageInThread(webhookMessageCreate, threadID)
-		if err != nil {
-			event.Client().Logger().Error("failed to create thread message: ", err)
+		if description, throttled := m.throttled(client, author); throttled {
+			updateNewTicketMessage(description, 0xFF0000)
 			return
 		}
-		m.Mu.Lock()
-		defer m.Mu.Unlock()
-		m.threadMessageIDs[event.Message.ID] = message.ID
-	}()
+
+		guildID := guildIDs[0]
+		if e.Data.Type() == discord.ComponentTypeSelectMenu {
+			values := e.SelectMenuInteractionData().Values
+			if len(values) == 0 {
+				return
+			}
+			if guildID, err = snowflake.Parse(values[0]); err != nil {
+				m.logs.Error(client.Logger(), "failed to parse selected guild: ", err)
+				return
+			}
+		}
+
+		if threadID, err = m.openThread(client, dmChannelID, author, guildID); err != nil {
+			m.logs.Error(client.Logger(), "failed to create new thread: ", err)
+			return
+		}
+		updateNewTicketMessage(common.Message(common.MessageModMailCreated), 0x00FF00)
+	}, func() {
+		if _, err := client.Rest().UpdateMessage(dmChannelID, newTicketMessage.ID, discord.MessageUpdate{
+			Embeds: &[]discord.Embed{
+				{
+					Description: common.Message(common.MessageModMailOptInTimeout),
+					Color:       0xFF0000,
+				},
+			},
+			Components: &[]discord.ContainerComponent{},
+		}); err != nil {
+			m.logs.Error(client.Logger(), "failed to update new ticket message: ", err)
+		}
+	})
 }
 
-func (m *ModMail) dmMessageUpdateListener(event *events.DMMessageUpdate) {
+// throttled reports whether the user may not open a new ticket right now and returns the message to show them.
+func (m *ModMail) throttled(client bot.Client, author discord.User) (string, bool) {
+	allowed, globallyThrottled := m.throttle.allow(author.ID)
+	if allowed {
+		return "", false
+	}
+	if globallyThrottled {
+		client.Logger().Warnf("mod mail thread creation is being throttled, rejected ticket from %s(%s)", author.Tag(), author.ID)
+		return "We are receiving a lot of tickets right now. Please try again in a few minutes.", true
+	}
+	return "You have opened too many tickets recently. Please try again later.", true
+}
+
+// openThread creates a new ticket thread for the user and announces it to the staff.
+func (m *ModMail) openThread(client bot.Client, dmChannelID snowflake.ID, author discord.User, guildID snowflake.ID) (snowflake.ID, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return 0, fmt.Errorf("mod mail is not configured for guild %s", guildID)
+	}
+	m.Mu.Lock()
+	webhookClient := m.webhookClients[guild.ChannelID]
+	m.Mu.Unlock()
+	name := author.Tag()
+	opener := fmt.Sprintf("%s(`%s`)", author.Tag(), author.ID)
+	var pseudonym *Pseudonym
+	if guild.Anonymize {
+		generated := newPseudonym(author.ID)
+		pseudonym = &generated
+		name = pseudonym.Name
+		opener = pseudonym.Name
+	}
+	threadID, err := m.createThread(client, webhookClient, guild.ChannelID, guild.ThreadSource, name, discord.WebhookMessageCreate{
+		Content:         fmt.Sprintf("%s\nNew ticket opened by %s %s%s", discord.RoleMention(guild.RoleID), opener, common.Timestamp(time.Now()), m.internalPrefixHint()),
+		Components:      []discord.ContainerComponent{triageButtons(author.ID)},
+		AllowedMentions: common.AllowedMentions(common.MentionsModMail, guild.RoleID),
+	})
+	if err != nil {
+		return 0, err
+	}
+
 	m.Mu.Lock()
 	defer m.Mu.Unlock()
+	m.threadGuilds[threadID] = guildID
+	if pseudonym != nil {
+		m.pseudonyms[threadID] = *pseudonym
+	}
+	m.recordOpened(dmChannelID, guildID)
+	m.DMThreads[dmChannelID] = threadID
+	m.ThreadDMs[threadID] = dmChannelID
+	m.bus.Publish(eventbus.ModMailThreadOpened{
+		UserID:      author.ID,
+		DMChannelID: dmChannelID,
+		ThreadID:    threadID,
+	})
+	return threadID, nil
+}
 
-	webhookMessageID, ok := m.threadMessageIDs[event.Message.ID]
-	if !ok {
-		return
+// splitContent splits the content into the parts it has to be sent in to stay within the message length limit.
+func splitContent(content string) []string {
+	parts := common.SplitLines(content, common.Limits.MessageLength)
+	if len(parts) == 0 {
+		return []string{""}
 	}
-	webhookMessageUpdate := discord.WebhookMessageUpdate{
-		Content: &event.Message.Content,
-		Embeds:  &event.Message.Embeds,
-		Files:   filesFromAttachments(event.Client(), event.Message.Attachments),
+	return parts
+}
+
+// forwardToThread queues the DM message to be posted in the thread. Long messages are split into multiple messages.
+func (m *ModMail) forwardToThread(client bot.Client, dmChannelID snowflake.ID, threadID snowflake.ID, message discord.Message) {
+	attachments, notes := m.config.AttachmentFilter.filterAttachments(message.Attachments)
+	parts := splitContent(withAttachmentNotes(message.Content, notes))
+
+	// the messages already sent, so retries continue with the next part instead of sending duplicates
+	var messageIDs []snowflake.ID
+	m.threadDeliveries.enqueue(dmChannelID, delivery{
+		deliver: func() error {
+			m.Mu.Lock()
+			webhookClient, err := m.threadWebhook(threadID)
+			pseudonym, anonymous := m.pseudonyms[threadID]
+			m.Mu.Unlock()
+			if err != nil {
+				return err
+			}
+			username, avatarURL := message.Author.Username, message.Author.EffectiveAvatarURL()
+			if anonymous {
+				// without an avatar URL the default avatar of the webhook is used
+				username, avatarURL = pseudonym.Name, ""
+			}
+			for i := len(messageIDs); i < len(parts); i++ {
+				webhookMessageCreate := discord.WebhookMessageCreate{
+					Content:         parts[i],
+					Username:        username,
+					AvatarURL:       avatarURL,
+					AllowedMentions: common.AllowedMentions(common.MentionsModMail),
+				}
+				// embeds and attachments go with the last part
+				if i == len(parts)-1 {
+					webhookMessageCreate.Embeds = message.Embeds
+					webhookMessageCreate.Files = m.filesFromAttachments(client, attachments)
+				}
+				threadMsg, err := webhookClient.CreateMessageInThread(webhookMessageCreate, threadID)
+				if err != nil {
+					return err
+				}
+				messageIDs = append(messageIDs, threadMsg.ID)
+			}
+			m.Mu.Lock()
+			defer m.Mu.Unlock()
+			m.threadMessageIDs[message.ID] = threadMessage{
+				ThreadID:   threadID,
+				MessageIDs: messageIDs,
+			}
+			return nil
+		},
+		failed: func(err error) {
+			m.logs.Error(client.Logger(), "failed to create thread message: ", err)
+			m.deliveryFailed(client, threadID, "A message from the user could not be delivered", err)
+		},
+	})
+}
+
+func (m *ModMail) dmMessageUpdateListener(event *events.DMMessageUpdate) {
+	m.Mu.Lock()
+	webhookMessage, ok := m.threadMessageIDs[event.Message.ID]
+	webhookClient, err := m.threadWebhook(webhookMessage.ThreadID)
+	m.Mu.Unlock()
+	if !ok || len(webhookMessage.MessageIDs) == 0 {
+		return
 	}
-	threadID := m.DMThreads[event.ChannelID]
-	_, err := m.webhookClient.UpdateMessageInThread(webhookMessageID, webhookMessageUpdate, threadID)
 	if err != nil {
-		event.Client().Logger().Error("failed to update thread message: ", err)
+		m.logs.Error(event.Client().Logger(), "failed to update thread message: ", err)
 		return
 	}
 
+	attachments, notes := m.config.AttachmentFilter.filterAttachments(event.Message.Attachments)
+	parts := splitContent(withAttachmentNotes(event.Message.Content, notes))
+
+	// update the parts which are still needed and delete the others
+	for i, messageID := range webhookMessage.MessageIDs {
+		if i >= len(parts) {
+			if err := webhookClient.DeleteMessageInThread(messageID, webhookMessage.ThreadID); err != nil {
+				m.logs.Error(event.Client().Logger(), "failed to delete thread message: ", err)
+			}
+			continue
+		}
+		content := parts[i]
+		last := i == len(webhookMessage.MessageIDs)-1 || i == len(parts)-1
+		if i == len(webhookMessage.MessageIDs)-1 && len(parts) > len(webhookMessage.MessageIDs) {
+			// edits can't add messages, so the rest is cut off
+			content = common.Truncate(strings.Join(parts[i:], ""), common.Limits.MessageLength)
+		}
+		webhookMessageUpdate := discord.WebhookMessageUpdate{Content: &content}
+		if last {
+			webhookMessageUpdate.Embeds = &event.Message.Embeds
+			webhookMessageUpdate.Files = m.filesFromAttachments(event.Client(), attachments)
+		}
+		if _, err := webhookClient.UpdateMessageInThread(messageID, webhookMessageUpdate, webhookMessage.ThreadID); err != nil {
+			// staff deleted the thread message while the bot couldn't see it
+			if isNotFound(err) {
+				continue
+			}
+			m.logs.Error(event.Client().Logger(), "failed to update thread message: ", err)
+			return
+		}
+	}
+	if len(parts) < len(webhookMessage.MessageIDs) {
+		m.Mu.Lock()
+		webhookMessage.MessageIDs = webhookMessage.MessageIDs[:len(parts)]
+		m.threadMessageIDs[event.Message.ID] = webhookMessage
+		m.Mu.Unlock()
+	}
 }
 
 func (m *ModMail) dmMessageDeleteListener(event *events.DMMessageDelete) {
 	m.Mu.Lock()
-	defer m.Mu.Unlock()
-
-	webhookMessageID, ok := m.threadMessageIDs[event.MessageID]
+	webhookMessage, ok := m.threadMessageIDs[event.MessageID]
+	delete(m.threadMessageIDs, event.MessageID)
+	webhookClient, err := m.threadWebhook(webhookMessage.ThreadID)
+	m.Mu.Unlock()
 	if !ok {
 		return
 	}
-	delete(m.threadMessageIDs, event.Message.ID)
-	if err := m.webhookClient.DeleteMessage(webhookMessageID); err != nil {
-		event.Client().Logger().Error("failed to delete thread message: ", err)
+	if err != nil {
+		m.logs.Error(event.Client().Logger(), "failed to delete thread message: ", err)
 		return
 	}
 
+	for _, messageID := range webhookMessage.MessageIDs {
+		if err := webhookClient.DeleteMessageInThread(messageID, webhookMessage.ThreadID); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			m.logs.Error(event.Client().Logger(), "failed to delete thread message: ", err)
+			return
+		}
+	}
 }
 
 func (m *ModMail) dmUserTypingStartListener(event *events.DMUserTypingStart) {
@@ -172,7 +416,7 @@ func (m *ModMail) dmUserTypingStartListener(event *events.DMUserTypingStart) {
 		return
 	}
 	if err := event.Client().Rest().SendTyping(threadID); err != nil {
-		event.Client().Logger().Error("failed to send thread typing: ", err)
+		m.logs.Error(event.Client().Logger(), "failed to send thread typing: ", err)
 		return
 	}
 
@@ -1,72 +1,164 @@
 package mod_mail
 
 import (
+	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 func (m *ModMail) guildMessageCreateListener(event *events.GuildMessageCreate) {
-	if event.Message.WebhookID != nil {
+	// webhook messages are forwarded user messages or interaction responses like staff notes, bot messages are notices for the staff
+	if event.Message.WebhookID != nil || event.Message.Author.ID == event.Client().ID() {
 		return
 	}
 
 	m.Mu.Lock()
-	defer m.Mu.Unlock()
 	dmID, ok := m.ThreadDMs[event.ChannelID]
+	m.Mu.Unlock()
 	if !ok {
 		return
 	}
-	messageCreate := discord.MessageCreate{
-		Embeds: generateEmbeds(event.Message),
-		Files:  filesFromAttachments(event.Client(), event.Message.Attachments),
+	internal, content := m.internalMessage(event.Message.Content)
+	if internal {
+		return
 	}
-
-	message, err := event.Client().Rest().CreateMessage(dmID, messageCreate)
-	if err != nil {
-		event.Client().Logger().Error("failed to create dm message: ", err)
+	if claim, blocked := m.blockedByClaim(event.ChannelID, event.Message.Author.ID); blocked {
+		m.claimedNotice(event.Client(), event.Message, claim)
 		return
 	}
-	m.dmMessageIDs[event.Message.ID] = message.ID
+	forwardMessage := event.Message
+	forwardMessage.Content = content
+	m.forwardToDM(event.Client(), dmID, forwardMessage, m.AnonymousReplies(event.ChannelID))
+}
+
+// forwardToDM sends the staff message of a ticket thread to the DM of the user. Anonymous messages show the staff
+// instead of the staff member who sent them.
+func (m *ModMail) forwardToDM(client bot.Client, dmID snowflake.ID, message discord.Message, anonymous bool) {
+	groups := generateEmbeds(message, anonymous)
 
+	// the messages already sent, so retries continue with the next part instead of sending duplicates
+	var messageIDs []snowflake.ID
+	m.dmDeliveries.enqueue(dmID, delivery{
+		deliver: func() error {
+			for i := len(messageIDs); i < len(groups); i++ {
+				messageCreate := discord.MessageCreate{Embeds: groups[i]}
+				// attachments go with the last part
+				if i == len(groups)-1 {
+					messageCreate.Files = m.filesFromAttachments(client, message.Attachments)
+				}
+				sent, err := client.Rest().CreateMessage(dmID, messageCreate)
+				if err != nil {
+					return err
+				}
+				messageIDs = append(messageIDs, sent.ID)
+			}
+			m.Mu.Lock()
+			defer m.Mu.Unlock()
+			m.dmMessageIDs[message.ID] = dmMessage{
+				ThreadID:   message.ChannelID,
+				MessageIDs: messageIDs,
+				Anonymous:  anonymous,
+			}
+			m.recordReply(dmID, message.Author.ID)
+			return nil
+		},
+		failed: func(err error) {
+			m.logs.Error(client.Logger(), "failed to create dm message: ", err)
+			m.deliveryFailed(client, message.ChannelID, "Your message could not be delivered to the user", err)
+		},
+	})
 }
 
 func (m *ModMail) guildMessageUpdateListener(event *events.GuildMessageUpdate) {
-	m.Mu.Lock()
-	defer m.Mu.Unlock()
-
-	dmMessageID, ok := m.dmMessageIDs[event.Message.ID]
-	if !ok {
+	// webhook messages are forwarded user messages, their edits come from the DM and must not be sent back
+	if event.Message.WebhookID != nil {
 		return
 	}
-	embeds := generateEmbeds(event.Message)
-	messageUpdate := discord.MessageUpdate{
-		Embeds: &embeds,
-		Files:  filesFromAttachments(event.Client(), event.Message.Attachments),
-	}
+	m.Mu.Lock()
+	dmMessage, ok := m.dmMessageIDs[event.Message.ID]
 	dmChannelID := m.ThreadDMs[event.ChannelID]
-	_, err := event.Client().Rest().UpdateMessage(dmChannelID, dmMessageID, messageUpdate)
-	if err != nil {
-		event.Client().Logger().Error("failed to update dm message: ", err)
+	m.Mu.Unlock()
+	dmMessageIDs := dmMessage.MessageIDs
+	if !ok || len(dmMessageIDs) == 0 {
 		return
 	}
+	internal, content := m.internalMessage(event.Message.Content)
+	if internal {
+		return
+	}
+	forwardMessage := event.Message
+	forwardMessage.Content = content
+	groups := generateEmbeds(forwardMessage, dmMessage.Anonymous)
 
+	// update the parts which are still needed and delete the others
+	for i, dmMessageID := range dmMessageIDs {
+		if i >= len(groups) {
+			if err := event.Client().Rest().DeleteMessage(dmChannelID, dmMessageID); err != nil {
+				m.logs.Error(event.Client().Logger(), "failed to delete dm message: ", err)
+			}
+			continue
+		}
+		embeds := groups[i]
+		if i == len(dmMessageIDs)-1 {
+			embeds = firstEmbeds(groups[i:])
+		}
+		messageUpdate := discord.MessageUpdate{Embeds: &embeds}
+		if i == len(dmMessageIDs)-1 || i == len(groups)-1 {
+			messageUpdate.Files = m.filesFromAttachments(event.Client(), event.Message.Attachments)
+		}
+		if _, err := event.Client().Rest().UpdateMessage(dmChannelID, dmMessageID, messageUpdate); err != nil {
+			m.logs.Error(event.Client().Logger(), "failed to update dm message: ", err)
+			return
+		}
+	}
+	if len(groups) < len(dmMessageIDs) {
+		m.Mu.Lock()
+		dmMessage.MessageIDs = dmMessageIDs[:len(groups)]
+		m.dmMessageIDs[event.Message.ID] = dmMessage
+		m.Mu.Unlock()
+	}
 }
 
 func (m *ModMail) guildMessageDeleteListener(event *events.GuildMessageDelete) {
 	m.Mu.Lock()
-	defer m.Mu.Unlock()
-
-	dmMessageID, ok := m.dmMessageIDs[event.MessageID]
+	dmMessage, ok := m.dmMessageIDs[event.MessageID]
+	delete(m.dmMessageIDs, event.MessageID)
+	dmChannelID := m.ThreadDMs[event.ChannelID]
+	m.forgetThreadMessage(event.ChannelID, event.MessageID)
+	m.Mu.Unlock()
 	if !ok {
 		return
 	}
-	delete(m.threadMessageIDs, event.Message.ID)
-	dmChannelID := m.ThreadDMs[event.ChannelID]
-	if err := event.Client().Rest().DeleteMessage(dmChannelID, dmMessageID); err != nil {
-		event.Client().Logger().Error("failed to delete dm message: ", err)
-		return
+
+	for _, dmMessageID := range dmMessage.MessageIDs {
+		if err := event.Client().Rest().DeleteMessage(dmChannelID, dmMessageID); err != nil {
+			m.logs.Error(event.Client().Logger(), "failed to delete dm message: ", err)
+			return
+		}
 	}
+}
 
+// forgetThreadMessage removes a forwarded user message staff deleted from the thread, so edits and deletions of it in
+// the DM are no longer bridged. Mu must be held.
+func (m *ModMail) forgetThreadMessage(threadID snowflake.ID, messageID snowflake.ID) {
+	for dmMessageID, webhookMessage := range m.threadMessageIDs {
+		if webhookMessage.ThreadID != threadID {
+			continue
+		}
+		for i, threadMessageID := range webhookMessage.MessageIDs {
+			if threadMessageID != messageID {
+				continue
+			}
+			webhookMessage.MessageIDs = append(webhookMessage.MessageIDs[:i:i], webhookMessage.MessageIDs[i+1:]...)
+			if len(webhookMessage.MessageIDs) == 0 {
+				delete(m.threadMessageIDs, dmMessageID)
+			} else {
+				m.threadMessageIDs[dmMessageID] = webhookMessage
+			}
+			return
+		}
+	}
 }
 
 func (m *ModMail) guildMemberTypingStartListener(event *events.GuildMemberTypingStart) {
@@ -78,7 +170,7 @@ func (m *ModMail) guildMemberTypingStartListener(event *events.GuildMemberTyping
 		return
 	}
 	if err := event.Client().Rest().SendTyping(dmChannelID); err != nil {
-		event.Client().Logger().Error("failed to send dm typing: ", err)
+		m.logs.Error(event.Client().Logger(), "failed to send dm typing: ", err)
 		return
 
 	}
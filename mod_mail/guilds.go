@@ -0,0 +1,126 @@
+package mod_mail
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// GuildConfig is the mod mail setup of a single guild.
+type GuildConfig struct {
+	RoleID       snowflake.ID `json:"role_id" yaml:"role_id" toml:"role_id"`
+	ChannelID    snowflake.ID `json:"channel_id" yaml:"channel_id" toml:"channel_id"`
+	WebhookID    snowflake.ID `json:"webhook_id" yaml:"webhook_id" toml:"webhook_id"`
+	WebhookToken string       `json:"webhook_token" yaml:"webhook_token" toml:"webhook_token"`
+	// ThreadSource is the type of ChannelID. Defaults to ThreadSourceChannel.
+	ThreadSource ThreadSource `json:"thread_source,omitempty" yaml:"thread_source,omitempty" toml:"thread_source,omitempty"`
+	// Anonymize shows users under a random pseudonym in their ticket threads instead of their name and avatar.
+	Anonymize bool `json:"anonymize,omitempty" yaml:"anonymize,omitempty" toml:"anonymize,omitempty"`
+	// AnonymousReplies sends the replies of the staff to users as "Staff" instead of their name and avatar. It can be
+	// overridden per reply with /modmail reply.
+	AnonymousReplies bool `json:"anonymous_replies,omitempty" yaml:"anonymous_replies,omitempty" toml:"anonymous_replies,omitempty"`
+}
+
+// ParsedGuilds returns the Guilds by their parsed ID. It fails if a key is no valid guild ID.
+func (c Config) ParsedGuilds() (map[snowflake.ID]GuildConfig, error) {
+	guilds, err := common.ParseIDKeys(c.Guilds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mod mail guild: %w", err)
+	}
+	return guilds, nil
+}
+
+// Guild returns the mod mail setup of the guild.
+func (m *ModMail) Guild(guildID snowflake.ID) (GuildConfig, bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	guild, ok := m.guilds[guildID]
+	return guild, ok
+}
+
+// Guilds returns a copy of the mod mail setups by their guild ID.
+func (m *ModMail) Guilds() map[snowflake.ID]GuildConfig {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	guilds := make(map[snowflake.ID]GuildConfig, len(m.guilds))
+	for guildID, guild := range m.guilds {
+		guilds[guildID] = guild
+	}
+	return guilds
+}
+
+// MigrateLegacy moves the single guild setup of older configs into Guilds under the given guild ID.
+func (c *Config) MigrateLegacy(guildID snowflake.ID) {
+	if c.ChannelID != 0 {
+		if c.Guilds == nil {
+			c.Guilds = map[string]GuildConfig{}
+		}
+		if _, ok := c.Guilds[guildID.String()]; !ok {
+			c.Guilds[guildID.String()] = GuildConfig{
+				RoleID:       c.RoleID,
+				ChannelID:    c.ChannelID,
+				WebhookID:    c.WebhookID,
+				WebhookToken: c.WebhookToken,
+			}
+		}
+		c.RoleID, c.ChannelID, c.WebhookID, c.WebhookToken = 0, 0, 0, ""
+	}
+	for i := range c.Threads {
+		if c.Threads[i].GuildID == 0 {
+			c.Threads[i].GuildID = guildID
+		}
+	}
+}
+
+// threadChannel returns the channel the thread was created in. Mu must be held.
+func (m *ModMail) threadChannel(threadID snowflake.ID) snowflake.ID {
+	if parentID, ok := m.threadParents[threadID]; ok {
+		return parentID
+	}
+	return m.guilds[m.threadGuilds[threadID]].ChannelID
+}
+
+// memberGuilds returns the guilds with mod mail the user is a member of.
+func (m *ModMail) memberGuilds(client bot.Client, userID snowflake.ID) ([]snowflake.ID, error) {
+	guildIDs := make([]snowflake.ID, 0, len(m.guilds))
+	for guildID := range m.guilds {
+		guildIDs = append(guildIDs, guildID)
+	}
+	sort.Slice(guildIDs, func(i, j int) bool {
+		return guildIDs[i] < guildIDs[j]
+	})
+	if len(guildIDs) <= 1 {
+		return guildIDs, nil
+	}
+
+	var memberGuildIDs []snowflake.ID
+	for _, guildID := range guildIDs {
+		if _, err := client.Rest().GetMember(guildID, userID); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		memberGuildIDs = append(memberGuildIDs, guildID)
+	}
+	return memberGuildIDs, nil
+}
+
+// isNotFound reports whether the requested member, message or channel doesn't exist (anymore).
+func isNotFound(err error) bool {
+	var restErr *rest.Error
+	return errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == 404
+}
+
+// guildName returns the name of the guild or its ID if it is not cached.
+func guildName(client bot.Client, guildID snowflake.ID) string {
+	if guild, ok := client.Caches().Guilds().Get(guildID); ok {
+		return guild.Name
+	}
+	return guildID.String()
+}
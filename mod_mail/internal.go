@@ -0,0 +1,31 @@
+package mod_mail
+
+import (
+	"fmt"
+	"strings"
+)
+
+// internalMessage reports whether a staff message in a ticket thread is internal and should not be forwarded to the user.
+// Messages which need to start with the prefix can escape it with a backslash, which is removed before forwarding.
+func (m *ModMail) internalMessage(content string) (internal bool, forwardContent string) {
+	prefix := m.config.InternalPrefix
+	if prefix == "" {
+		return false, content
+	}
+	trimmed := strings.TrimLeft(content, " \t\n")
+	if strings.HasPrefix(trimmed, prefix) {
+		return true, ""
+	}
+	if strings.HasPrefix(trimmed, `\`+prefix) {
+		return false, trimmed[1:]
+	}
+	return false, content
+}
+
+// internalPrefixHint returns a hint about the internal prefix for the first message of a thread.
+func (m *ModMail) internalPrefixHint() string {
+	if m.config.InternalPrefix == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nMessages starting with `%s` are not sent to the user. Use `\\%s` to send a message starting with it.", m.config.InternalPrefix, m.config.InternalPrefix)
+}
@@ -1,8 +1,11 @@
 package mod_mail
 
 import (
+	"errors"
 	"sync"
 
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/eventbus"
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
@@ -10,20 +13,58 @@ import (
 	"github.com/disgoorg/snowflake/v2"
 )
 
-func New(config Config) *ModMail {
+const defaultAttachmentConcurrency = 4
+
+// New creates the mod mail of the config. logs suppresses repeated errors of the listeners.
+func New(config Config, bus *eventbus.Bus, logs *common.LogLimiter) *ModMail {
+	// invalid guild IDs are rejected when loading the config
+	guilds, _ := config.ParsedGuilds()
 	modMail := &ModMail{
-		roleID:           config.RoleID,
-		channelID:        config.ChannelID,
-		webhookClient:    webhook.New(config.WebhookID, config.WebhookToken),
+		config:           config,
+		guilds:           guilds,
+		bus:              bus,
+		logs:             logs,
+		webhookClients:   map[snowflake.ID]webhook.Client{},
+		throttle:         newThrottle(config.Throttle),
+		threadDeliveries: newDeliveryQueue(),
+		dmDeliveries:     newDeliveryQueue(),
 		DMThreads:        map[snowflake.ID]snowflake.ID{},
 		ThreadDMs:        map[snowflake.ID]snowflake.ID{},
-		dmMessageIDs:     map[snowflake.ID]snowflake.ID{},
-		threadMessageIDs: map[snowflake.ID]snowflake.ID{},
+		threadParents:    map[snowflake.ID]snowflake.ID{},
+		threadGuilds:     map[snowflake.ID]snowflake.ID{},
+		claims:           map[snowflake.ID]Claim{},
+		pseudonyms:       map[snowflake.ID]Pseudonym{},
+		blocked:          map[snowflake.ID]struct{}{},
+		pendingMessages:  map[snowflake.ID][]discord.Message{},
+		dmMessageIDs:     map[snowflake.ID]dmMessage{},
+		threadMessageIDs: map[snowflake.ID]threadMessage{},
+		openRecords:      map[snowflake.ID]*TicketRecord{},
+		moving:           map[snowflake.ID]struct{}{},
+	}
+	for _, guild := range guilds {
+		modMail.webhookClients[guild.ChannelID] = webhook.New(guild.WebhookID, guild.WebhookToken)
+	}
+	for _, channelWebhook := range config.Webhooks {
+		modMail.webhookClients[channelWebhook.ChannelID] = webhook.New(channelWebhook.WebhookID, channelWebhook.WebhookToken)
+	}
+	for _, userID := range config.BlockedUserIDs {
+		modMail.blocked[userID] = struct{}{}
 	}
 	for _, thread := range config.Threads {
 		modMail.DMThreads[thread.ChannelID] = thread.ThreadID
 		modMail.ThreadDMs[thread.ThreadID] = thread.ChannelID
+		modMail.threadGuilds[thread.ThreadID] = thread.GuildID
+		if thread.ParentID != 0 {
+			modMail.threadParents[thread.ThreadID] = thread.ParentID
+		}
+		if thread.Claim != nil {
+			modMail.claims[thread.ThreadID] = *thread.Claim
+		}
+		if thread.Pseudonym != nil {
+			modMail.pseudonyms[thread.ThreadID] = *thread.Pseudonym
+		}
 	}
+	modMail.loadHistory(config.History)
 
 	modMail.ListenerAdapter = events.ListenerAdapter{
 		OnDMMessageCreate:   modMail.dmMessageCreateListener,
@@ -44,9 +85,19 @@ var _ bot.EventListener = (*ModMail)(nil)
 
 type ModMail struct {
 	events.ListenerAdapter
-	roleID        snowflake.ID
-	channelID     snowflake.ID
-	webhookClient webhook.Client
+	config Config
+	// guilds are the Guilds of the config by their parsed ID
+	guilds   map[snowflake.ID]GuildConfig
+	bus      *eventbus.Bus
+	logs     *common.LogLimiter
+	throttle *throttle
+
+	// deliveries from the DM to the thread and from the thread to the DM, keyed by DMChannelID
+	threadDeliveries *deliveryQueue
+	dmDeliveries     *deliveryQueue
+
+	// ChannelID -> WebhookClient
+	webhookClients map[snowflake.ID]webhook.Client
 
 	Mu sync.Mutex
 
@@ -54,14 +105,59 @@ type ModMail struct {
 	DMThreads map[snowflake.ID]snowflake.ID
 	// ThreadID -> DMChannelID
 	ThreadDMs map[snowflake.ID]snowflake.ID
+	// ThreadID -> ParentChannelID, only set for threads outside the default channel of their guild
+	threadParents map[snowflake.ID]snowflake.ID
+	// ThreadID -> GuildID
+	threadGuilds map[snowflake.ID]snowflake.ID
+	// ThreadID -> Claim of the staff member handling the ticket
+	claims map[snowflake.ID]Claim
+	// ThreadID -> Pseudonym of the user, only set for tickets of guilds which anonymize users
+	pseudonyms map[snowflake.ID]Pseudonym
+	// UserID -> blocked users whose DMs are ignored
+	blocked map[snowflake.ID]struct{}
+	// UserID -> messages held back until the ticket is opened
+	pendingMessages map[snowflake.ID][]discord.Message
 
-	// DMMessageID -> ThreadMessageID
-	dmMessageIDs map[snowflake.ID]snowflake.ID
-	// ThreadMessageID -> DMMessageID
-	threadMessageIDs map[snowflake.ID]snowflake.ID
+	// ThreadMessageID -> the DM messages it was forwarded as
+	dmMessageIDs map[snowflake.ID]dmMessage
+	// DMMessageID -> the thread messages it was forwarded as
+	threadMessageIDs map[snowflake.ID]threadMessage
+
+	// DMChannelID -> activity of the open ticket
+	openRecords   map[snowflake.ID]*TicketRecord
+	closedRecords []TicketRecord
+	// DMChannelID -> tickets which are being moved to another thread
+	moving map[snowflake.ID]struct{}
+}
+
+type dmMessage struct {
+	// ThreadID is the thread the message was sent in, to forget its DM messages once the ticket is closed.
+	ThreadID snowflake.ID
+	// MessageIDs are the DM messages the thread message was split into, usually one.
+	MessageIDs []snowflake.ID
+	// Anonymous is set if the message was sent without showing the staff member, so edits stay anonymous.
+	Anonymous bool
+}
+
+type threadMessage struct {
+	ThreadID snowflake.ID
+	// MessageIDs are the messages the DM message was split into, usually one.
+	MessageIDs []snowflake.ID
 }
 
-func (m *ModMail) Close() []Thread {
+// WithState returns the config with the threads, webhooks, blocked users and history of state, which is the Config
+// returned by Close. The rest of the config is kept, so saving the state doesn't revert changes made to the config
+// since mod mail started, like a restored backup.
+func (c Config) WithState(state Config) Config {
+	c.Threads = state.Threads
+	c.Webhooks = state.Webhooks
+	c.BlockedUserIDs = state.BlockedUserIDs
+	c.History = state.History
+	return c
+}
+
+// Close returns the Config with the current threads and webhooks to persist them.
+func (m *ModMail) Close() Config {
 	m.Mu.Lock()
 	defer m.Mu.Unlock()
 
@@ -71,60 +167,272 @@ func (m *ModMail) Close() []Thread {
 		threads[i] = Thread{
 			ChannelID: dmID,
 			ThreadID:  threadID,
+			ParentID:  m.threadParents[threadID],
+			GuildID:   m.threadGuilds[threadID],
+		}
+		if claim, ok := m.claims[threadID]; ok {
+			threads[i].Claim = &claim
+		}
+		if pseudonym, ok := m.pseudonyms[threadID]; ok {
+			threads[i].Pseudonym = &pseudonym
 		}
 		i++
 	}
-	return threads
+
+	defaultChannels := map[snowflake.ID]struct{}{}
+	for _, guild := range m.guilds {
+		defaultChannels[guild.ChannelID] = struct{}{}
+	}
+	var webhooks []ChannelWebhook
+	for channelID, webhookClient := range m.webhookClients {
+		if _, ok := defaultChannels[channelID]; ok {
+			continue
+		}
+		webhooks = append(webhooks, ChannelWebhook{
+			ChannelID:    channelID,
+			WebhookID:    webhookClient.ID(),
+			WebhookToken: webhookClient.Token(),
+		})
+	}
+
+	config := m.config
+	config.Threads = threads
+	config.Webhooks = webhooks
+	config.BlockedUserIDs = m.blockedUserIDs()
+	config.History = m.history()
+	return config
 }
 
-func generateEmbeds(message discord.Message) []discord.Embed {
-	embeds := make([]discord.Embed, len(message.Embeds)+1)
-	embeds[0] = discord.Embed{
-		Author: &discord.EmbedAuthor{
-			Name:    message.Author.Tag(),
-			IconURL: message.Author.EffectiveAvatarURL(),
-		},
-		Description: message.Content,
+// messageTooLongNote replaces the rest of edited messages which no longer fit into a single message.
+const messageTooLongNote = "… (message too long, the rest of the edit could not be forwarded)"
+
+// errNoThreadWebhook is returned for threads whose channel has no webhook anymore, e.g. because mod mail was removed
+// from their guild.
+var errNoThreadWebhook = errors.New("no webhook is set up for the channel of the thread")
+
+// threadWebhook returns the webhook client which can post in the given thread. Mu must be held.
+func (m *ModMail) threadWebhook(threadID snowflake.ID) (webhook.Client, error) {
+	if webhookClient, ok := m.webhookClients[m.threadChannel(threadID)]; ok {
+		return webhookClient, nil
+	}
+	guild, ok := m.guilds[m.threadGuilds[threadID]]
+	if !ok {
+		return nil, errNoThreadWebhook
+	}
+	webhookClient, ok := m.webhookClients[guild.ChannelID]
+	if !ok {
+		return nil, errNoThreadWebhook
 	}
+	return webhookClient, nil
+}
+
+// generateEmbeds renders the message as embeds grouped by the messages they have to be sent in to stay within
+// Discord's limits. Long content is split across multiple embeds, so nothing is dropped. Anonymous messages show
+// anonymousStaffName instead of the author.
+func generateEmbeds(message discord.Message, anonymous bool) [][]discord.Embed {
+	chunks := common.SplitLines(message.Content, common.Limits.EmbedDescriptionLength)
+	if len(chunks) == 0 {
+		chunks = []string{""}
+	}
+	embeds := make([]discord.Embed, 0, len(chunks)+len(message.Embeds))
+	for i, chunk := range chunks {
+		embed := discord.Embed{Description: chunk}
+		if i == 0 && anonymous {
+			embed.Author = &discord.EmbedAuthor{Name: anonymousStaffName}
+		} else if i == 0 {
+			embed.Author = &discord.EmbedAuthor{
+				Name:    message.Author.Tag(),
+				IconURL: message.Author.EffectiveAvatarURL(),
+			}
+		}
+		embeds = append(embeds, embed)
+	}
+	embeds = append(embeds, message.Embeds...)
+	return groupEmbeds(embeds)
+}
 
-	for i := range message.Embeds {
-		if len(embeds) == 10 {
-			break
+// groupEmbeds splits the embeds into groups which each fit into a single message.
+func groupEmbeds(embeds []discord.Embed) [][]discord.Embed {
+	var (
+		groups [][]discord.Embed
+		group  []discord.Embed
+		length int
+	)
+	for _, embed := range embeds {
+		embedLength := common.EmbedLength(embed)
+		if len(group) == common.MaxEmbeds || (len(group) > 0 && length+embedLength > common.MaxEmbedsLength) {
+			groups = append(groups, group)
+			group = nil
+			length = 0
 		}
-		embeds[i+1] = message.Embeds[i]
+		group = append(group, embed)
+		length += embedLength
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
 	}
+	return groups
+}
+
+// firstEmbeds returns the first group of the embeds. If there are more groups, a note that the message was too long
+// is added, as edits can't be split across multiple messages.
+func firstEmbeds(groups [][]discord.Embed) []discord.Embed {
+	embeds := groups[0]
+	if len(groups) == 1 {
+		return embeds
+	}
+	note := discord.Embed{Description: messageTooLongNote}
+	var length int
+	for _, embed := range embeds {
+		length += common.EmbedLength(embed)
+	}
+	if len(embeds) < common.MaxEmbeds && length+common.EmbedLength(note) <= common.MaxEmbedsLength {
+		return append(embeds, note)
+	}
+	embeds[len(embeds)-1] = note
 	return embeds
 }
 
-func filesFromAttachments(client bot.Client, attachments []discord.Attachment) []*discord.File {
+// filesFromAttachments downloads the attachments with at most AttachmentConcurrency downloads at once.
+// Attachments which fail to download are skipped. Mu must not be held.
+func (m *ModMail) filesFromAttachments(client bot.Client, attachments []discord.Attachment) []*discord.File {
+	concurrency := m.config.AttachmentConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultAttachmentConcurrency
+	}
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
 	files := make([]*discord.File, len(attachments))
 	for ii := range attachments {
 		wg.Add(1)
 		i := ii
 		go func() {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			rs, err := client.Rest().HTTPClient().Get(attachments[i].URL)
 			if err != nil {
-				client.Logger().Error("failed to get attachment: ", err)
+				m.logs.Errorf(client.Logger(), "failed to get attachment %s: %s", attachments[i].Filename, err)
 				return
 			}
 			files[i] = discord.NewFile(attachments[i].Filename, "", rs.Body)
 		}()
 	}
 	wg.Wait()
-	return files
+
+	downloaded := files[:0]
+	for _, file := range files {
+		if file != nil {
+			downloaded = append(downloaded, file)
+		}
+	}
+	return downloaded
 }
 
 type Config struct {
-	RoleID       snowflake.ID `json:"role_id"`
-	ChannelID    snowflake.ID `json:"channel_id"`
-	WebhookID    snowflake.ID `json:"webhook_id"`
-	WebhookToken string       `json:"webhook_token"`
-	Threads      []Thread     `json:"threads"`
+	// RoleID, ChannelID, WebhookID and WebhookToken are the single guild setup of older configs, see MigrateLegacy.
+	RoleID       snowflake.ID `json:"role_id,omitempty" yaml:"role_id,omitempty" toml:"role_id,omitempty"`
+	ChannelID    snowflake.ID `json:"channel_id,omitempty" yaml:"channel_id,omitempty" toml:"channel_id,omitempty"`
+	WebhookID    snowflake.ID `json:"webhook_id,omitempty" yaml:"webhook_id,omitempty" toml:"webhook_id,omitempty"`
+	WebhookToken string       `json:"webhook_token,omitempty" yaml:"webhook_token,omitempty" toml:"webhook_token,omitempty"`
+
+	// Guilds are the guilds mod mail is available in by their ID, see ParsedGuilds.
+	Guilds   map[string]GuildConfig `json:"guilds" yaml:"guilds" toml:"guilds"`
+	Threads  []Thread               `json:"threads" yaml:"threads" toml:"threads"`
+	Webhooks []ChannelWebhook       `json:"webhooks" yaml:"webhooks" toml:"webhooks"`
+	Throttle ThrottleConfig         `json:"throttle" yaml:"throttle" toml:"throttle"`
+	// BlockedUserIDs are the users whose DMs are ignored.
+	BlockedUserIDs []snowflake.ID `json:"blocked_user_ids,omitempty" yaml:"blocked_user_ids,omitempty" toml:"blocked_user_ids,omitempty"`
+	// AttachmentFilter controls which attachments of users are forwarded into ticket threads.
+	AttachmentFilter AttachmentFilterConfig `json:"attachment_filter" yaml:"attachment_filter" toml:"attachment_filter"`
+	// InstantThreads opens a thread on the first DM instead of asking the user to confirm first.
+	InstantThreads bool `json:"instant_threads" yaml:"instant_threads" toml:"instant_threads"`
+	// InternalPrefix marks staff messages in a ticket thread which are not sent to the user, e.g. "//". Empty disables it.
+	InternalPrefix string `json:"internal_prefix" yaml:"internal_prefix" toml:"internal_prefix"`
+	// AttachmentConcurrency is the amount of attachments downloaded at once when forwarding a message. Defaults to 4.
+	AttachmentConcurrency int `json:"attachment_concurrency" yaml:"attachment_concurrency" toml:"attachment_concurrency"`
+	// OptInTimeoutSeconds is how long the user has to confirm a new ticket. Defaults to 20 seconds.
+	OptInTimeoutSeconds int `json:"opt_in_timeout_seconds" yaml:"opt_in_timeout_seconds" toml:"opt_in_timeout_seconds"`
+	// History is the activity of past and open tickets used by /modmail stats.
+	History []TicketRecord `json:"history,omitempty" yaml:"history,omitempty" toml:"history,omitempty"`
+	// HistoryRetentionDays is how long tickets are kept in the History. Defaults to 90 days.
+	HistoryRetentionDays int `json:"history_retention_days,omitempty" yaml:"history_retention_days,omitempty" toml:"history_retention_days,omitempty"`
 }
 
 type Thread struct {
-	ThreadID  snowflake.ID `json:"thread_id"`
-	ChannelID snowflake.ID `json:"channel_id"`
+	ThreadID  snowflake.ID `json:"thread_id" yaml:"thread_id" toml:"thread_id"`
+	ChannelID snowflake.ID `json:"channel_id" yaml:"channel_id" toml:"channel_id"`
+	ParentID  snowflake.ID `json:"parent_id,omitempty" yaml:"parent_id,omitempty" toml:"parent_id,omitempty"`
+	GuildID   snowflake.ID `json:"guild_id" yaml:"guild_id" toml:"guild_id"`
+	Claim     *Claim       `json:"claim,omitempty" yaml:"claim,omitempty" toml:"claim,omitempty"`
+	Pseudonym *Pseudonym   `json:"pseudonym,omitempty" yaml:"pseudonym,omitempty" toml:"pseudonym,omitempty"`
+}
+
+type ChannelWebhook struct {
+	ChannelID    snowflake.ID `json:"channel_id" yaml:"channel_id" toml:"channel_id"`
+	WebhookID    snowflake.ID `json:"webhook_id" yaml:"webhook_id" toml:"webhook_id"`
+	WebhookToken string       `json:"webhook_token" yaml:"webhook_token" toml:"webhook_token"`
+}
+
+// WebhookClient returns the mod mail webhook client with the given ID.
+func (m *ModMail) WebhookClient(webhookID snowflake.ID) (webhook.Client, bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	for _, webhookClient := range m.webhookClients {
+		if webhookClient.ID() == webhookID {
+			return webhookClient, true
+		}
+	}
+	return nil, false
+}
+
+// IsTicket reports whether the given thread is an open ticket.
+func (m *ModMail) IsTicket(threadID snowflake.ID) bool {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	_, ok := m.ThreadDMs[threadID]
+	return ok
+}
+
+// Webhooks returns all webhooks used by mod mail including the one of the default channel.
+func (m *ModMail) Webhooks() []ChannelWebhook {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	webhooks := make([]ChannelWebhook, 0, len(m.webhookClients))
+	for channelID, webhookClient := range m.webhookClients {
+		webhooks = append(webhooks, ChannelWebhook{
+			ChannelID:    channelID,
+			WebhookID:    webhookClient.ID(),
+			WebhookToken: webhookClient.Token(),
+		})
+	}
+	return webhooks
+}
+
+// Stats are the sizes of the internal mod mail state used for debugging.
+type Stats struct {
+	Tickets          int `json:"tickets"`
+	ThreadLinks      int `json:"thread_links"`
+	MovedThreads     int `json:"moved_threads"`
+	Claims           int `json:"claims"`
+	PendingUsers     int `json:"pending_users"`
+	DMMessageIDs     int `json:"dm_message_ids"`
+	ThreadMessageIDs int `json:"thread_message_ids"`
+	Webhooks         int `json:"webhooks"`
+}
+
+// Stats returns the sizes of the internal mod mail state.
+func (m *ModMail) Stats() Stats {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	return Stats{
+		Tickets:          len(m.DMThreads),
+		ThreadLinks:      len(m.ThreadDMs),
+		MovedThreads:     len(m.threadParents),
+		Claims:           len(m.claims),
+		PendingUsers:     len(m.pendingMessages),
+		DMMessageIDs:     len(m.dmMessageIDs),
+		ThreadMessageIDs: len(m.threadMessageIDs),
+		Webhooks:         len(m.webhookClients),
+	}
 }
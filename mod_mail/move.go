@@ -0,0 +1,103 @@
+package mod_mail
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var (
+	ErrNoTicket     = common.NewUserError("no ticket found for this thread")
+	ErrTicketMoved  = common.NewUserError("this ticket has already been moved to another thread")
+	ErrSameChannel  = common.NewUserError("this ticket is already in that channel")
+	ErrTicketMoving = common.NewUserError("this ticket is already being moved")
+)
+
+// MoveThread moves the ticket of the given thread to another channel. As Discord can't move threads between channels
+// a new thread is created and the DM is linked to it. The old thread stays linked to the DM so already mirrored
+// messages can still be edited and deleted. Mu is not held while talking to Discord, so other tickets aren't blocked.
+func (m *ModMail) MoveThread(client bot.Client, threadID snowflake.ID, channelID snowflake.ID) (snowflake.ID, error) {
+	m.Mu.Lock()
+	dmID, ok := m.ThreadDMs[threadID]
+	if !ok {
+		m.Mu.Unlock()
+		return 0, ErrNoTicket
+	}
+	if m.DMThreads[dmID] != threadID {
+		m.Mu.Unlock()
+		return 0, ErrTicketMoved
+	}
+	if m.threadChannel(threadID) == channelID {
+		m.Mu.Unlock()
+		return 0, ErrSameChannel
+	}
+	if _, ok = m.moving[dmID]; ok {
+		m.Mu.Unlock()
+		return 0, ErrTicketMoving
+	}
+	m.moving[dmID] = struct{}{}
+	webhookClient := m.webhookClients[channelID]
+	guildID := m.threadGuilds[threadID]
+	guild := m.guilds[guildID]
+	prefixHint := m.internalPrefixHint()
+	m.Mu.Unlock()
+
+	defer func() {
+		m.Mu.Lock()
+		delete(m.moving, dmID)
+		m.Mu.Unlock()
+	}()
+
+	oldThread, err := client.Rest().GetChannel(threadID)
+	if err != nil {
+		return 0, err
+	}
+
+	if webhookClient == nil {
+		incomingWebhook, err := client.Rest().CreateWebhook(channelID, discord.WebhookCreate{Name: "Mod Mail"})
+		if err != nil {
+			return 0, err
+		}
+		webhookClient = webhook.New(incomingWebhook.ID(), incomingWebhook.Token)
+		m.Mu.Lock()
+		m.webhookClients[channelID] = webhookClient
+		m.Mu.Unlock()
+	}
+
+	source := ThreadSourceChannel
+	if channelID == guild.ChannelID {
+		source = guild.ThreadSource
+	}
+	newThreadID, err := m.createThread(client, webhookClient, channelID, source, oldThread.Name(), discord.WebhookMessageCreate{
+		Content:         fmt.Sprintf("%s\nTicket moved here from %s%s", discord.RoleMention(guild.RoleID), discord.ChannelMention(threadID), prefixHint),
+		AllowedMentions: common.AllowedMentions(common.MentionsModMail, guild.RoleID),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	// the ticket could have been closed while the thread was created
+	if m.DMThreads[dmID] != threadID {
+		return 0, ErrNoTicket
+	}
+	m.DMThreads[dmID] = newThreadID
+	m.ThreadDMs[newThreadID] = dmID
+	m.threadGuilds[newThreadID] = guildID
+	if channelID != guild.ChannelID {
+		m.threadParents[newThreadID] = channelID
+	}
+	if claim, ok := m.claims[threadID]; ok {
+		m.claims[newThreadID] = claim
+		delete(m.claims, threadID)
+	}
+	if pseudonym, ok := m.pseudonyms[threadID]; ok {
+		m.pseudonyms[newThreadID] = pseudonym
+	}
+	return newThreadID, nil
+}
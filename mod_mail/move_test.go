@@ -0,0 +1,83 @@
+package mod_mail
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/disgo"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// unlockedRecorder answers the requests of a thread move and fails the test if Mu is held during a request.
+type unlockedRecorder struct {
+	t *testing.T
+	m *ModMail
+}
+
+func (r *unlockedRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !r.m.Mu.TryLock() {
+		r.t.Errorf("Mu is held during %s %s", req.Method, req.URL.Path)
+	} else {
+		r.m.Mu.Unlock()
+	}
+	body := `{"id":"1","channel_id":"1"}`
+	switch {
+	case req.Method == http.MethodGet:
+		body = `{"id":"817327181659111459","type":11,"name":"ticket","guild_id":"817327181659111454","parent_id":"1"}`
+	case strings.HasSuffix(req.URL.Path, "/threads"):
+		body = `{"id":"817327181659111463","type":11,"name":"ticket","guild_id":"817327181659111454","parent_id":"817327181659111461"}`
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestMoveThread(t *testing.T) {
+	const (
+		guildID     snowflake.ID = 817327181659111454
+		threadID    snowflake.ID = 817327181659111459
+		dmID        snowflake.ID = 817327181659111460
+		channelID   snowflake.ID = 817327181659111461
+		newThreadID snowflake.ID = 817327181659111463
+	)
+	m := New(Config{
+		Guilds:  map[string]GuildConfig{guildID.String(): {ChannelID: 1}},
+		Threads: []Thread{{ThreadID: threadID, ChannelID: dmID, GuildID: guildID}},
+	}, nil, nil)
+	httpClient := &http.Client{Transport: &unlockedRecorder{t: t, m: m}}
+	token := base64.StdEncoding.EncodeToString([]byte(guildID.String())) + ".token.test"
+	client, err := disgo.New(token, bot.WithRestClientConfigOpts(rest.WithHTTPClient(httpClient)))
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	m.webhookClients[channelID] = webhook.New(817327181659111462, "token", webhook.WithRestClientConfigOpts(rest.WithHTTPClient(httpClient)))
+
+	movedID, err := m.MoveThread(client, threadID, channelID)
+	if err != nil {
+		t.Fatalf("MoveThread() error = %v", err)
+	}
+	if movedID != newThreadID {
+		t.Errorf("MoveThread() = %s, want %s", movedID, newThreadID)
+	}
+	if got := m.DMThreads[dmID]; got != newThreadID {
+		t.Errorf("thread of the DM = %s, want %s", got, newThreadID)
+	}
+	if got := m.threadChannel(newThreadID); got != channelID {
+		t.Errorf("channel of the new thread = %s, want %s", got, channelID)
+	}
+	if _, err = m.MoveThread(client, threadID, 1); err != ErrTicketMoved {
+		t.Errorf("moving the old thread again error = %v, want %v", err, ErrTicketMoved)
+	}
+	if len(m.moving) != 0 {
+		t.Errorf("tickets still moving = %d, want 0", len(m.moving))
+	}
+}
@@ -0,0 +1,49 @@
+package mod_mail
+
+import (
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// anonymousStaffName is shown to users instead of the staff member who sent an anonymous reply.
+const anonymousStaffName = "Staff"
+
+// AnonymousReplies reports whether replies in the thread are sent to the user anonymously by default, see
+// GuildConfig.AnonymousReplies.
+func (m *ModMail) AnonymousReplies(threadID snowflake.ID) bool {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	return m.guilds[m.threadGuilds[threadID]].AnonymousReplies
+}
+
+// CheckReply returns an error if the staff member can't reply in the thread, because it is no ticket or another staff
+// member claimed it exclusively.
+func (m *ModMail) CheckReply(threadID snowflake.ID, staffID snowflake.ID) error {
+	if !m.IsTicket(threadID) {
+		return ErrNoTicket
+	}
+	if claim, blocked := m.blockedByClaim(threadID, staffID); blocked {
+		return common.NewUserErrorf("this ticket is claimed by %s, your reply was not sent to the user", discord.UserMention(claim.UserID))
+	}
+	return nil
+}
+
+// Reply sends the reply of the staff member to the user like a message in the thread. messageID is the copy of the
+// reply in the thread which shows who sent it, deleting it deletes the reply for the user as well.
+func (m *ModMail) Reply(client bot.Client, threadID snowflake.ID, messageID snowflake.ID, staff discord.User, content string, anonymous bool) error {
+	m.Mu.Lock()
+	dmID, ok := m.ThreadDMs[threadID]
+	m.Mu.Unlock()
+	if !ok {
+		return ErrNoTicket
+	}
+	m.forwardToDM(client, dmID, discord.Message{
+		ID:        messageID,
+		ChannelID: threadID,
+		Author:    staff,
+		Content:   content,
+	}, anonymous)
+	return nil
+}
@@ -0,0 +1,145 @@
+package mod_mail
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+var (
+	ErrNotConfigured = common.NewUserError("mod mail is not configured for this guild")
+	// errTestSkipped marks test steps which were not run because a step they depend on failed.
+	errTestSkipped = errors.New("skipped")
+)
+
+// TestStep is the result of a single step of Test.
+type TestStep struct {
+	Name string
+	// Err is nil if the step succeeded.
+	Err error
+}
+
+// Skipped reports whether the step was not run because a step it depends on failed.
+func (s TestStep) Skipped() bool {
+	return errors.Is(s.Err, errTestSkipped)
+}
+
+// Test walks through opening a ticket in the guild without a real user. It creates a test thread the same way a ticket
+// is opened, posts in it like the user and the staff would and checks the bot can open a DM with the staff member
+// running the test without sending anything there. The test thread is not registered as a ticket and deleted again.
+func (m *ModMail) Test(client bot.Client, guildID snowflake.ID, staff discord.User) ([]TestStep, error) {
+	guild, ok := m.guilds[guildID]
+	if !ok {
+		return nil, ErrNotConfigured
+	}
+
+	steps := []TestStep{
+		{Name: "Intents", Err: testIntents(client)},
+		{Name: "Staff role", Err: testRole(client, guildID, guild.RoleID)},
+	}
+	steps = append(steps, m.testThread(client, guild, staff)...)
+	// creating the DM channel doesn't notify the user, replies of the staff are sent there
+	_, err := client.Rest().CreateDMChannel(staff.ID)
+	return append(steps, TestStep{Name: "Open DM", Err: err}), nil
+}
+
+// testThread creates a test thread like a ticket is opened, posts in it and deletes it again.
+func (m *ModMail) testThread(client bot.Client, guild GuildConfig, staff discord.User) []TestStep {
+	var steps []TestStep
+	step := func(name string, err error) bool {
+		steps = append(steps, TestStep{Name: name, Err: err})
+		return err == nil
+	}
+	skip := func(names ...string) []TestStep {
+		for _, name := range names {
+			steps = append(steps, TestStep{Name: name, Err: errTestSkipped})
+		}
+		return steps
+	}
+
+	if !step("Channel", guild.ValidateChannel(client)) {
+		return skip("Webhook", "Create thread", "Post as user", "Post notice", "Clean up")
+	}
+	m.Mu.Lock()
+	webhookClient := m.webhookClients[guild.ChannelID]
+	m.Mu.Unlock()
+	if !step("Webhook", testWebhook(webhookClient, guild.ChannelID)) {
+		return skip("Create thread", "Post as user", "Post notice", "Clean up")
+	}
+
+	threadID, err := m.createThread(client, webhookClient, guild.ChannelID, guild.ThreadSource, "mod mail test", discord.WebhookMessageCreate{
+		Content:         fmt.Sprintf("Test ticket started by %s, it is deleted after the test.", staff.Tag()),
+		AllowedMentions: &discord.AllowedMentions{},
+	})
+	if !step("Create thread", err) {
+		return skip("Post as user", "Post notice", "Clean up")
+	}
+	_, err = webhookClient.CreateMessageInThread(discord.WebhookMessageCreate{
+		Username:        staff.Username,
+		AvatarURL:       staff.EffectiveAvatarURL(),
+		Content:         "This is how messages of users show up in tickets.",
+		AllowedMentions: &discord.AllowedMentions{},
+	}, threadID)
+	step("Post as user", err)
+	_, err = client.Rest().CreateMessage(threadID, discord.MessageCreate{
+		Content:         "This is how notices of the bot show up in tickets.",
+		AllowedMentions: &discord.AllowedMentions{},
+	})
+	step("Post notice", err)
+	step("Clean up", client.Rest().DeleteChannel(threadID))
+	return steps
+}
+
+// testIntents checks the bot receives the messages mod mail listens to.
+func testIntents(client bot.Client) error {
+	if !client.HasGateway() {
+		return errors.New("the bot is not connected to the gateway")
+	}
+	intents := client.Gateway().Intents()
+	for _, intent := range []struct {
+		Intent gateway.Intents
+		Name   string
+	}{
+		{gateway.IntentGuildMessages, "Guild Messages"},
+		{gateway.IntentDirectMessages, "Direct Messages"},
+	} {
+		if !intents.Has(intent.Intent) {
+			return fmt.Errorf("the %s intent is missing", intent.Name)
+		}
+	}
+	return nil
+}
+
+func testRole(client bot.Client, guildID snowflake.ID, roleID snowflake.ID) error {
+	roles, err := client.Rest().GetRoles(guildID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return nil
+		}
+	}
+	return fmt.Errorf("role %s does not exist", roleID)
+}
+
+// testWebhook checks the webhook exists and posts in the channel.
+func testWebhook(webhookClient webhook.Client, channelID snowflake.ID) error {
+	if webhookClient == nil {
+		return errors.New("no webhook is set up for the channel")
+	}
+	webhook, err := webhookClient.GetWebhook()
+	if err != nil {
+		return err
+	}
+	if webhook.ChannelID != channelID {
+		return fmt.Errorf("webhook %s belongs to channel %s instead of %s", webhook.ID(), webhook.ChannelID, channelID)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package mod_mail
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest/route"
+	"github.com/disgoorg/disgo/webhook"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// ThreadSource is the kind of channel ticket threads are created in.
+type ThreadSource string
+
+const (
+	// ThreadSourceChannel creates standalone public threads in a text channel.
+	ThreadSourceChannel ThreadSource = "channel"
+	// ThreadSourceForum creates a post in a forum channel.
+	ThreadSourceForum ThreadSource = "forum"
+)
+
+// channelTypeGuildForum is not known to disgo yet, which also means forum channels can't be fetched with
+// rest.Channels.GetChannel.
+const channelTypeGuildForum discord.ChannelType = 15
+
+func (s ThreadSource) channelType() discord.ChannelType {
+	if s == ThreadSourceForum {
+		return channelTypeGuildForum
+	}
+	return discord.ChannelTypeGuildText
+}
+
+// ValidateChannel checks that the channel exists and matches the ThreadSource.
+func (c GuildConfig) ValidateChannel(client bot.Client) error {
+	switch c.ThreadSource {
+	case "", ThreadSourceChannel, ThreadSourceForum:
+	default:
+		return fmt.Errorf("unknown thread source %q, must be %q or %q", c.ThreadSource, ThreadSourceChannel, ThreadSourceForum)
+	}
+	channelType, err := getChannelType(client, c.ChannelID)
+	if err != nil {
+		return err
+	}
+	if expected := c.ThreadSource.channelType(); channelType != expected {
+		return fmt.Errorf("channel %s has type %d but thread source %q needs type %d", c.ChannelID, channelType, c.ThreadSource, expected)
+	}
+	return nil
+}
+
+func getChannelType(client bot.Client, channelID snowflake.ID) (discord.ChannelType, error) {
+	compiledRoute, err := route.GetChannel.Compile(nil, channelID)
+	if err != nil {
+		return 0, err
+	}
+	var channel struct {
+		Type discord.ChannelType `json:"type"`
+	}
+	if err = client.Rest().Do(compiledRoute, nil, &channel); err != nil {
+		return 0, err
+	}
+	return channel.Type, nil
+}
+
+// createThread creates a ticket thread in the channel and posts the first message into it. Forum posts can't exist
+// without a first message, so for them the message is sent along and a failure fails the whole thread.
+func (m *ModMail) createThread(client bot.Client, webhookClient webhook.Client, channelID snowflake.ID, source ThreadSource, name string, messageCreate discord.WebhookMessageCreate) (snowflake.ID, error) {
+	if source == ThreadSourceForum {
+		messageCreate.ThreadName = name
+		message, err := webhookClient.CreateMessage(messageCreate)
+		if err != nil {
+			return 0, err
+		}
+		return message.ChannelID, nil
+	}
+
+	thread, err := client.Rest().CreateThread(channelID, discord.GuildPublicThreadCreate{
+		Name:                name,
+		AutoArchiveDuration: discord.AutoArchiveDuration1h,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if _, err = webhookClient.CreateMessageInThread(messageCreate, thread.ID()); err != nil {
+		m.logs.Error(client.Logger(), "failed to create new thread message: ", err)
+	}
+	return thread.ID(), nil
+}
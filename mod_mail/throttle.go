@@ -0,0 +1,77 @@
+package mod_mail
+
+import (
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+type ThrottleConfig struct {
+	// GlobalBurst is the amount of threads which can be created at once. 0 disables the global limit.
+	GlobalBurst int `json:"global_burst" yaml:"global_burst" toml:"global_burst"`
+	// GlobalRefillSeconds is the amount of seconds it takes to allow one more thread to be created.
+	GlobalRefillSeconds int `json:"global_refill_seconds" yaml:"global_refill_seconds" toml:"global_refill_seconds"`
+	// UserLimit is the amount of threads a single user can open within UserWindowSeconds. 0 disables the user limit.
+	UserLimit         int `json:"user_limit" yaml:"user_limit" toml:"user_limit"`
+	UserWindowSeconds int `json:"user_window_seconds" yaml:"user_window_seconds" toml:"user_window_seconds"`
+}
+
+func newThrottle(config ThrottleConfig) *throttle {
+	return &throttle{
+		config:      config,
+		tokens:      float64(config.GlobalBurst),
+		lastRefill:  time.Now(),
+		userCreates: map[snowflake.ID][]time.Time{},
+	}
+}
+
+type throttle struct {
+	config ThrottleConfig
+
+	mu          sync.Mutex
+	tokens      float64
+	lastRefill  time.Time
+	userCreates map[snowflake.ID][]time.Time
+}
+
+// allow reports whether the user may create a new thread and records the creation if so.
+// globallyThrottled is true if the global limit was hit.
+func (t *throttle) allow(userID snowflake.ID) (allowed bool, globallyThrottled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.config.UserLimit > 0 {
+		window := time.Duration(t.config.UserWindowSeconds) * time.Second
+		var creates []time.Time
+		for _, createdAt := range t.userCreates[userID] {
+			if now.Sub(createdAt) < window {
+				creates = append(creates, createdAt)
+			}
+		}
+		t.userCreates[userID] = creates
+		if len(creates) >= t.config.UserLimit {
+			return false, false
+		}
+	}
+
+	if t.config.GlobalBurst > 0 {
+		if t.config.GlobalRefillSeconds > 0 {
+			t.tokens += now.Sub(t.lastRefill).Seconds() / float64(t.config.GlobalRefillSeconds)
+			if t.tokens > float64(t.config.GlobalBurst) {
+				t.tokens = float64(t.config.GlobalBurst)
+			}
+		}
+		t.lastRefill = now
+		if t.tokens < 1 {
+			return false, true
+		}
+		t.tokens--
+	}
+
+	if t.config.UserLimit > 0 {
+		t.userCreates[userID] = append(t.userCreates[userID], now)
+	}
+	return true, false
+}
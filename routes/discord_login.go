@@ -5,10 +5,11 @@ import (
 	"embed"
 	"html/template"
 	"net/http"
-	"strings"
 
 	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/eventbus"
 	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
 	"golang.org/x/exp/slices"
 )
 
@@ -17,7 +18,7 @@ var templateFS embed.FS
 
 func HandleLogin(b *butler.Butler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, b.OAuth2.GenerateAuthorizationURL(b.Config.BaseURL+"/github", discord.PermissionsNone, 0, false, discord.OAuth2ScopeGuildsMembersRead, discord.OAuth2ScopeConnections), http.StatusTemporaryRedirect)
+		http.Redirect(w, r, b.OAuth2.GenerateAuthorizationURL(b.BaseURL()+"/github", discord.PermissionsNone, 0, false, discord.OAuth2ScopeGuildsMembersRead, discord.OAuth2ScopeConnections), http.StatusTemporaryRedirect)
 	}
 }
 
@@ -30,7 +31,7 @@ func HandleGithub(b *butler.Butler) http.HandlerFunc {
 			state = query.Get("state")
 		)
 		if code == "" || state == "" {
-			http.Redirect(w, r, b.Config.BaseURL+"/github/login", http.StatusTemporaryRedirect)
+			http.Redirect(w, r, b.BaseURL()+"/github/login", http.StatusTemporaryRedirect)
 			return
 		}
 
@@ -45,7 +46,7 @@ func HandleGithub(b *butler.Butler) http.HandlerFunc {
 			return
 		}
 
-		member, err := b.OAuth2.GetMember(session, b.Config.GuildID)
+		member, err := b.OAuth2.GetMember(session, b.GuildID())
 		if err != nil {
 			httpError(w, err)
 			return
@@ -68,27 +69,29 @@ func HandleGithub(b *butler.Butler) http.HandlerFunc {
 		}
 
 		var (
-			roleIDs = member.RoleIDs
-			repos   []string
+			roleIDs      = member.RoleIDs
+			addedRoleIDs []snowflake.ID
+			repos        []string
 		)
-		for repo, roleID := range b.Config.ContributorRepos {
-			values := strings.SplitN(repo, "/", 2)
-			contributors, _, err := b.GitHubClient.Repositories.ListContributors(context.TODO(), values[0], values[1], nil)
+		for repo, roleID := range b.ContributorRepos() {
+			contributors, err := b.RepoContributors(context.TODO(), repo)
 			if err != nil {
 				httpError(w, err)
 				return
 			}
-			for _, contributor := range contributors {
-				if contributor.GetLogin() == conn.Name {
-					if !slices.Contains(roleIDs, roleID) {
-						roleIDs = append(roleIDs, roleID)
-					}
-					repos = append(repos, repo)
-					break
+			if slices.Contains(contributors, conn.Name) {
+				if !slices.Contains(roleIDs, roleID) {
+					roleIDs = append(roleIDs, roleID)
+					addedRoleIDs = append(addedRoleIDs, roleID)
 				}
+				repos = append(repos, repo)
 			}
 
 		}
+		if err = b.DB.SetGithubAccount(member.User.ID, conn.Name, repos); err != nil {
+			b.Logger.Errorf("Failed to save github account of %s: %s", member.User.ID, err)
+		}
+
 		if len(roleIDs) == 0 {
 			if err = t.ExecuteTemplate(w, "error.html", map[string]any{
 				"Error": "You don't seem to be a contributor of any DisGo repositories",
@@ -98,12 +101,18 @@ func HandleGithub(b *butler.Butler) http.HandlerFunc {
 			return
 		}
 
-		if _, err = b.Client.Rest().UpdateMember(b.Config.GuildID, member.User.ID, discord.MemberUpdate{
+		if _, err = b.Client.Rest().UpdateMember(b.GuildID(), member.User.ID, discord.MemberUpdate{
 			Roles: &roleIDs,
 		}); err != nil {
 			httpError(w, err)
 			return
 		}
+		for _, roleID := range addedRoleIDs {
+			b.Events.Publish(eventbus.ContributorRoleAdded{
+				UserID: member.User.ID,
+				RoleID: roleID,
+			})
+		}
 
 		if err = t.ExecuteTemplate(w, "response.html", map[string]any{
 			"Repos": repos,
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/disgoorg/disgo-butler/butler"
+	"github.com/disgoorg/disgo-butler/common"
+	"github.com/disgoorg/disgo-butler/db"
+	"github.com/disgoorg/disgo-butler/eventbus"
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/webhook"
 	"github.com/google/go-github/v44/github"
 )
 
@@ -26,7 +30,11 @@ var (
 
 func HandleGithubWebhook(b *butler.Butler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		payload, err := github.ValidatePayload(r, []byte(b.Config.GithubWebhookSecret))
+		var secret string
+		b.ReadConfig(func(cfg butler.Config) {
+			secret = cfg.GithubWebhookSecret
+		})
+		payload, err := github.ValidatePayload(r, []byte(secret))
 		if err != nil {
 			b.Logger.Errorf("Failed to validate payload: %s", err)
 			w.WriteHeader(http.StatusBadRequest)
@@ -51,23 +59,35 @@ func HandleGithubWebhook(b *butler.Butler) http.HandlerFunc {
 }
 
 func processReleaseEvent(b *butler.Butler, e *github.ReleaseEvent) error {
+	// any change to the releases makes the cached ones like the latest release outdated
+	b.GithubCache.Invalidate(e.GetRepo().GetFullName())
 	if e.GetAction() != "published" {
 		return nil
 	}
 
 	org, repo, fullName := e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetRepo().GetFullName()
 
-	cfg, ok := b.Config.GithubReleases[fullName]
+	cfg, ok := b.ReleaseConfig(fullName)
 	if !ok {
 		return errors.New("no config found for this repo")
 	}
 
-	webhookClient, ok := b.Webhooks[fullName]
-	if !ok {
-		webhookClient = webhook.New(cfg.WebhookID, cfg.WebhookToken)
-		b.Webhooks[fullName] = webhookClient
+	if cfg.Broken != "" && !cfg.Digest.Enabled() {
+		return fmt.Errorf("announcements of %s are paused as its webhook is broken: %s", fullName, cfg.Broken)
+	}
+
+	if cfg.Digest.Enabled() {
+		return b.DB.AddPendingRelease(db.PendingRelease{
+			Repo:        fullName,
+			TagName:     e.GetRelease().GetTagName(),
+			URL:         e.GetRelease().GetHTMLURL(),
+			Author:      e.GetRelease().GetAuthor().GetLogin(),
+			PublishedAt: e.GetRelease().GetPublishedAt().Time,
+		})
 	}
 
+	webhookClient := b.ReleaseWebhooks.Client(fullName, cfg)
+
 	releases, _, err := b.GitHubClient.Repositories.ListReleases(context.TODO(), org, repo, &github.ListOptions{PerPage: 2})
 	if err != nil {
 		return err
@@ -85,16 +105,9 @@ func processReleaseEvent(b *butler.Butler, e *github.ReleaseEvent) error {
 		return err
 	}
 
-	message := parseMarkdown(e.GetRelease().GetBody())
-	if len(message) > 1024 {
-		message = substr(message, 0, 1024)
-		if index := strings.LastIndex(message, "\n"); index != -1 {
-			message = message[:index]
-		}
-		message += "\n…"
-	}
+	message := common.TruncateLink(parseMarkdown(e.GetRelease().GetBody()), common.Limits.EmbedFieldLength, e.GetRelease().GetHTMLURL())
 	message += "\n\n__**Commits:**__\n"
-out:
+	var commits string
 	for _, commit := range comparison.Commits {
 		commitLines := strings.Split(commit.GetCommit().GetMessage(), "\n")
 		for i, commitLine := range commitLines {
@@ -102,38 +115,101 @@ out:
 			if i == 0 {
 				shortId = substr(commit.GetSHA(), 0, 7)
 			}
-			line := fmt.Sprintf("[`%s`](%s) %s\n", shortId, commit.GetHTMLURL(), commitLine)
-			if len(message)+len(line) > 4068 {
-				message += "…"
-				break out
-			}
-			message += line
+			commits += fmt.Sprintf("[`%s`](%s) %s\n", shortId, commit.GetHTMLURL(), commitLine)
 		}
 	}
+	// the commits get the space left by the release notes
+	if remaining := common.Limits.EmbedDescriptionLength - utf8.RuneCountInString(message); remaining > 0 {
+		message += common.Truncate(commits, remaining)
+	}
 
-	msg, err := webhookClient.CreateMessage(discord.NewWebhookMessageCreateBuilder().
-		SetContent(discord.RoleMention(cfg.PingRole)).
-		SetEmbeds(discord.NewEmbedBuilder().
-			SetAuthor(
-				fmt.Sprintf("%s version %s has been released", repo, e.Release.GetTagName()),
-				e.GetRelease().GetHTMLURL(),
-				e.GetRepo().GetOwner().GetAvatarURL(),
-			).
-			SetDescription(message).
-			SetColor(0x5865f2).
-			SetFooter("Release by "+e.GetRelease().GetAuthor().GetLogin(), e.GetRelease().GetAuthor().GetAvatarURL()).
-			SetTimestamp(e.GetRelease().GetCreatedAt().Time).
-			Build(),
+	embed := discord.NewEmbedBuilder().
+		SetAuthor(
+			fmt.Sprintf("%s version %s has been released", repo, e.Release.GetTagName()),
+			e.GetRelease().GetHTMLURL(),
+			e.GetRepo().GetOwner().GetAvatarURL(),
 		).
+		SetDescription(common.Truncate(message, common.Limits.EmbedDescriptionLength)).
+		SetColor(0x5865f2).
+		SetFooter("Release by "+e.GetRelease().GetAuthor().GetLogin(), e.GetRelease().GetAuthor().GetAvatarURL()).
+		SetTimestamp(e.GetRelease().GetCreatedAt().Time)
+	if assets := formatReleaseAssets(e.GetRelease().Assets, cfg.Assets); assets != "" {
+		embed.AddField("Assets", assets, false)
+	}
+
+	msg, err := b.DeliverRelease(fullName, webhookClient, cfg.ThreadID, discord.NewWebhookMessageCreateBuilder().
+		SetContent(discord.RoleMention(cfg.PingRole)).
+		SetAllowedMentions(common.AllowedMentions(common.MentionsReleases, cfg.PingRole)).
+		SetEmbeds(embed.Build()).
+		SetUsername(cfg.Username).
+		SetAvatarURL(cfg.AvatarURL).
 		Build(),
 	)
 	if err != nil {
 		return err
 	}
+	b.Events.Publish(eventbus.ReleaseAnnounced{
+		Repo:      fullName,
+		TagName:   e.GetRelease().GetTagName(),
+		URL:       e.GetRelease().GetHTMLURL(),
+		ChannelID: msg.ChannelID,
+		MessageID: msg.ID,
+	})
+	if cfg.ThreadID != 0 {
+		// messages in threads can't be crossposted and threads can't be created in threads
+		return nil
+	}
 	_, err = b.Client.Rest().CrosspostMessage(msg.ChannelID, msg.ID)
+	if cfg.DiscussionThread {
+		// channels may not allow threads, which should not fail the announcement
+		if _, threadErr := b.Client.Rest().CreateThreadWithMessage(msg.ChannelID, msg.ID, discord.ThreadCreateWithMessage{
+			Name: substr(e.GetRelease().GetTagName(), 0, 100),
+		}); threadErr != nil {
+			b.Logger.Warnf("Failed to create discussion thread for %s %s: %s", fullName, e.GetRelease().GetTagName(), threadErr)
+		}
+	}
 	return err
 }
 
+// formatReleaseAssets lists the assets as links with their size. It returns an empty string if there are no assets or
+// the list is disabled.
+func formatReleaseAssets(assets []*github.ReleaseAsset, cfg butler.ReleaseAssetsConfig) string {
+	if cfg.Limit <= 0 || len(assets) == 0 {
+		return ""
+	}
+	assets = append([]*github.ReleaseAsset(nil), assets...)
+	switch cfg.Order {
+	case "name":
+		sort.SliceStable(assets, func(i, j int) bool {
+			return assets[i].GetName() < assets[j].GetName()
+		})
+	case "size":
+		sort.SliceStable(assets, func(i, j int) bool {
+			return assets[i].GetSize() > assets[j].GetSize()
+		})
+	case "downloads":
+		sort.SliceStable(assets, func(i, j int) bool {
+			return assets[i].GetDownloadCount() > assets[j].GetDownloadCount()
+		})
+	}
+
+	var message string
+	for i, asset := range assets {
+		more := fmt.Sprintf("…and %d more", len(assets)-i)
+		if i == cfg.Limit {
+			message += more
+			break
+		}
+		line := fmt.Sprintf("[`%s`](%s) %s\n", asset.GetName(), asset.GetBrowserDownloadURL(), common.FormatBytes(asset.GetSize()))
+		if len(message)+len(line)+len(more) > common.Limits.EmbedFieldLength {
+			message += more
+			break
+		}
+		message += line
+	}
+	return message
+}
+
 func substr(input string, start int, length int) string {
 	asRunes := []rune(input)
 
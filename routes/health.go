@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/disgoorg/disgo-butler/butler"
+)
+
+type healthResponse struct {
+	Status   string           `json:"status"`
+	Discord  *componentHealth `json:"discord,omitempty"`
+	Database *componentHealth `json:"database,omitempty"`
+}
+
+type componentHealth struct {
+	DegradedSince time.Time `json:"degraded_since"`
+	Error         string    `json:"error"`
+}
+
+func HandleHealth(b *butler.Butler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := b.Health.Status()
+		rs := healthResponse{Status: "ok"}
+		if status.DiscordDegraded {
+			rs.Discord = &componentHealth{
+				DegradedSince: status.DiscordDegradedSince,
+			}
+			if status.DiscordErr != nil {
+				rs.Discord.Error = status.DiscordErr.Error()
+			}
+		}
+		if status.DatabaseDegraded {
+			rs.Database = &componentHealth{
+				DegradedSince: status.DatabaseDegradedSince,
+			}
+			if status.DatabaseErr != nil {
+				rs.Database.Error = status.DatabaseErr.Error()
+			}
+		}
+		if status.Degraded() {
+			rs.Status = "degraded"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if status.Degraded() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(rs); err != nil {
+			b.Logger.Errorf("Failed to write health response: %s", err)
+		}
+	}
+}